@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+)
+
+// AdaptiveCache is a cache whose eviction policy can be switched at runtime
+// without losing its entries
+type AdaptiveCache[K comparable, V any] struct {
+	mu       sync.RWMutex // held exclusively while switching policies
+	capacity int
+	policy   string
+	inner    migratable[K, V]
+}
+
+// migratable is a cache whose entries can be copied into another one. All
+// the policies created by New implement it.
+type migratable[K comparable, V any] interface {
+	Cache[K, V]
+	ToMap() map[K]V
+	FromMap(m map[K]V) error
+}
+
+// NewAdaptiveCache creates a switchable cache starting with the named
+// policy, see New for the policies and options
+func NewAdaptiveCache[K comparable, V any](policy string, capacity int, opts ...Option) (*AdaptiveCache[K, V], error) {
+	c := &AdaptiveCache[K, V]{capacity: capacity}
+	if err := c.SwitchPolicy(policy, opts...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SwitchPolicy moves all entries into a new cache of the named policy and
+// the same capacity, then swaps it in. The relative order of the moved
+// entries, and thus which ones the new policy evicts first, is unspecified.
+// Other calls wait for the switch to finish. On error the current policy is
+// kept. The janitor of the cache left behind, if it runs one, is stopped.
+func (c *AdaptiveCache[K, V]) SwitchPolicy(policy string, opts ...Option) error {
+	next, err := New[K, V](policy, c.capacity, opts...)
+	if err != nil {
+		return err
+	}
+	inner, ok := next.(migratable[K, V])
+	if !ok {
+		return fmt.Errorf("%w: policy %q can't be migrated", ErrUnknownPolicy, policy)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.inner != nil {
+		if err := inner.FromMap(c.inner.ToMap()); err != nil {
+			if j, ok := inner.(janitorStopper); ok {
+				j.StopJanitor()
+			}
+			return err
+		}
+		if j, ok := c.inner.(janitorStopper); ok {
+			j.StopJanitor()
+		}
+	}
+	c.inner = inner
+	c.policy = policy
+	return nil
+}
+
+// Policy returns the name of the current policy
+func (c *AdaptiveCache[K, V]) Policy() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.policy
+}
+
+// Get returns the value stored for key
+func (c *AdaptiveCache[K, V]) Get(key K) (V, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.inner.Get(key)
+}
+
+// Set stores value for key
+func (c *AdaptiveCache[K, V]) Set(key K, value V) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.inner.Set(key, value)
+}
+
+// Delete removes key from the cache
+func (c *AdaptiveCache[K, V]) Delete(key K) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.inner.Delete(key)
+}
+
+// Clear removes all entries from the cache
+func (c *AdaptiveCache[K, V]) Clear() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	c.inner.Clear()
+}
@@ -0,0 +1,140 @@
+// Package bench replays access traces against the cache policies and
+// compares their hit rates.
+package bench
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"caching-labwork/cache"
+)
+
+// Stats holds the outcome of replaying a trace against a cache
+type Stats struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+}
+
+// HitRate returns the fraction of accesses that were hits
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// Result is one row of a policy comparison
+type Result struct {
+	Policy string `json:"policy"`
+	Stats
+}
+
+// policies lists the caches compared by CompareTrace, in table order
+var policies = []struct {
+	name string
+	new  func(capacity int) cache.Cache[string, int]
+}{
+	{"FIFO", cache.NewFIFOCache[string, int]},
+	{"LRU", cache.NewLRUCache[string, int]},
+	{"LFU", cache.NewLFUCache[string, int]},
+	{"ARC", cache.NewARCCache[string, int]},
+}
+
+// RunTrace replays keys against c. Each key is looked up and inserted on a
+// miss, so the trace behaves like a read-through workload.
+func RunTrace(c cache.Cache[string, int], keys []string) Stats {
+	var stats Stats
+	for i, key := range keys {
+		if _, err := c.Get(key); err == nil {
+			stats.Hits++
+			continue
+		}
+		stats.Misses++
+		_ = c.Set(key, i)
+	}
+	return stats
+}
+
+// CompareTrace replays trace against a fresh cache of every policy with the
+// given capacity and returns one result per policy. The last row, "OPT",
+// replays it against Belady's offline optimal cache, the upper bound of the
+// hit rate on that trace.
+func CompareTrace(trace []string, capacity int) []Result {
+	results := make([]Result, 0, len(policies)+1)
+	for _, policy := range policies {
+		results = append(results, Result{
+			Policy: policy.name,
+			Stats:  RunTrace(policy.new(capacity), trace),
+		})
+	}
+	return append(results, Result{
+		Policy: "OPT",
+		Stats:  RunTrace(cache.NewOfflineOptimalCache[string, int](capacity, trace), trace),
+	})
+}
+
+// LoadTrace reads a trace with one key per line. Lines may also hold several
+// comma-separated keys, so CSV files are accepted as well; empty fields are
+// skipped.
+func LoadTrace(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var trace []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return trace, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range record {
+			if key := strings.TrimSpace(field); key != "" {
+				trace = append(trace, key)
+			}
+		}
+	}
+}
+
+// LoadTraceFile reads a trace from the file at path, see LoadTrace
+func LoadTraceFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	trace, err := LoadTrace(file)
+	if err != nil {
+		return nil, fmt.Errorf("load trace %s: %w", path, err)
+	}
+	return trace, nil
+}
+
+// WriteCSV writes results as a CSV table with a header row
+func WriteCSV(w io.Writer, results []Result) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"policy", "hits", "misses", "hit_rate"}); err != nil {
+		return err
+	}
+	for _, result := range results {
+		record := []string{
+			result.Policy,
+			strconv.Itoa(result.Hits),
+			strconv.Itoa(result.Misses),
+			strconv.FormatFloat(result.HitRate(), 'f', 4, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -1,6 +1,12 @@
 package cache
 
 // Cache defines the interface for all cache implementations
+//
+// Set returns nil whenever the value was stored, including when storing it
+// evicted other entries. A full cache makes room instead of failing, so the
+// policies only fail with ErrCacheFull when they can't hold any entry at
+// all, that is with a capacity of 0 or less. Wrappers document their own
+// errors, such as ErrValueTooLarge.
 type Cache[K comparable, V any] interface {
 	Get(key K) (V, error)
 	Set(key K, value V) error
@@ -8,10 +14,50 @@ type Cache[K comparable, V any] interface {
 	Clear()
 }
 
-// This file contains the Cache interface and shared errors for the cache package.
-// Individual cache implementations are in separate files:
+// SizedCache is a cache reporting its occupancy. Every cache of the
+// strategies package implements it.
+type SizedCache[K comparable, V any] interface {
+	Cache[K, V]
+	// Len returns the number of entries Get would find
+	Len() int
+	// Cap returns the capacity the cache was created with
+	Cap() int
+}
+
+// GetDefault returns the value stored for key, or def if key is missing or
+// expired. A hit counts as an access like Get.
+func GetDefault[K comparable, V any](c Cache[K, V], key K, def V) V {
+	value, err := c.Get(key)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// This file contains the Cache interface for the cache package. Shared errors
+// are in errors.go and the constructors in fabric.go. Individual cache
+// implementations are in the strategies package:
 // - fifo.go: FIFO cache implementation
 // - lru.go: LRU cache implementation
-// - lfu.go: LFU cache implementation (to be implemented)
-// - ttl.go: TTL cache implementation (to be implemented)
-// - arc.go: ARC cache implementation (to be implemented) 
\ No newline at end of file
+// - lfu.go: LFU cache implementation
+// - ttl.go: TTL cache implementation
+// - arc.go: ARC cache implementation
+// - lruk.go: LRU-K cache implementation
+// - windowedlfu.go: windowed LFU cache implementation
+// - intfifo.go: FIFO cache implementation for dense int keys
+// - mru.go: MRU cache implementation
+// - random.go: random replacement cache implementation
+// - clockcache.go: CLOCK cache implementation
+// - slru.go: segmented LRU cache implementation
+// - twoqueue.go: 2Q cache implementation
+// - lirs.go: LIRS cache implementation
+// - tinylfu.go: W-TinyLFU cache implementation
+// - secondchance.go: second chance FIFO cache implementation
+// - car.go: CAR cache implementation
+// - sieve.go: SIEVE cache implementation
+// - s3fifo.go: S3-FIFO cache implementation
+// - gdsf.go: GreedyDual-Size-Frequency cache implementation
+// - priority.go: priority cache implementation
+// - policy.go: EvictionPolicy interface and the cache built on it
+// - policies.go: FIFO, LRU, LFU and ARC eviction policies
+// - optimal.go: Belady's offline optimal cache implementation
@@ -0,0 +1,60 @@
+// Package cachetest provides helpers for testing cache implementations.
+package cachetest
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"caching-labwork/cache"
+)
+
+// KeyedCache is a cache able to list its keys without touching them
+type KeyedCache[K comparable, V any] interface {
+	cache.Cache[K, V]
+	Keys() []K
+}
+
+// AssertEvictionOrder sets every key of inserts in order, storing the zero
+// value, and checks that exactly the keys of expectedSurvivors remain, in
+// any order. It reports a failure through t and returns whether the check
+// passed.
+func AssertEvictionOrder[K comparable, V any](t testing.TB, c KeyedCache[K, V], inserts, expectedSurvivors []K) bool {
+	t.Helper()
+
+	var zero V
+	for _, key := range inserts {
+		if err := c.Set(key, zero); err != nil {
+			t.Errorf("Set(%v): %v", key, err)
+			return false
+		}
+	}
+
+	survivors := c.Keys()
+	missing := difference(expectedSurvivors, survivors)
+	unexpected := difference(survivors, expectedSurvivors)
+	if len(missing) > 0 || len(unexpected) > 0 {
+		t.Errorf("eviction order mismatch after inserting %v: evicted %s, kept %s",
+			inserts, format(missing), format(unexpected))
+		return false
+	}
+	return true
+}
+
+// difference returns the keys of a that are not in b
+func difference[K comparable](a, b []K) []K {
+	var diff []K
+	for _, key := range a {
+		if !slices.Contains(b, key) {
+			diff = append(diff, key)
+		}
+	}
+	return diff
+}
+
+func format[K comparable](keys []K) string {
+	if len(keys) == 0 {
+		return "nothing unexpected"
+	}
+	return fmt.Sprintf("%v", keys)
+}
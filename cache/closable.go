@@ -0,0 +1,91 @@
+package cache
+
+import "sync"
+
+// janitorStopper is a cache running a background janitor
+type janitorStopper interface {
+	StopJanitor()
+}
+
+// ClosableCache wraps a cache so that it can be shut down gracefully with
+// Close. Once closed, Get, Set and Delete return ErrCacheClosed and Clear
+// does nothing.
+type ClosableCache[K comparable, V any] struct {
+	mu     sync.RWMutex
+	inner  MapCache[K, V]
+	closed bool
+}
+
+// NewClosableCache wraps inner, which must not be used directly afterwards
+func NewClosableCache[K comparable, V any](inner MapCache[K, V]) *ClosableCache[K, V] {
+	return &ClosableCache[K, V]{inner: inner}
+}
+
+// Get returns the value stored for key
+func (c *ClosableCache[K, V]) Get(key K) (V, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		var zero V
+		return zero, ErrCacheClosed
+	}
+	return c.inner.Get(key)
+}
+
+// Set stores value for key
+func (c *ClosableCache[K, V]) Set(key K, value V) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrCacheClosed
+	}
+	return c.inner.Set(key, value)
+}
+
+// Delete removes key from the cache
+func (c *ClosableCache[K, V]) Delete(key K) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrCacheClosed
+	}
+	return c.inner.Delete(key)
+}
+
+// Clear removes all entries
+func (c *ClosableCache[K, V]) Clear() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.closed {
+		c.inner.Clear()
+	}
+}
+
+// Close hands every live entry to drain, in no particular order, then clears
+// the cache and stops its janitor, if any. If drain fails, Close stops and
+// returns that error, leaving the cache open and its entries in place so
+// that Close can be retried. Closing a closed cache returns ErrCacheClosed.
+// Other operations wait for Close to finish.
+func (c *ClosableCache[K, V]) Close(drain func(key K, value V) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrCacheClosed
+	}
+	for key, value := range c.inner.ToMap() {
+		if err := drain(key, value); err != nil {
+			return err
+		}
+	}
+	c.inner.Clear()
+	if j, ok := c.inner.(janitorStopper); ok {
+		j.StopJanitor()
+	}
+	c.closed = true
+	return nil
+}
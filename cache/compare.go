@@ -0,0 +1,46 @@
+package cache
+
+// ConditionalDeleter is a cache supporting atomic conditional deletes
+type ConditionalDeleter[K comparable, V any] interface {
+	DeleteIf(key K, fn func(value V) bool) (bool, error)
+}
+
+// ConditionalSetter is a cache supporting atomic conditional updates
+type ConditionalSetter[K comparable, V any] interface {
+	SetIf(key K, value V, fn func(current V) bool) (bool, error)
+}
+
+// CompareAndDelete atomically removes key if its current value equals
+// expected. It reports whether key was deleted and returns ErrKeyNotFound if
+// key is missing.
+func CompareAndDelete[K comparable, V comparable](c ConditionalDeleter[K, V], key K, expected V) (bool, error) {
+	return CompareAndDeleteFunc(c, key, expected, equal[V])
+}
+
+// CompareAndDeleteFunc is like CompareAndDelete for values that are not
+// comparable, using eq to compare them. eq runs while the cache is locked.
+func CompareAndDeleteFunc[K comparable, V any](c ConditionalDeleter[K, V], key K, expected V, eq func(a, b V) bool) (bool, error) {
+	return c.DeleteIf(key, func(value V) bool {
+		return eq(value, expected)
+	})
+}
+
+// CompareAndSwap atomically replaces the value of key with value if its
+// current value equals expected. It reports whether the value was swapped
+// and returns ErrKeyNotFound if key is missing.
+func CompareAndSwap[K comparable, V comparable](c ConditionalSetter[K, V], key K, expected, value V) (bool, error) {
+	return CompareAndSwapFunc(c, key, expected, value, equal[V])
+}
+
+// CompareAndSwapFunc is like CompareAndSwap for values that are not
+// comparable, using eq to compare them. eq runs while the cache is locked.
+func CompareAndSwapFunc[K comparable, V any](c ConditionalSetter[K, V], key K, expected, value V, eq func(a, b V) bool) (bool, error) {
+	return c.SetIf(key, value, func(current V) bool {
+		return eq(current, expected)
+	})
+}
+
+// equal reports whether a and b are equal
+func equal[V comparable](a, b V) bool {
+	return a == b
+}
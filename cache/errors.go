@@ -1,9 +1,42 @@
 package cache
 
-import "errors"
+import (
+	"errors"
+
+	"caching-labwork/cache/strategies"
+)
 
 // Common errors
 var (
-	ErrKeyNotFound = errors.New("key not found")
-	ErrCacheFull   = errors.New("cache is full")
-) 
\ No newline at end of file
+	ErrKeyNotFound = strategies.ErrKeyNotFound
+	ErrCacheFull   = strategies.ErrCacheFull
+
+	ErrTooManyPinned = strategies.ErrTooManyPinned
+	ErrKeyOutOfRange = strategies.ErrKeyOutOfRange
+	ErrKeyExists     = strategies.ErrKeyExists
+)
+
+// Errors returned by New
+var (
+	ErrUnknownPolicy     = errors.New("unknown cache policy")
+	ErrUnsupportedOption = errors.New("option not supported by policy")
+)
+
+// ErrReentrantLoad is returned by GetOrLoad when a loader asks for a key it
+// is still loading
+var ErrReentrantLoad = errors.New("reentrant load of the same key")
+
+// ErrLoaderPanicked is returned by GetOrLoad to the callers waiting for a
+// load whose loader panicked
+var ErrLoaderPanicked = errors.New("loader panicked")
+
+// ErrValueTooLarge is returned by NewMaxValueSize caches for values above
+// the size limit
+var ErrValueTooLarge = errors.New("value too large")
+
+// ErrCacheClosed is returned by a ClosableCache once it has been closed
+var ErrCacheClosed = errors.New("cache is closed")
+
+// ErrVersionConflict is returned by SetVersioned when the entry changed since
+// the expected version was read
+var ErrVersionConflict = errors.New("version conflict")
@@ -1,38 +1,233 @@
 package cache
 
-import "time"
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"caching-labwork/cache/strategies"
+)
+
+// New creates a cache of the named policy: "fifo", "lru", "lfu", "ttl" or
+// "arc". It fails with ErrUnknownPolicy for other names and with
+// ErrUnsupportedOption when an option does not apply to the policy. policy
+// and capacity take precedence over WithPolicy and WithCapacity.
+func New[K comparable, V any](policy string, capacity int, opts ...Option) (Cache[K, V], error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.policy, cfg.capacity = policy, capacity
+	return build[K, V](cfg)
+}
+
+// NewCache creates a cache configured by options alone. The policy defaults
+// to "ttl" when WithTTL is given and to "lru" otherwise, and WithCapacity is
+// required. Every cache it returns is safe for concurrent use.
+func NewCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.capacity <= 0 {
+		return nil, fmt.Errorf("%w: NewCache requires a positive WithCapacity", ErrUnsupportedOption)
+	}
+	if cfg.policy == "" {
+		cfg.policy = "lru"
+		if cfg.ttl != 0 {
+			cfg.policy = "ttl"
+		}
+	}
+	return build[K, V](cfg)
+}
+
+// build creates the cache described by cfg
+func build[K comparable, V any](cfg config) (Cache[K, V], error) {
+	policy, capacity := strings.ToLower(cfg.policy), cfg.capacity
+	if policy != "ttl" && cfg.ttl != 0 {
+		return nil, fmt.Errorf("%w: WithTTL for policy %q", ErrUnsupportedOption, policy)
+	}
+	var c EvictingCache[K, V]
+	switch policy {
+	case "fifo":
+		c = strategies.NewFIFOCache[K, V](capacity, cfg.strategies...)
+	case "lru":
+		c = strategies.NewLRUCache[K, V](capacity, cfg.strategies...)
+	case "lfu":
+		c = strategies.NewLFUCache[K, V](capacity, cfg.strategies...)
+	case "ttl":
+		if cfg.ttl <= 0 {
+			return nil, fmt.Errorf("%w: policy %q requires a positive WithTTL", ErrUnsupportedOption, policy)
+		}
+		c = strategies.NewTTLCache[K, V](capacity, cfg.ttl, cfg.strategies...)
+	case "arc":
+		c = strategies.NewARCCache[K, V](capacity, cfg.strategies...)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPolicy, policy)
+	}
+
+	var onEvict func(key K, value V)
+	if cfg.onEvict != nil {
+		fn, ok := cfg.onEvict.(func(key K, value V))
+		if !ok {
+			return nil, fmt.Errorf("%w: WithOnEvict callback of type %T", ErrUnsupportedOption, cfg.onEvict)
+		}
+		onEvict = fn
+	}
+	if cfg.stats == nil {
+		if onEvict != nil {
+			c.SetEvictCallback(onEvict)
+		}
+		return c, nil
+	}
+
+	stats := cfg.stats
+	c.SetEvictCallback(func(key K, value V) {
+		stats.evictions.Add(1)
+		if onEvict != nil {
+			onEvict(key, value)
+		}
+	})
+	return &statsCache[K, V]{migratable: c.(migratable[K, V]), stats: stats}, nil
+}
 
 // NewFIFOCache creates a new FIFO (First In, First Out) cache
-// TODO: Implement this function
 func NewFIFOCache[K comparable, V any](capacity int) Cache[K, V] {
-	// Students should implement this
-	return &emptyCache[K, V]{}
+	return strategies.NewFIFOCache[K, V](capacity)
 }
 
 // NewLRUCache creates a new LRU (Least Recently Used) cache
-// TODO: Implement this function
 func NewLRUCache[K comparable, V any](capacity int) Cache[K, V] {
-	// Students should implement this
-	return &emptyCache[K, V]{}
+	return strategies.NewLRUCache[K, V](capacity)
+}
+
+// NewMRUCache creates a new MRU (Most Recently Used) cache
+func NewMRUCache[K comparable, V any](capacity int) Cache[K, V] {
+	return strategies.NewMRUCache[K, V](capacity)
+}
+
+// NewRandomCache creates a new random replacement cache picking victims
+// from src, or from a time-seeded source if src is nil
+func NewRandomCache[K comparable, V any](capacity int, src rand.Source) Cache[K, V] {
+	return strategies.NewRandomCache[K, V](capacity, src)
+}
+
+// NewClockCache creates a new CLOCK cache, an approximation of LRU giving
+// recently used entries a second chance
+func NewClockCache[K comparable, V any](capacity int) Cache[K, V] {
+	return strategies.NewClockCache[K, V](capacity)
+}
+
+// NewSLRUCache creates a new segmented LRU cache promoting entries from a
+// probationary to a protected segment on their second hit
+func NewSLRUCache[K comparable, V any](probationaryCap, protectedCap int) Cache[K, V] {
+	return strategies.NewSLRUCache[K, V](probationaryCap, protectedCap)
+}
+
+// NewTwoQueueCache creates a new 2Q cache with the default queue ratios
+func NewTwoQueueCache[K comparable, V any](size int) Cache[K, V] {
+	return strategies.NewTwoQueueCache[K, V](size)
+}
+
+// NewLIRSCache creates a new LIRS (Low Inter-reference Recency Set) cache
+func NewLIRSCache[K comparable, V any](capacity int) Cache[K, V] {
+	return strategies.NewLIRSCache[K, V](capacity)
+}
+
+// NewTinyLFUCache creates a new W-TinyLFU cache admitting entries to its
+// main space by their estimated access frequency
+func NewTinyLFUCache[K comparable, V any](capacity int) Cache[K, V] {
+	return strategies.NewTinyLFUCache[K, V](capacity)
+}
+
+// NewSecondChanceCache creates a new FIFO cache giving entries read since
+// they were enqueued a second chance before eviction
+func NewSecondChanceCache[K comparable, V any](capacity int) Cache[K, V] {
+	return strategies.NewSecondChanceCache[K, V](capacity)
+}
+
+// NewCARCache creates a new CAR (Clock with Adaptive Replacement) cache
+func NewCARCache[K comparable, V any](capacity int) Cache[K, V] {
+	return strategies.NewCARCache[K, V](capacity)
+}
+
+// NewSieveCache creates a new SIEVE cache
+func NewSieveCache[K comparable, V any](capacity int) Cache[K, V] {
+	return strategies.NewSieveCache[K, V](capacity)
+}
+
+// NewS3FIFOCache creates a new S3-FIFO cache
+func NewS3FIFOCache[K comparable, V any](capacity int) Cache[K, V] {
+	return strategies.NewS3FIFOCache[K, V](capacity)
+}
+
+// NewGDSFCache creates a new GreedyDual-Size-Frequency cache bounding the
+// total size of its entries. Set stores entries of size and cost 1; use
+// strategies.GDSFCache.SetWithCost for anything else.
+func NewGDSFCache[K comparable, V any](capacity int64) Cache[K, V] {
+	return strategies.NewGDSFCache[K, V](capacity)
+}
+
+// NewPriorityCache creates a new cache evicting the lowest priority entry
+// first. Set stores new entries with priority 0; use
+// strategies.PriorityCache.SetWithPriority for anything else.
+func NewPriorityCache[K comparable, V any](capacity int) Cache[K, V] {
+	return strategies.NewPriorityCache[K, V](capacity)
+}
+
+// NewPolicyCache creates a new cache evicting the entries chosen by policy,
+// for instance strategies.NewLRUPolicy or a policy of your own
+func NewPolicyCache[K comparable, V any](capacity int, policy strategies.EvictionPolicy[K]) Cache[K, V] {
+	return strategies.NewPolicyCache[K, V](capacity, policy)
+}
+
+// NewOfflineOptimalCache creates a new cache evicting by Belady's optimal
+// policy for the given access trace, for offline comparisons only
+func NewOfflineOptimalCache[K comparable, V any](capacity int, trace []K) Cache[K, V] {
+	return strategies.NewOfflineOptimalCache[K, V](capacity, trace)
 }
 
 // NewLFUCache creates a new LFU (Least Frequently Used) cache
-// TODO: Implement this function
 func NewLFUCache[K comparable, V any](capacity int) Cache[K, V] {
-	// Students should implement this
-	return &emptyCache[K, V]{}
+	return strategies.NewLFUCache[K, V](capacity)
 }
 
 // NewTTLCache creates a new TTL (Time To Live) cache
-// TODO: Implement this function
 func NewTTLCache[K comparable, V any](capacity int, ttl time.Duration) Cache[K, V] {
-	// Students should implement this
-	return &emptyCache[K, V]{}
+	return strategies.NewTTLCache[K, V](capacity, ttl)
+}
+
+// NewAdaptiveTTLCache creates a new TTL cache extending the lifetime of
+// frequently accessed entries from baseTTL up to maxTTL
+func NewAdaptiveTTLCache[K comparable, V any](capacity int, baseTTL, maxTTL time.Duration, opts ...strategies.Option) Cache[K, V] {
+	return strategies.NewAdaptiveTTLCache[K, V](capacity, baseTTL, maxTTL, opts...)
+}
+
+// NewLRUKCache creates a new LRU-K cache evicting by the k-th most recent
+// reference of entries
+func NewLRUKCache[K comparable, V any](capacity, k int) Cache[K, V] {
+	return strategies.NewLRUKCache[K, V](capacity, k)
+}
+
+// NewIntFIFOCache creates a new FIFO cache for int keys in [0, keySpace)
+func NewIntFIFOCache[V any](capacity, keySpace int) Cache[int, V] {
+	return strategies.NewIntFIFOCache[V](capacity, keySpace)
+}
+
+// NewWindowedLFUCache creates a new LFU cache counting the accesses made
+// within the last window
+func NewWindowedLFUCache[K comparable, V any](capacity int, window time.Duration) Cache[K, V] {
+	return strategies.NewWindowedLFUCache[K, V](capacity, window)
+}
+
+// NewFIFOTTLCache creates a new FIFO cache whose entries also expire ttl
+// after their first insertion
+func NewFIFOTTLCache[K comparable, V any](capacity int, ttl time.Duration, opts ...strategies.Option) Cache[K, V] {
+	return strategies.NewFIFOTTLCache[K, V](capacity, ttl, opts...)
 }
 
 // NewARCCache creates a new ARC (Adaptive Replacement Cache)
-// TODO: Implement this function (Advanced task)
 func NewARCCache[K comparable, V any](capacity int) Cache[K, V] {
-	// Students should implement this
-	return &emptyCache[K, V]{}
-} 
\ No newline at end of file
+	return strategies.NewARCCache[K, V](capacity)
+}
@@ -0,0 +1,103 @@
+// Package httpcache provides an http.RoundTripper caching GET responses in
+// any cache.Cache.
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"strings"
+	"time"
+
+	"caching-labwork/cache"
+)
+
+// HTTPCache is an http.RoundTripper serving GET requests from a cache.
+// Responses are cached by URL for the max-age of their Cache-Control header;
+// responses without max-age or marked no-store are not cached.
+type HTTPCache struct {
+	cache     cache.Cache[string, []byte]
+	transport http.RoundTripper
+}
+
+// New creates an HTTPCache storing responses in c and fetching them with
+// transport, or http.DefaultTransport if transport is nil
+func New(c cache.Cache[string, []byte], transport http.RoundTripper) *HTTPCache {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &HTTPCache{cache: c, transport: transport}
+}
+
+// RoundTrip serves req from the cache when a fresh response is stored and
+// fetches it with the underlying transport otherwise
+func (h *HTTPCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return h.transport.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	if data, err := h.cache.Get(key); err == nil {
+		if resp, ok := decode(data, req); ok {
+			return resp, nil
+		}
+		_ = h.cache.Delete(key)
+	}
+
+	resp, err := h.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	ttl, ok := maxAge(resp.Header)
+	if !ok || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	// DumpResponse leaves an unread copy of the body in resp
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return nil, err
+	}
+	data := binary.BigEndian.AppendUint64(nil, uint64(time.Now().Add(ttl).UnixNano()))
+	_ = h.cache.Set(key, append(data, dump...))
+	return resp, nil
+}
+
+// decode parses a cached response, reporting false if it is stale or corrupt
+func decode(data []byte, req *http.Request) (*http.Response, bool) {
+	if len(data) < 8 {
+		return nil, false
+	}
+	expiresAt := time.Unix(0, int64(binary.BigEndian.Uint64(data)))
+	if !time.Now().Before(expiresAt) {
+		return nil, false
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data[8:])), req)
+	if err != nil {
+		return nil, false
+	}
+	return resp, true
+}
+
+// maxAge returns the lifetime allowed by the Cache-Control header. It reports
+// false for no-store responses and responses without a positive max-age.
+func maxAge(header http.Header) (time.Duration, bool) {
+	var ttl time.Duration
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == "no-store" {
+			return 0, false
+		}
+		if value, ok := strings.CutPrefix(directive, "max-age="); ok {
+			seconds, err := strconv.Atoi(value)
+			if err != nil {
+				return 0, false
+			}
+			ttl = time.Duration(seconds) * time.Second
+		}
+	}
+	return ttl, ttl > 0
+}
@@ -0,0 +1,45 @@
+package cache
+
+import "caching-labwork/cache/strategies"
+
+// Compile-time checks that every implementation satisfies the interfaces
+var (
+	_ SizedCache[string, int] = (*strategies.FIFOCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.LRUCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.LFUCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.ARCCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.TTLCache[string, int])(nil)
+
+	_ SizedCache[string, int] = (*strategies.LRUKCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.WindowedLFUCache[string, int])(nil)
+	_ SizedCache[int, int]    = (*strategies.IntFIFOCache[int])(nil)
+	_ SizedCache[string, int] = (*strategies.MRUCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.RandomCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.ClockCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.SLRUCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.TwoQueueCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.LIRSCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.TinyLFUCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.SecondChanceCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.CARCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.SieveCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.S3FIFOCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.GDSFCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.PriorityCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.PolicyCache[string, int])(nil)
+	_ SizedCache[string, int] = (*strategies.OfflineOptimalCache[string, int])(nil)
+
+	_ Cache[string, int] = (*AdaptiveCache[string, int])(nil)
+	_ Cache[string, int] = (*ClosableCache[string, int])(nil)
+	_ Cache[string, int] = (*KeyFuncCache[string, int])(nil)
+	_ Cache[string, int] = (*LoadingCache[string, int])(nil)
+	_ Cache[string, int] = (*MaxValueSizeCache[string, int])(nil)
+	_ Cache[string, int] = (*Namespace[int])(nil)
+	_ Cache[string, int] = (*PooledLRU[string, int])(nil)
+	_ Cache[string, int] = (*SingleFlightCache[string, int])(nil)
+	_ Cache[string, int] = (*SoftCache[string, int])(nil)
+	_ Cache[string, int] = (*SpilloverCache[string, int])(nil)
+	_ Cache[string, int] = (*TaggedCache[string, int])(nil)
+	_ Cache[string, int] = (*VersionedCache[string, int])(nil)
+	_ Cache[string, int] = (*recordingCache[string, int])(nil)
+)
@@ -0,0 +1,30 @@
+package cache
+
+// Number is the set of value types supported by Increment
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Updater is a cache supporting atomic read-modify-write updates
+type Updater[K comparable, V any] interface {
+	Update(key K, fn func(value V, found bool) V) (V, error)
+}
+
+// Increment atomically adds delta to the value stored for key, treating a
+// missing key as zero, and returns the new value
+func Increment[K comparable, V Number](c Updater[K, V], key K, delta V) (V, error) {
+	return c.Update(key, func(value V, _ bool) V {
+		return value + delta
+	})
+}
+
+// Decrement atomically subtracts delta from the value stored for key,
+// treating a missing key as zero, and returns the new value. Unlike a
+// negative delta passed to Increment, it also works for unsigned counters.
+func Decrement[K comparable, V Number](c Updater[K, V], key K, delta V) (V, error) {
+	return c.Update(key, func(value V, _ bool) V {
+		return value - delta
+	})
+}
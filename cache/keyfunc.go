@@ -0,0 +1,86 @@
+package cache
+
+import "caching-labwork/cache/strategies"
+
+// KeyFuncCache is a cache keyed by values that are not comparable, or that
+// need normalizing. A key function derives the canonical string key used
+// internally, while the original key is stored next to the value and handed
+// back by Keys and the eviction callback.
+type KeyFuncCache[K any, V any] struct {
+	inner keyedStore[K, V]
+	keyFn func(K) string
+}
+
+// keyedStore is the cache holding the entries of a KeyFuncCache under their
+// canonical keys
+type keyedStore[K any, V any] interface {
+	EvictingCache[string, keyedValue[K, V]]
+	Values() []keyedValue[K, V]
+}
+
+// keyedValue is a value stored together with its original key
+type keyedValue[K any, V any] struct {
+	key   K
+	value V
+}
+
+// NewFIFOCacheFunc creates a FIFO cache whose keys are canonicalized by keyFn
+func NewFIFOCacheFunc[K any, V any](capacity int, keyFn func(K) string) *KeyFuncCache[K, V] {
+	return &KeyFuncCache[K, V]{inner: strategies.NewFIFOCache[string, keyedValue[K, V]](capacity), keyFn: keyFn}
+}
+
+// NewLRUCacheFunc creates an LRU cache whose keys are canonicalized by keyFn
+func NewLRUCacheFunc[K any, V any](capacity int, keyFn func(K) string) *KeyFuncCache[K, V] {
+	return &KeyFuncCache[K, V]{inner: strategies.NewLRUCache[string, keyedValue[K, V]](capacity), keyFn: keyFn}
+}
+
+// NewLFUCacheFunc creates an LFU cache whose keys are canonicalized by keyFn
+func NewLFUCacheFunc[K any, V any](capacity int, keyFn func(K) string) *KeyFuncCache[K, V] {
+	return &KeyFuncCache[K, V]{inner: strategies.NewLFUCache[string, keyedValue[K, V]](capacity), keyFn: keyFn}
+}
+
+// Get returns the value stored for key
+func (c *KeyFuncCache[K, V]) Get(key K) (V, error) {
+	kv, err := c.inner.Get(c.keyFn(key))
+	return kv.value, err
+}
+
+// Set stores value for key, replacing the original key stored for its
+// canonical key
+func (c *KeyFuncCache[K, V]) Set(key K, value V) error {
+	return c.inner.Set(c.keyFn(key), keyedValue[K, V]{key: key, value: value})
+}
+
+// Delete removes key from the cache
+func (c *KeyFuncCache[K, V]) Delete(key K) error {
+	return c.inner.Delete(c.keyFn(key))
+}
+
+// Clear removes all entries
+func (c *KeyFuncCache[K, V]) Clear() {
+	c.inner.Clear()
+}
+
+// Keys returns the original keys of all entries in eviction order, the next
+// victim first
+func (c *KeyFuncCache[K, V]) Keys() []K {
+	values := c.inner.Values()
+	keys := make([]K, len(values))
+	for i, kv := range values {
+		keys[i] = kv.key
+	}
+	return keys
+}
+
+// SetEvictCallback registers fn to be called with the original key and the
+// value of every entry the policy evicts, see the inner cache for when it
+// runs
+func (c *KeyFuncCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	if fn == nil {
+		c.inner.SetEvictCallback(nil)
+		return
+	}
+	c.inner.SetEvictCallback(func(_ string, kv keyedValue[K, V]) {
+		fn(kv.key, kv.value)
+	})
+}
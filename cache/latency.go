@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultLatencySamples is the number of recent loads the percentiles of
+// LatencyStats are computed from
+const DefaultLatencySamples = 1024
+
+// LatencyStats summarizes the durations of loader calls. Count, Min, Max and
+// Mean cover every load; the percentiles cover the most recent
+// DefaultLatencySamples loads.
+type LatencyStats struct {
+	Count         uint64
+	Min, Max      time.Duration
+	Mean          time.Duration
+	P50, P90, P99 time.Duration
+}
+
+// latencyTracker aggregates load durations and keeps a ring of recent ones
+type latencyTracker struct {
+	mu       sync.Mutex
+	count    uint64
+	total    time.Duration
+	min, max time.Duration
+	samples  []time.Duration
+	next     int
+}
+
+func (l *latencyTracker) record(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.count == 0 || d < l.min {
+		l.min = d
+	}
+	l.max = max(l.max, d)
+	l.count++
+	l.total += d
+	if len(l.samples) < DefaultLatencySamples {
+		l.samples = append(l.samples, d)
+		return
+	}
+	l.samples[l.next] = d
+	l.next = (l.next + 1) % DefaultLatencySamples
+}
+
+func (l *latencyTracker) stats() LatencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.count == 0 {
+		return LatencyStats{}
+	}
+	sorted := slices.Clone(l.samples)
+	slices.Sort(sorted)
+	percentile := func(p int) time.Duration {
+		return sorted[(len(sorted)-1)*p/100]
+	}
+	return LatencyStats{
+		Count: l.count,
+		Min:   l.min,
+		Max:   l.max,
+		Mean:  l.total / time.Duration(l.count),
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+	}
+}
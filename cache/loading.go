@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"caching-labwork/cache/strategies"
+)
+
+// LoadingCache is an LRU cache filling missing keys from a loader. Entries
+// older than the refresh window stay usable and are reloaded in the
+// background on access.
+type LoadingCache[K comparable, V any] struct {
+	inner        *SingleFlightCache[K, loaded[V]]
+	store        *strategies.LRUCache[K, loaded[V]] // wrapped by inner
+	loader       func(key K) (V, error)
+	refreshAfter time.Duration
+	failures     atomic.Uint64
+
+	mu         sync.Mutex
+	refreshing map[K]struct{}
+}
+
+// loaded is a value together with the time it was loaded
+type loaded[V any] struct {
+	value    V
+	loadedAt time.Time
+}
+
+// NewLoadingCache creates a loading cache holding at most capacity entries.
+// A non-positive refreshAfter disables background refreshes.
+func NewLoadingCache[K comparable, V any](capacity int, loader func(key K) (V, error), refreshAfter time.Duration) *LoadingCache[K, V] {
+	store := strategies.NewLRUCache[K, loaded[V]](capacity)
+	return &LoadingCache[K, V]{
+		inner:        NewSingleFlightCache[K, loaded[V]](store),
+		store:        store,
+		loader:       loader,
+		refreshAfter: refreshAfter,
+		refreshing:   make(map[K]struct{}),
+	}
+}
+
+// Get returns the value stored for key, loading it on a miss. A value older
+// than the refresh window is returned as is while a refresh runs.
+func (c *LoadingCache[K, V]) Get(key K) (V, error) {
+	e, err := c.inner.GetOrLoad(key, c.load)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	if c.refreshAfter > 0 && time.Since(e.loadedAt) >= c.refreshAfter {
+		c.mu.Lock()
+		_, running := c.refreshing[key]
+		if !running {
+			c.refreshing[key] = struct{}{}
+		}
+		c.mu.Unlock()
+		if !running {
+			go c.refresh(key)
+		}
+	}
+	return e.value, nil
+}
+
+// Set stores value for key as if it was just loaded
+func (c *LoadingCache[K, V]) Set(key K, value V) error {
+	return c.inner.Set(key, loaded[V]{value: value, loadedAt: time.Now()})
+}
+
+// Delete removes key from the cache
+func (c *LoadingCache[K, V]) Delete(key K) error {
+	return c.inner.Delete(key)
+}
+
+// Clear removes all entries from the cache
+func (c *LoadingCache[K, V]) Clear() {
+	c.inner.Clear()
+}
+
+// RefreshFailures returns how many background refreshes failed, panicking
+// loaders included. A failed refresh keeps the previous value.
+func (c *LoadingCache[K, V]) RefreshFailures() uint64 {
+	return c.failures.Load()
+}
+
+// LoadLatency returns statistics about the durations of the loader calls,
+// background refreshes and failed calls included
+func (c *LoadingCache[K, V]) LoadLatency() LatencyStats {
+	return c.inner.LoadLatency()
+}
+
+func (c *LoadingCache[K, V]) load(key K) (loaded[V], error) {
+	value, err := c.loader(key)
+	return loaded[V]{value: value, loadedAt: time.Now()}, err
+}
+
+// refresh reloads key and stores the new value if the loader succeeds and
+// key was not removed meanwhile. A loader panic is counted as a failure
+// rather than crashing the process from a goroutine no caller waits on.
+func (c *LoadingCache[K, V]) refresh(key K) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.failures.Add(1)
+		}
+		c.mu.Lock()
+		delete(c.refreshing, key)
+		c.mu.Unlock()
+	}()
+
+	start := time.Now()
+	e, err := c.load(key)
+	c.inner.latency.record(time.Since(start))
+	if err != nil {
+		c.failures.Add(1)
+		return
+	}
+	_ = c.store.SetIfPresent(key, e)
+}
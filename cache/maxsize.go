@@ -0,0 +1,24 @@
+package cache
+
+// MaxValueSizeCache rejects values larger than a size limit and passes
+// everything else to the cache it wraps
+type MaxValueSizeCache[K comparable, V any] struct {
+	Cache[K, V]
+	sizeOf       func(V) int64
+	maxItemBytes int64
+}
+
+// NewMaxValueSize wraps inner so that Set fails with ErrValueTooLarge for
+// values whose sizeOf exceeds maxItemBytes
+func NewMaxValueSize[K comparable, V any](inner Cache[K, V], sizeOf func(V) int64, maxItemBytes int64) *MaxValueSizeCache[K, V] {
+	return &MaxValueSizeCache[K, V]{Cache: inner, sizeOf: sizeOf, maxItemBytes: maxItemBytes}
+}
+
+// Set stores value for key in the inner cache unless it is too large. A
+// rejected value leaves any previous value of key untouched.
+func (c *MaxValueSizeCache[K, V]) Set(key K, value V) error {
+	if c.sizeOf(value) > c.maxItemBytes {
+		return ErrValueTooLarge
+	}
+	return c.Cache.Set(key, value)
+}
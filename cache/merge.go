@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// MapCache is a cache able to copy its entries into a map
+type MapCache[K comparable, V any] interface {
+	Cache[K, V]
+	ToMap() map[K]V
+}
+
+// MergeStrategy decides which value wins when both caches of a Merge hold
+// the same key
+type MergeStrategy int
+
+// Merge strategies
+const (
+	// MergePreferLocal keeps the value of the destination cache
+	MergePreferLocal MergeStrategy = iota
+	// MergePreferRemote takes the value of the source cache
+	MergePreferRemote
+	// MergeKeepNewest keeps the value expiring last. Both caches must report
+	// expiries, like the TTL caches do.
+	MergeKeepNewest
+)
+
+// expirer is a cache reporting when its entries expire
+type expirer[K comparable] interface {
+	Expiry(key K) (time.Time, error)
+}
+
+// Diff compares the entries of two caches. It returns the keys only found in
+// here, the keys only found in there, and the keys found in both with
+// different values, each in no particular order. The caches are copied one
+// after the other, so concurrent updates may show up in the result.
+func Diff[K comparable, V comparable](here, there MapCache[K, V]) (onlyHere, onlyThere, differing []K) {
+	a, b := here.ToMap(), there.ToMap()
+	for key, value := range a {
+		other, ok := b[key]
+		switch {
+		case !ok:
+			onlyHere = append(onlyHere, key)
+		case other != value:
+			differing = append(differing, key)
+		}
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			onlyThere = append(onlyThere, key)
+		}
+	}
+	return onlyHere, onlyThere, differing
+}
+
+// Merge copies the entries of src missing from dst into dst, and resolves the
+// keys held by both according to strategy. Values taken from src are stored
+// as if by Set. MergeKeepNewest fails with ErrUnsupportedOption unless both
+// caches report expiries.
+func Merge[K comparable, V any](dst, src MapCache[K, V], strategy MergeStrategy) error {
+	dstExpiry, dstOK := dst.(expirer[K])
+	srcExpiry, srcOK := src.(expirer[K])
+	if strategy == MergeKeepNewest && (!dstOK || !srcOK) {
+		return fmt.Errorf("%w: MergeKeepNewest for caches without expiries", ErrUnsupportedOption)
+	}
+
+	local := dst.ToMap()
+	for key, value := range src.ToMap() {
+		if _, ok := local[key]; ok {
+			switch strategy {
+			case MergePreferLocal:
+				continue
+			case MergeKeepNewest:
+				mine, err := dstExpiry.Expiry(key)
+				theirs, err2 := srcExpiry.Expiry(key)
+				if err == nil && (err2 != nil || !theirs.After(mine)) {
+					continue
+				}
+			}
+		}
+		if err := dst.Set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
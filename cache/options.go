@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"time"
+
+	"caching-labwork/cache/strategies"
+)
+
+// Option configures a cache created by New or NewCache
+type Option func(*config)
+
+// config holds the settings collected from the options passed to New or
+// NewCache
+type config struct {
+	policy     string
+	capacity   int
+	ttl        time.Duration
+	strategies []strategies.Option
+	onEvict    any
+	stats      *Stats
+}
+
+// WithPolicy selects the named policy of a cache created by NewCache, see
+// New for the supported names
+func WithPolicy(policy string) Option {
+	return func(c *config) {
+		c.policy = policy
+	}
+}
+
+// WithFIFO selects the "fifo" policy
+func WithFIFO() Option {
+	return WithPolicy("fifo")
+}
+
+// WithLRU selects the "lru" policy
+func WithLRU() Option {
+	return WithPolicy("lru")
+}
+
+// WithLFU selects the "lfu" policy
+func WithLFU() Option {
+	return WithPolicy("lfu")
+}
+
+// WithARC selects the "arc" policy
+func WithARC() Option {
+	return WithPolicy("arc")
+}
+
+// WithCapacity sets the number of entries a cache created by NewCache holds
+func WithCapacity(capacity int) Option {
+	return func(c *config) {
+		c.capacity = capacity
+	}
+}
+
+// WithTTL sets the default TTL of a "ttl" cache. It is required by that
+// policy and rejected by every other one.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// WithAccessTracking makes the cache count the accesses of every resident
+// key, see strategies.WithAccessTracking
+func WithAccessTracking() Option {
+	return func(c *config) {
+		c.strategies = append(c.strategies, strategies.WithAccessTracking())
+	}
+}
+
+// WithOnEvict registers fn to be called with every entry the policy evicts,
+// see SetEvictCallback of the strategies. New rejects fn unless its key and
+// value types match the cache.
+func WithOnEvict[K comparable, V any](fn func(key K, value V)) Option {
+	return func(c *config) {
+		c.onEvict = fn
+	}
+}
+
+// WithStats makes the cache count its Get hits and misses and its evictions
+// into stats. The counting wraps the cache New returns, so only the methods
+// of Cache stay reachable on it; leave the option out to type assert the
+// cache to its strategy type.
+func WithStats(stats *Stats) Option {
+	return func(c *config) {
+		c.stats = stats
+	}
+}
+
+// WithStrategyOptions passes opts on to the constructor of the policy, for
+// the settings without a counterpart here, such as strategies.WithClock,
+// WithJanitor, WithOverflowPolicy, WithFrequencyDecay or
+// WithEvictionEvents. Policies ignore the options that don't apply to them,
+// as documented in the strategies package.
+func WithStrategyOptions(opts ...strategies.Option) Option {
+	return func(c *config) {
+		c.strategies = append(c.strategies, opts...)
+	}
+}
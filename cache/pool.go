@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Pool is a memory budget shared by several LRU caches. When the combined
+// size of their entries exceeds the budget, the globally least recently used
+// entry is evicted, whichever cache holds it.
+type Pool struct {
+	mu       sync.Mutex
+	maxBytes int64
+	used     int64
+	order    *list.List // front is the most recently used entry of any cache
+}
+
+// pooledEntry is the part of a pooled entry the pool needs for eviction
+type pooledEntry interface {
+	release()
+}
+
+// NewPool creates a pool with a budget of maxBytes
+func NewPool(maxBytes int64) *Pool {
+	return &Pool{maxBytes: maxBytes, order: list.New()}
+}
+
+// Used returns the number of bytes accounted to the caches of the pool
+func (p *Pool) Used() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.used
+}
+
+// trim evicts the least recently used entries until the pool fits its budget
+func (p *Pool) trim() {
+	for p.used > p.maxBytes && p.order.Len() > 0 {
+		p.order.Back().Value.(pooledEntry).release()
+	}
+}
+
+// PooledLRU is an LRU cache accounting its entries against a Pool
+type PooledLRU[K comparable, V any] struct {
+	pool   *Pool
+	sizeOf func(V) int64
+	items  map[K]*list.Element // elements of the pool order
+	closed bool
+}
+
+// pooledItem is an entry of a PooledLRU
+type pooledItem[K comparable, V any] struct {
+	key   K
+	value V
+	size  int64
+	owner *PooledLRU[K, V]
+	elem  *list.Element
+}
+
+// NewPooledLRU creates an LRU cache sharing the budget of pool, where sizeOf
+// returns the number of bytes accounted to a value
+func NewPooledLRU[K comparable, V any](pool *Pool, sizeOf func(V) int64) *PooledLRU[K, V] {
+	return &PooledLRU[K, V]{pool: pool, sizeOf: sizeOf, items: make(map[K]*list.Element)}
+}
+
+// Get returns the value stored for key and marks it as most recently used
+func (c *PooledLRU[K, V]) Get(key K) (V, error) {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	c.pool.order.MoveToFront(elem)
+	return elem.Value.(*pooledItem[K, V]).value, nil
+}
+
+// Set stores value for key, evicting least recently used entries of the pool
+// until it fits the budget. Values larger than the whole budget and Sets on
+// a closed cache fail with ErrCacheFull.
+func (c *PooledLRU[K, V]) Set(key K, value V) error {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+
+	size := c.sizeOf(value)
+	if c.closed || size > c.pool.maxBytes {
+		return ErrCacheFull
+	}
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(pooledEntry).release()
+	}
+	item := &pooledItem[K, V]{key: key, value: value, size: size, owner: c}
+	item.elem = c.pool.order.PushFront(item)
+	c.items[key] = item.elem
+	c.pool.used += size
+	c.pool.trim()
+	return nil
+}
+
+// Delete removes key from the cache
+func (c *PooledLRU[K, V]) Delete(key K) error {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	elem.Value.(pooledEntry).release()
+	return nil
+}
+
+// Clear removes all entries, freeing their bytes in the pool
+func (c *PooledLRU[K, V]) Clear() {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+
+	c.clear()
+}
+
+// Close removes the cache from its pool, freeing all bytes it accounted.
+// The cache stays empty afterwards.
+func (c *PooledLRU[K, V]) Close() {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+
+	c.clear()
+	c.closed = true
+}
+
+func (c *PooledLRU[K, V]) clear() {
+	for _, elem := range c.items {
+		elem.Value.(pooledEntry).release()
+	}
+}
+
+// release removes the item from its cache and the pool
+func (item *pooledItem[K, V]) release() {
+	pool := item.owner.pool
+	pool.order.Remove(item.elem)
+	pool.used -= item.size
+	delete(item.owner.items, item.key)
+}
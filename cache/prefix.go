@@ -0,0 +1,70 @@
+package cache
+
+import "strings"
+
+// FuncDeleter is a cache able to remove all entries matching a predicate in
+// a single locked pass
+type FuncDeleter[K comparable, V any] interface {
+	DeleteFunc(fn func(key K, value V) bool) int
+}
+
+// DeletePrefix removes every key starting with prefix in a single pass and
+// returns how many keys it removed
+func DeletePrefix[V any](c FuncDeleter[string, V], prefix string) int {
+	return c.DeleteFunc(func(key string, _ V) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// PrefixCache is a string keyed cache supporting DeletePrefix
+type PrefixCache[V any] interface {
+	Cache[string, V]
+	FuncDeleter[string, V]
+}
+
+// Namespace is a view of the keys of a shared cache that start with a
+// prefix. Keys passed to it are relative to the prefix, and Clear only
+// removes the entries of the namespace, leaving the rest of the cache alone.
+type Namespace[V any] struct {
+	inner  PrefixCache[V]
+	prefix string
+}
+
+// NewNamespace returns the namespace of inner holding the keys that start
+// with prefix
+func NewNamespace[V any](inner PrefixCache[V], prefix string) *Namespace[V] {
+	return &Namespace[V]{inner: inner, prefix: prefix}
+}
+
+// Namespace returns the nested namespace of the keys that start with prefix
+// within this one
+func (n *Namespace[V]) Namespace(prefix string) *Namespace[V] {
+	return NewNamespace(n.inner, n.prefix+prefix)
+}
+
+// Prefix returns the prefix of the namespace within the shared cache
+func (n *Namespace[V]) Prefix() string {
+	return n.prefix
+}
+
+// Get returns the value stored for key in the namespace
+func (n *Namespace[V]) Get(key string) (V, error) {
+	return n.inner.Get(n.prefix + key)
+}
+
+// Set stores value for key in the namespace. Entries of all namespaces
+// compete for the capacity of the shared cache.
+func (n *Namespace[V]) Set(key string, value V) error {
+	return n.inner.Set(n.prefix+key, value)
+}
+
+// Delete removes key from the namespace
+func (n *Namespace[V]) Delete(key string) error {
+	return n.inner.Delete(n.prefix + key)
+}
+
+// Clear removes every entry of the namespace, including those of nested
+// namespaces
+func (n *Namespace[V]) Clear() {
+	DeletePrefix(n.inner, n.prefix)
+}
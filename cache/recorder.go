@@ -0,0 +1,120 @@
+package cache
+
+import "sync"
+
+// DefaultRecorderSize is the number of events kept by a Recorder
+const DefaultRecorderSize = 4096
+
+// Op identifies a cache operation
+type Op int
+
+// Recorded operations
+const (
+	OpGet Op = iota
+	OpSet
+	OpDelete
+	OpClear
+)
+
+// String returns the name of the operation
+func (op Op) String() string {
+	switch op {
+	case OpGet:
+		return "get"
+	case OpSet:
+		return "set"
+	case OpDelete:
+		return "delete"
+	case OpClear:
+		return "clear"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a single recorded cache operation. Hit reports whether the
+// operation succeeded: a Get hit, a stored Set or a Delete of a present key.
+type Event[K comparable] struct {
+	Op  Op
+	Key K
+	Hit bool
+}
+
+// Recorder keeps the most recent operations applied to a cache in a ring
+// buffer, e.g. to replay a captured workload against other policies
+type Recorder[K comparable] struct {
+	mu     sync.Mutex
+	events []Event[K]
+	next   int
+	full   bool
+}
+
+// recordingCache forwards every operation to inner and records it
+type recordingCache[K comparable, V any] struct {
+	inner    Cache[K, V]
+	recorder *Recorder[K]
+}
+
+// NewRecorder wraps inner into a cache recording every operation. The
+// returned cache behaves exactly like inner.
+func NewRecorder[K comparable, V any](inner Cache[K, V]) (*Recorder[K], Cache[K, V]) {
+	recorder := &Recorder[K]{events: make([]Event[K], DefaultRecorderSize)}
+	return recorder, &recordingCache[K, V]{inner: inner, recorder: recorder}
+}
+
+// Events returns the recorded events from the oldest to the newest
+func (r *Recorder[K]) Events() []Event[K] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]Event[K](nil), r.events[:r.next]...)
+	}
+	events := make([]Event[K], 0, len(r.events))
+	events = append(events, r.events[r.next:]...)
+	return append(events, r.events[:r.next]...)
+}
+
+// Reset drops all recorded events
+func (r *Recorder[K]) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.next = 0
+	r.full = false
+}
+
+func (r *Recorder[K]) record(event Event[K]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events[r.next] = event
+	r.next++
+	if r.next == len(r.events) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+func (c *recordingCache[K, V]) Get(key K) (V, error) {
+	value, err := c.inner.Get(key)
+	c.recorder.record(Event[K]{Op: OpGet, Key: key, Hit: err == nil})
+	return value, err
+}
+
+func (c *recordingCache[K, V]) Set(key K, value V) error {
+	err := c.inner.Set(key, value)
+	c.recorder.record(Event[K]{Op: OpSet, Key: key, Hit: err == nil})
+	return err
+}
+
+func (c *recordingCache[K, V]) Delete(key K) error {
+	err := c.inner.Delete(key)
+	c.recorder.record(Event[K]{Op: OpDelete, Key: key, Hit: err == nil})
+	return err
+}
+
+func (c *recordingCache[K, V]) Clear() {
+	c.inner.Clear()
+	c.recorder.record(Event[K]{Op: OpClear, Hit: true})
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"time"
+
+	"caching-labwork/cache/strategies"
+)
+
+// The constructors below create a cache and insert the seed entries as if by
+// Set, evicting entries if the seed exceeds the capacity. Maps have no order,
+// so which seed entries survive such an overflow is unspecified. A cache
+// with a capacity of 0 or less stays empty.
+
+// NewFIFOCacheFrom creates a FIFO cache seeded with the entries of seed
+func NewFIFOCacheFrom[K comparable, V any](capacity int, seed map[K]V) Cache[K, V] {
+	c := strategies.NewFIFOCache[K, V](capacity)
+	_ = c.FromMap(seed)
+	return c
+}
+
+// NewLRUCacheFrom creates an LRU cache seeded with the entries of seed
+func NewLRUCacheFrom[K comparable, V any](capacity int, seed map[K]V) Cache[K, V] {
+	c := strategies.NewLRUCache[K, V](capacity)
+	_ = c.FromMap(seed)
+	return c
+}
+
+// NewLFUCacheFrom creates an LFU cache seeded with the entries of seed
+func NewLFUCacheFrom[K comparable, V any](capacity int, seed map[K]V) Cache[K, V] {
+	c := strategies.NewLFUCache[K, V](capacity)
+	_ = c.FromMap(seed)
+	return c
+}
+
+// NewTTLCacheFrom creates a TTL cache seeded with the entries of seed, all
+// expiring ttl after the construction
+func NewTTLCacheFrom[K comparable, V any](capacity int, ttl time.Duration, seed map[K]V) Cache[K, V] {
+	c := strategies.NewTTLCache[K, V](capacity, ttl)
+	_ = c.FromMap(seed)
+	return c
+}
+
+// NewARCCacheFrom creates an ARC cache seeded with the entries of seed
+func NewARCCacheFrom[K comparable, V any](capacity int, seed map[K]V) Cache[K, V] {
+	c := strategies.NewARCCache[K, V](capacity)
+	_ = c.FromMap(seed)
+	return c
+}
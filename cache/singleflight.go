@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SingleFlightCache adds GetOrLoad to a cache. Concurrent misses on the same
+// key share a single call of the loader.
+type SingleFlightCache[K comparable, V any] struct {
+	Cache[K, V]
+	mu       sync.Mutex
+	inflight map[K]*loadCall[V]
+	latency  latencyTracker
+}
+
+// loadCall is a load in progress, owned by the goroutine running it
+type loadCall[V any] struct {
+	done  chan struct{}
+	owner uint64
+	value V
+	err   error
+}
+
+// NewSingleFlightCache wraps inner into a cache with single-flight loading
+func NewSingleFlightCache[K comparable, V any](inner Cache[K, V]) *SingleFlightCache[K, V] {
+	return &SingleFlightCache[K, V]{
+		Cache:    inner,
+		inflight: make(map[K]*loadCall[V]),
+	}
+}
+
+// GetOrLoad returns the value stored for key. On a miss, which the inner
+// cache reports to its miss hook as usual, it calls load and stores the
+// result, unless load fails. Callers missing the same key while a
+// load is running wait for it and share its result. A load asking for its
+// own key again, directly or through other keys, gets ErrReentrantLoad
+// instead of waiting for itself. If load panics, the panic goes on in the
+// caller running it while the waiting callers get ErrLoaderPanicked.
+func (c *SingleFlightCache[K, V]) GetOrLoad(key K, load func(key K) (V, error)) (V, error) {
+	if value, err := c.Cache.Get(key); err == nil {
+		return value, nil
+	}
+
+	id := goroutineID()
+	c.mu.Lock()
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		if call.owner == id {
+			var zero V
+			return zero, ErrReentrantLoad
+		}
+		<-call.done
+		return call.value, call.err
+	}
+	call := &loadCall[V]{done: make(chan struct{}), owner: id}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		// Waiters must not mistake a panicking load for a successful one
+		r := recover()
+		if r != nil {
+			var zero V
+			call.value, call.err = zero, fmt.Errorf("%w: %v", ErrLoaderPanicked, r)
+		}
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		close(call.done)
+		if r != nil {
+			panic(r)
+		}
+	}()
+	start := time.Now()
+	call.value, call.err = load(key)
+	c.latency.record(time.Since(start))
+	if call.err == nil {
+		call.err = c.Cache.Set(key, call.value)
+	}
+	return call.value, call.err
+}
+
+// LoadLatency returns statistics about the durations of the loads run by
+// GetOrLoad, failed ones included
+func (c *SingleFlightCache[K, V]) LoadLatency() LatencyStats {
+	return c.latency.stats()
+}
+
+// goroutineID returns the id of the calling goroutine, parsed from the
+// "goroutine N [...]" header of its stack trace
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	header, _, _ = bytes.Cut(header, []byte(" "))
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
@@ -0,0 +1,91 @@
+// Package sketch provides probabilistic data structures for approximate
+// frequency counting over large key spaces with bounded memory.
+package sketch
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// CountMinSketch estimates how often keys were added. Estimates are never
+// below the true count; with a width of w and a depth of d they exceed it by
+// at most e/w times the total number of additions with a probability of
+// 1 - e^-d.
+type CountMinSketch struct {
+	mu       sync.Mutex
+	width    int
+	depth    int
+	seed     maphash.Seed
+	counters []uint64 // depth rows of width counters
+}
+
+// NewCountMinSketch creates a sketch with depth rows of width counters.
+// Values below 1 are treated as 1.
+func NewCountMinSketch(width, depth int) *CountMinSketch {
+	width, depth = max(width, 1), max(depth, 1)
+	return &CountMinSketch{
+		width:    width,
+		depth:    depth,
+		seed:     maphash.MakeSeed(),
+		counters: make([]uint64, width*depth),
+	}
+}
+
+// Add counts one occurrence of key
+func (s *CountMinSketch) Add(key string) {
+	s.AddHash(maphash.String(s.seed, key))
+}
+
+// Estimate returns the approximate number of times key was added
+func (s *CountMinSketch) Estimate(key string) uint64 {
+	return s.EstimateHash(maphash.String(s.seed, key))
+}
+
+// AddHash is like Add for a key the caller already hashed to hash
+func (s *CountMinSketch) AddHash(hash uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for row := 0; row < s.depth; row++ {
+		s.counters[s.index(row, hash)]++
+	}
+}
+
+// EstimateHash is like Estimate for a key the caller already hashed to hash
+func (s *CountMinSketch) EstimateHash(hash uint64) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	estimate := s.counters[s.index(0, hash)]
+	for row := 1; row < s.depth; row++ {
+		estimate = min(estimate, s.counters[s.index(row, hash)])
+	}
+	return estimate
+}
+
+// Reset sets all counters back to zero
+func (s *CountMinSketch) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.counters)
+}
+
+// Halve divides all counters by two, so that past additions weigh less
+// than recent ones. Periodic halving lets estimates follow a popularity
+// that changes over time.
+func (s *CountMinSketch) Halve() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.counters {
+		s.counters[i] >>= 1
+	}
+}
+
+// index returns the counter of hash in row, deriving the row hashes from the
+// two halves of hash by double hashing
+func (s *CountMinSketch) index(row int, hash uint64) int {
+	h := (hash >> 32) + uint64(row)*(hash&0xffffffff|1)
+	return row*s.width + int(h%uint64(s.width))
+}
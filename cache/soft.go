@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"runtime"
+	"sync"
+
+	"caching-labwork/cache/strategies"
+)
+
+// SoftCache is an experimental cache keeping its most recently used entries
+// in an LRU window and handing the entries evicted from it to the garbage
+// collector. Such cold entries may be reclaimed by any collection, and are
+// reported as misses from then on.
+//
+// Cold values are held in a sync.Pool per entry, which the runtime empties
+// during garbage collection. A pool gives no guarantee of returning what was
+// put in it, so a lookup may also miss a cold entry that was not reclaimed
+// yet. Once a cold value is reclaimed, a finalizer drops its entry from the
+// cold tier. A cold hit moves the entry back into the hot window.
+type SoftCache[K comparable, V any] struct {
+	mu   sync.Mutex
+	hot  *strategies.LRUCache[K, V]
+	cold map[K]*sync.Pool
+}
+
+// softValue boxes a cold value so that the pool holds a pointer
+type softValue[V any] struct {
+	value V
+}
+
+// NewSoftCache creates a soft cache whose hot window holds hotCapacity
+// entries. The number of cold entries is only bounded by the memory the
+// garbage collector leaves them.
+func NewSoftCache[K comparable, V any](hotCapacity int) *SoftCache[K, V] {
+	c := &SoftCache[K, V]{
+		hot:  strategies.NewLRUCache[K, V](hotCapacity),
+		cold: make(map[K]*sync.Pool),
+	}
+	// Evictions happen inside calls made under c.mu
+	c.hot.SetEvictCallback(c.demote)
+	return c
+}
+
+// Get returns the value stored for key. A reclaimed cold entry is a miss.
+func (c *SoftCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if value, err := c.hot.Get(key); err == nil {
+		return value, nil
+	}
+	pool, ok := c.cold[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	delete(c.cold, key)
+	soft, _ := pool.Get().(*softValue[V])
+	if soft == nil {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	if err := c.hot.Set(key, soft.value); err != nil {
+		var zero V
+		return zero, err
+	}
+	return soft.value, nil
+}
+
+// Set stores value for key in the hot window
+func (c *SoftCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.cold, key)
+	return c.hot.Set(key, value)
+}
+
+// Delete removes key from the cache. A cold entry counts as present until
+// the finalizer of its reclaimed value dropped it.
+func (c *SoftCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.cold[key]; ok {
+		delete(c.cold, key)
+		return nil
+	}
+	return c.hot.Delete(key)
+}
+
+// Clear removes all entries
+func (c *SoftCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hot.Clear()
+	clear(c.cold)
+}
+
+// Len returns the number of hot entries plus the number of cold entries
+// whose value was not reclaimed yet
+func (c *SoftCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.hot.Len() + len(c.cold)
+}
+
+// demote moves an entry evicted from the hot window to the cold tier
+func (c *SoftCache[K, V]) demote(key K, value V) {
+	pool := &sync.Pool{}
+	soft := &softValue[V]{value: value}
+	// The box becomes unreachable once the pool dropped it or a cold hit
+	// took it out, so the map entry would otherwise outlive the value
+	runtime.SetFinalizer(soft, func(*softValue[V]) { c.reclaim(key, pool) })
+	pool.Put(soft)
+	c.cold[key] = pool
+}
+
+// reclaim drops key from the cold tier if it is still held in pool
+func (c *SoftCache[K, V]) reclaim(key K, pool *sync.Pool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cold[key] == pool {
+		delete(c.cold, key)
+	}
+}
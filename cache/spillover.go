@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"caching-labwork/cache/strategies"
+)
+
+// spillSuffix is the file name suffix of spilled entries
+const spillSuffix = ".spill"
+
+// Codec converts values to and from their on-disk representation
+type Codec[V any] interface {
+	Marshal(value V) ([]byte, error)
+	Unmarshal(data []byte) (V, error)
+}
+
+// JSONCodec is a Codec encoding values as JSON
+type JSONCodec[V any] struct{}
+
+// Marshal encodes value as JSON
+func (JSONCodec[V]) Marshal(value V) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Unmarshal decodes a JSON encoded value
+func (JSONCodec[V]) Unmarshal(data []byte) (V, error) {
+	var value V
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// SpilloverCache keeps its hottest entries in an in-memory LRU cache and
+// spills the entries evicted from memory to files in a directory. Spilled
+// entries are loaded back into memory by a later Get.
+type SpilloverCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	mem      *strategies.LRUCache[K, V]
+	dir      string
+	codec    Codec[V]
+	spillErr error // error of the last failed spill, reported by Set
+}
+
+// NewSpilloverCache creates a cache keeping memCapacity entries in memory
+// and spilling the rest into dir, which must exist
+func NewSpilloverCache[K comparable, V any](memCapacity int, dir string, codec Codec[V]) *SpilloverCache[K, V] {
+	c := &SpilloverCache[K, V]{
+		mem:   strategies.NewLRUCache[K, V](memCapacity),
+		dir:   dir,
+		codec: codec,
+	}
+	c.mem.SetEvictCallback(c.spill)
+	return c
+}
+
+// Get returns the value stored for key, reading it back from disk if it was
+// spilled. Errors reading a spilled entry are returned as is.
+func (c *SpilloverCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if value, err := c.mem.Get(key); err == nil {
+		return value, nil
+	}
+
+	var zero V
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return zero, ErrKeyNotFound
+	}
+	if err != nil {
+		return zero, err
+	}
+	value, err := c.codec.Unmarshal(data)
+	if err != nil {
+		return zero, fmt.Errorf("decode spilled entry %s: %w", path, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return zero, err
+	}
+	if err := c.set(key, value); err != nil {
+		return zero, err
+	}
+	return value, nil
+}
+
+// Set stores value for key in memory, spilling the least recently used
+// entry to disk if memory is full
+func (c *SpilloverCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Remove(c.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return c.set(key, value)
+}
+
+// Delete removes key from memory and disk
+func (c *SpilloverCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	memErr := c.mem.Delete(key)
+	err := os.Remove(c.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return memErr
+	}
+	return err
+}
+
+// Clear removes all entries from memory and disk
+func (c *SpilloverCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.mem.Clear()
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), spillSuffix) {
+			_ = os.Remove(filepath.Join(c.dir, entry.Name()))
+		}
+	}
+}
+
+// set stores value in memory and reports a failure to spill the evicted entry
+func (c *SpilloverCache[K, V]) set(key K, value V) error {
+	c.spillErr = nil
+	if err := c.mem.Set(key, value); err != nil {
+		return err
+	}
+	return c.spillErr
+}
+
+// spill writes an entry evicted from memory to disk
+func (c *SpilloverCache[K, V]) spill(key K, value V) {
+	data, err := c.codec.Marshal(value)
+	if err == nil {
+		err = os.WriteFile(c.path(key), data, 0o600)
+	}
+	if err != nil {
+		c.spillErr = fmt.Errorf("spill entry: %w", err)
+	}
+}
+
+// path returns the file holding the spilled entry of key
+func (c *SpilloverCache[K, V]) path(key K) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%#v", key)))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+spillSuffix)
+}
@@ -0,0 +1,63 @@
+package cache
+
+import "sync/atomic"
+
+// Stats counts the Get hits and misses and the evictions of the caches
+// created with WithStats. It is safe for concurrent use, and several caches
+// may share one.
+type Stats struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// Hits returns the number of Get calls that found their key
+func (s *Stats) Hits() uint64 {
+	return s.hits.Load()
+}
+
+// Misses returns the number of Get calls that did not find their key
+func (s *Stats) Misses() uint64 {
+	return s.misses.Load()
+}
+
+// Evictions returns the number of entries the policy evicted, expired
+// entries included. Explicit Delete and Clear calls are not counted.
+func (s *Stats) Evictions() uint64 {
+	return s.evictions.Load()
+}
+
+// HitRate returns the fraction of Get calls that were hits, or 0 before the
+// first one
+func (s *Stats) HitRate() float64 {
+	hits, misses := s.hits.Load(), s.misses.Load()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// statsCache counts the Get hits and misses of inner into stats. It stays
+// migratable so that AdaptiveCache accepts it.
+type statsCache[K comparable, V any] struct {
+	migratable[K, V]
+	stats *Stats
+}
+
+// Get returns the value stored for key and counts the hit or miss
+func (c *statsCache[K, V]) Get(key K) (V, error) {
+	value, err := c.migratable.Get(key)
+	if err != nil {
+		c.stats.misses.Add(1)
+	} else {
+		c.stats.hits.Add(1)
+	}
+	return value, err
+}
+
+// StopJanitor stops the janitor of inner, if it runs one
+func (c *statsCache[K, V]) StopJanitor() {
+	if j, ok := c.migratable.(janitorStopper); ok {
+		j.StopJanitor()
+	}
+}
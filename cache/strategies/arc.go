@@ -1,6 +1,814 @@
 package strategies
 
+import (
+	"container/list"
+	"sync"
+)
+
 // ARCCache implements an Adaptive Replacement Cache
+//
+// Resident entries live in t1 (seen once recently) and t2 (seen at least
+// twice). The ghost lists b1 and b2 remember the keys recently evicted from
+// t1 and t2; hits on ghosts adapt the target size p of t1.
 type ARCCache[K comparable, V any] struct {
-	// TODO: Add necessary fields for ARC implementation
-}
\ No newline at end of file
+	mu        sync.Mutex
+	capacity  int
+	p         int // target size of t1
+	t1, t2    *list.List
+	b1, b2    *list.List
+	items     map[K]*list.Element // entries of t1, t2, b1 and b2
+	onEvict   evictHook[K, V]
+	onMiss    func(key K)
+	watermark watermark
+	pinned    map[K]V // entries kept out of reach of the policy
+	accesses  accessCounts[K]
+	events    eventStream[K, V]
+	overflow  OverflowPolicy
+}
+
+// arcEntry is an entry together with the list it currently belongs to.
+// CARCache shares it, and uses the reference bit.
+type arcEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	where      *list.List
+	referenced bool
+}
+
+// NewARCCache creates an ARC cache holding at most capacity entries
+func NewARCCache[K comparable, V any](capacity int, opts ...Option) *ARCCache[K, V] {
+	o := newOptions(opts)
+	return &ARCCache[K, V]{
+		capacity: capacity,
+		t1:       list.New(),
+		t2:       list.New(),
+		b1:       list.New(),
+		b2:       list.New(),
+		items:    make(map[K]*list.Element),
+		events:   newEventStream[K, V](o.eventBuffer),
+		overflow: o.overflow,
+		accesses: newAccessCounts[K](o.tracking),
+	}
+}
+
+// Get returns the value stored for key and promotes it to the frequent list
+func (c *ARCCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	value, err := c.get(key)
+	onMiss := c.onMiss
+	c.unlock()
+
+	if err != nil && onMiss != nil {
+		onMiss(key)
+	}
+	return value, err
+}
+
+// GetBatch looks up all keys under a single lock. The results are aligned
+// with keys, including duplicates; misses carry ErrKeyNotFound.
+func (c *ARCCache[K, V]) GetBatch(keys []K) []Result[V] {
+	c.mu.Lock()
+	results := make([]Result[V], len(keys))
+	for i, key := range keys {
+		results[i].Value, results[i].Err = c.get(key)
+	}
+	onMiss := c.onMiss
+	c.unlock()
+
+	if onMiss != nil {
+		for i, key := range keys {
+			if results[i].Err != nil {
+				onMiss(key)
+			}
+		}
+	}
+	return results
+}
+
+// Set stores value for key, adapting the balance between the recent and
+// frequent lists when key is found in a ghost list
+func (c *ARCCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// Add stores value for key only if key is not cached yet, and fails with
+// ErrKeyExists otherwise
+func (c *ARCCache[K, V]) Add(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.peek(key); ok {
+		return ErrKeyExists
+	}
+	return c.set(key, value)
+}
+
+// SetIfPresent stores value for key only if key is cached, and fails with
+// ErrKeyNotFound otherwise. Like Set, it counts as an access.
+func (c *ARCCache[K, V]) SetIfPresent(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.peek(key); !ok {
+		return ErrKeyNotFound
+	}
+	return c.set(key, value)
+}
+
+// SetMany stores entries in order in a single locked pass, evicting as
+// usual after each insert. A batch with more keys than the capacity either
+// leaves only its last keys resident or, with RejectExcess, fails with
+// ErrCacheFull before anything is stored. Otherwise SetMany stops at the
+// first failing insert and returns its error.
+func (c *ARCCache[K, V]) SetMany(entries []Entry[K, V]) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.overflow == RejectExcess && overflows(entries, c.capacity, c.pinned) {
+		return ErrCacheFull
+	}
+	for _, e := range entries {
+		if err := c.set(e.Key, e.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Keys returns the keys of all entries in eviction order, the next victim
+// first and pinned entries last, without counting as an access
+func (c *ARCCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return entryKeys(c.ordered())
+}
+
+// Values returns the values of all entries in the order of Keys, without
+// counting as an access
+func (c *ARCCache[K, V]) Values() []V {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return entryValues(c.ordered())
+}
+
+// Items returns all entries in the order of Keys, without counting as an
+// access
+func (c *ARCCache[K, V]) Items() []Entry[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.ordered()
+}
+
+// All returns an iterator over the entries in the order of Keys, without
+// counting as accesses. Each iteration walks a snapshot taken when it
+// starts, so the loop body may call back into the cache and sees none of
+// its own changes. The iterator has the type of iter.Seq2[K, V], so that
+// with Go 1.23 or later it can be ranged over directly.
+func (c *ARCCache[K, V]) All() func(yield func(key K, value V) bool) {
+	return func(yield func(key K, value V) bool) {
+		for _, e := range c.Items() {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// ForEach calls fn with the entries in the order of Keys until fn returns
+// false, without counting as accesses. Unlike All it takes no snapshot: fn
+// runs while the cache is locked and must not call back into the cache.
+func (c *ARCCache[K, V]) ForEach(fn func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.walk(fn)
+}
+
+// ToMap returns a copy of all resident entries without counting as an access
+func (c *ARCCache[K, V]) ToMap() map[K]V {
+	c.mu.Lock()
+	defer c.unlock()
+
+	m := make(map[K]V, len(c.items))
+	for key, elem := range c.items {
+		if c.resident(elem) {
+			m[key] = elem.Value.(*arcEntry[K, V]).value
+		}
+	}
+	for key, value := range c.pinned {
+		m[key] = value
+	}
+	return m
+}
+
+// FromMap inserts every entry of m as if by Set, applying eviction as
+// usual. It stops at the first failing insert and returns its error.
+func (c *ARCCache[K, V]) FromMap(m map[K]V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for key, value := range m {
+		if err := c.set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update atomically replaces the value of key with fn(value, found), where
+// found reports whether key was present. The update counts as a single
+// access. fn runs while the cache is locked and must not call back into
+// the cache.
+func (c *ARCCache[K, V]) Update(key K, fn func(value V, found bool) V) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value := fn(c.peek(key))
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, err
+	}
+	return value, nil
+}
+
+// Contains reports whether key is stored, pinned or not, without counting
+// as an access
+func (c *ARCCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.peek(key)
+	return ok
+}
+
+// Peek returns the value stored for key like Get, but without counting as
+// an access, so the eviction order stays as it was
+func (c *ARCCache[K, V]) Peek(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, ok := c.peek(key)
+	if !ok {
+		return value, ErrKeyNotFound
+	}
+	return value, nil
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *ARCCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// GetOrCompute returns the value stored for key if there is one, and
+// otherwise calls fn and stores its result. An error from fn is returned
+// and nothing is stored. fn runs while the cache is unlocked, so it may be
+// slow or call back into the cache; concurrent misses on the same key each
+// call fn, and the first result stored is returned to all of them. Wrap the
+// cache in a SingleFlightCache to share a single call instead.
+func (c *ARCCache[K, V]) GetOrCompute(key K, fn func() (V, error)) (V, error) {
+	c.mu.Lock()
+	value, err := c.get(key)
+	c.unlock()
+	if err == nil {
+		return value, nil
+	}
+
+	value, err = fn()
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, ok := c.peek(key); ok {
+		return actual, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, err
+	}
+	return value, nil
+}
+
+// Len returns the number of entries, pinned ones included
+func (c *ARCCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.t1.Len() + c.t2.Len() + len(c.pinned)
+}
+
+// Cap returns the maximum number of entries the policy manages. Pinned
+// entries don't count against it.
+func (c *ARCCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *ARCCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.deleteKey(key)
+}
+
+// Pop removes key and returns the value it stored, or ErrKeyNotFound if key
+// is missing. The removal counts as a Delete, not as an access.
+func (c *ARCCache[K, V]) Pop(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, ok := c.peek(key)
+	if !ok {
+		return value, ErrKeyNotFound
+	}
+	return value, c.deleteKey(key)
+}
+
+// DeleteMany removes keys under a single lock and returns how many of them
+// were cached. Missing keys are skipped.
+func (c *ARCCache[K, V]) DeleteMany(keys []K) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	removed := 0
+	for _, key := range keys {
+		if c.deleteKey(key) == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// SetIf stores value for key only if key is cached and fn reports true for
+// its current value, counting as a single access. It returns ErrKeyNotFound
+// for a missing key and false without error when fn rejects the value. fn
+// runs while the cache is locked and must not call back into the cache.
+func (c *ARCCache[K, V]) SetIf(key K, value V, fn func(current V) bool) (bool, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	current, ok := c.peek(key)
+	if !ok {
+		return false, ErrKeyNotFound
+	}
+	if !fn(current) {
+		return false, nil
+	}
+	return true, c.set(key, value)
+}
+
+// DeleteIf removes key only if fn reports true for its current value. It
+// returns ErrKeyNotFound for a missing key and false without error when fn
+// rejects the value. fn runs while the cache is locked and must not call
+// back into the cache.
+func (c *ARCCache[K, V]) DeleteIf(key K, fn func(value V) bool) (bool, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, ok := c.peek(key)
+	if !ok {
+		return false, ErrKeyNotFound
+	}
+	if !fn(value) {
+		return false, nil
+	}
+	return true, c.deleteKey(key)
+}
+
+// SetPinned stores value for key and pins it: the policy never evicts a
+// pinned entry, and pinned entries don't take up the capacity left to the
+// others. At most capacity entries can be pinned at once; pinning more fails
+// with ErrTooManyPinned.
+func (c *ARCCache[K, V]) SetPinned(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.pinned[key]; !ok && len(c.pinned) >= c.capacity {
+		return ErrTooManyPinned
+	}
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+	if c.pinned == nil {
+		c.pinned = make(map[K]V)
+	}
+	c.pinned[key] = value
+	return nil
+}
+
+// Unpin hands a pinned entry back to the policy, inserting it as if by Set
+func (c *ARCCache[K, V]) Unpin(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, ok := c.pinned[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	delete(c.pinned, key)
+	return c.set(key, value)
+}
+
+// DeleteFunc removes every entry for which fn reports true in a single
+// locked pass and returns how many entries it removed. fn runs while the
+// cache is locked and must not call back into the cache.
+func (c *ARCCache[K, V]) DeleteFunc(fn func(key K, value V) bool) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var keys []K
+	for key := range c.items {
+		if value, ok := c.peek(key); ok && fn(key, value) {
+			keys = append(keys, key)
+		}
+	}
+	for key, value := range c.pinned {
+		if fn(key, value) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		_ = c.deleteKey(key)
+	}
+	return len(keys)
+}
+
+// SetOnMiss registers fn to be called with every key a Get or GetBatch
+// misses, including expired keys. fn runs after the cache is unlocked, so it
+// may block or call back into the cache.
+func (c *ARCCache[K, V]) SetOnMiss(fn func(key K)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onMiss = fn
+}
+
+// SetWatermark registers onHigh to be called when the share of the capacity
+// in use reaches highPct percent, and onLow when it then drops below lowPct
+// percent. Each callback fires once per crossing; onHigh can only fire again
+// after onLow did. Either may be nil. The callbacks run once the cache is
+// unlocked, so they may call back into the cache.
+func (c *ARCCache[K, V]) SetWatermark(highPct, lowPct float64, onHigh, onLow func()) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.watermark = watermark{high: highPct, low: lowPct, onHigh: onHigh, onLow: onLow}
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts, including entries moved to the ghost lists. Explicit Delete and
+// Clear calls are not reported. fn runs once the call that evicted the
+// entries has released the lock, so it may call back into the cache, for
+// instance to Delete related keys.
+func (c *ARCCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// Clear removes all entries and resets the adaptation
+func (c *ARCCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.reset()
+}
+
+// Resize changes the capacity to newCapacity, evicting resident entries
+// into the ghost lists as ARC would until the cache fits, and forgetting
+// the ghost keys beyond the new bounds. It fails with ErrTooManyPinned,
+// changing nothing, if more entries are pinned than newCapacity allows.
+func (c *ARCCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	capacity := max(newCapacity, 0)
+	if len(c.pinned) > capacity {
+		return ErrTooManyPinned
+	}
+	c.capacity = newCapacity
+	c.p = min(c.p, capacity)
+	for c.t1.Len()+c.t2.Len() > capacity {
+		c.makeRoom(false)
+	}
+	for c.b1.Len() > 0 && c.t1.Len()+c.b1.Len() > capacity {
+		c.removeElement(c.b1.Back())
+	}
+	for c.b2.Len() > 0 && c.t1.Len()+c.t2.Len()+c.b1.Len()+c.b2.Len() > 2*capacity {
+		c.removeElement(c.b2.Back())
+	}
+	return nil
+}
+
+// Replace atomically swaps the whole contents of the cache for entries,
+// inserted as if by Set, so readers see either the old or the new entries
+// but never a partially filled cache. A map has no order telling which of
+// its entries should survive an overflow, so more entries than the capacity
+// fail with ErrCacheFull and leave the cache untouched.
+func (c *ARCCache[K, V]) Replace(entries map[K]V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if len(entries) > max(c.capacity, 0) {
+		return ErrCacheFull
+	}
+	c.reset()
+	for key, value := range entries {
+		_ = c.set(key, value)
+	}
+	return nil
+}
+
+// EvictionEvents returns the stream of removed entries enabled by
+// WithEvictionEvents, or nil if the cache was created without it
+func (c *ARCCache[K, V]) EvictionEvents() <-chan EvictionEvent[K, V] {
+	return c.events
+}
+
+// TopKeys returns the n most accessed resident keys, most accessed first,
+// counting Set and Get hits since the key last entered the cache. It
+// requires WithAccessTracking and returns nil otherwise.
+func (c *ARCCache[K, V]) TopKeys(n int) []KeyCount[K] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.accesses == nil {
+		return nil
+	}
+	return topKeys(c.accesses.keyCounts(), n)
+}
+
+// ARCState is a snapshot of the adaptive state of an ARCCache. Each list
+// holds its keys in eviction order, the next to go first.
+type ARCState[K comparable] struct {
+	P  int // target size of T1
+	T1 []K // resident keys seen once recently
+	T2 []K // resident keys seen at least twice
+	B1 []K // ghost keys recently evicted from T1
+	B2 []K // ghost keys recently evicted from T2
+}
+
+// State returns the target size p and the contents of the four lists,
+// without counting as an access. Pinned entries are in none of them.
+func (c *ARCCache[K, V]) State() ARCState[K] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := func(l *list.List) []K {
+		keys := make([]K, 0, l.Len())
+		for elem := l.Back(); elem != nil; elem = elem.Prev() {
+			keys = append(keys, elem.Value.(*arcEntry[K, V]).key)
+		}
+		return keys
+	}
+	return ARCState[K]{P: c.p, T1: keys(c.t1), T2: keys(c.t2), B1: keys(c.b1), B2: keys(c.b2)}
+}
+
+// ordered returns the entries in eviction order
+func (c *ARCCache[K, V]) ordered() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, c.t1.Len()+c.t2.Len()+len(c.pinned))
+	c.walk(func(key K, value V) bool {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+		return true
+	})
+	return entries
+}
+
+// walk calls fn with the entries in eviction order until it returns false
+func (c *ARCCache[K, V]) walk(fn func(key K, value V) bool) {
+	visit := func(elem *list.Element) bool {
+		e := elem.Value.(*arcEntry[K, V])
+		return fn(e.key, e.value)
+	}
+
+	// t1 gives up the entries above its target first, then t2 empties
+	elem := c.t1.Back()
+	for n := c.t1.Len() - c.p; n > 0; n-- {
+		if !visit(elem) {
+			return
+		}
+		elem = elem.Prev()
+	}
+	for e := c.t2.Back(); e != nil; e = e.Prev() {
+		if !visit(e) {
+			return
+		}
+	}
+	for ; elem != nil; elem = elem.Prev() {
+		if !visit(elem) {
+			return
+		}
+	}
+	walkPinned(c.pinned, fn)
+}
+
+// makeRoom evicts one resident entry into its ghost list when the cache is
+// full, choosing t1 or t2 according to the target size p
+func (c *ARCCache[K, V]) makeRoom(inB2 bool) {
+	if c.t1.Len()+c.t2.Len() < c.capacity {
+		return
+	}
+	if c.t1.Len() > 0 && (c.t1.Len() > c.p || (c.t1.Len() == c.p && inB2)) {
+		elem := c.move(c.t1.Back(), c.b1)
+		c.report(elem.Value.(*arcEntry[K, V]))
+	} else if c.t2.Len() > 0 {
+		elem := c.move(c.t2.Back(), c.b2)
+		c.report(elem.Value.(*arcEntry[K, V]))
+	}
+}
+
+// growTarget returns the target size of t1 after a hit in the ghost list
+// b1, which grows it by the ratio of the ghost list sizes
+func growTarget(p, capacity, b1, b2 int) int {
+	return min(capacity, p+max(1, b2/b1))
+}
+
+// shrinkTarget returns the target size of t1 after a hit in the ghost list
+// b2, which shrinks it by the ratio of the ghost list sizes
+func shrinkTarget(p, b1, b2 int) int {
+	return max(0, p-max(1, b1/b2))
+}
+
+// move transfers elem to the front of the target list
+func (c *ARCCache[K, V]) move(elem *list.Element, target *list.List) *list.Element {
+	e := elem.Value.(*arcEntry[K, V])
+	if e.where == target {
+		target.MoveToFront(elem)
+		return elem
+	}
+	e.where.Remove(elem)
+	e.where = target
+	elem = target.PushFront(e)
+	c.items[e.key] = elem
+	return elem
+}
+
+// report passes an evicted entry to the eviction callback and drops its value
+func (c *ARCCache[K, V]) report(e *arcEntry[K, V]) {
+	value := e.value
+	e.value = *new(V)
+	delete(c.accesses, e.key)
+	c.events.emit(e.key, value, ReasonCapacity)
+	c.onEvict.report(e.key, value)
+}
+
+func (c *ARCCache[K, V]) resident(elem *list.Element) bool {
+	where := elem.Value.(*arcEntry[K, V]).where
+	return where == c.t1 || where == c.t2
+}
+
+func (c *ARCCache[K, V]) removeElement(elem *list.Element) {
+	e := elem.Value.(*arcEntry[K, V])
+	e.where.Remove(elem)
+	delete(c.items, e.key)
+	delete(c.accesses, e.key)
+}
+
+func (c *ARCCache[K, V]) get(key K) (V, error) {
+	if value, ok := c.pinned[key]; ok {
+		return value, nil
+	}
+	elem, ok := c.items[key]
+	if !ok || !c.resident(elem) {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	elem = c.move(elem, c.t2)
+	c.accesses.record(key)
+	return elem.Value.(*arcEntry[K, V]).value, nil
+}
+
+func (c *ARCCache[K, V]) set(key K, value V) error {
+	if _, ok := c.pinned[key]; ok {
+		c.pinned[key] = value
+		return nil
+	}
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*arcEntry[K, V])
+		switch e.where {
+		case c.t1, c.t2:
+			e.value = value
+			c.move(elem, c.t2)
+			c.accesses.record(key)
+			return nil
+		case c.b1:
+			c.p = growTarget(c.p, c.capacity, c.b1.Len(), c.b2.Len())
+			c.makeRoom(false)
+		case c.b2:
+			c.p = shrinkTarget(c.p, c.b1.Len(), c.b2.Len())
+			c.makeRoom(true)
+		}
+		e.value = value
+		c.move(elem, c.t2)
+		c.accesses.record(key)
+		return nil
+	}
+
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if c.t1.Len()+c.b1.Len() >= c.capacity {
+		if c.t1.Len() < c.capacity {
+			c.removeElement(c.b1.Back())
+			c.makeRoom(false)
+		} else {
+			e := c.t1.Back().Value.(*arcEntry[K, V])
+			c.removeElement(c.t1.Back())
+			c.report(e)
+		}
+	} else if c.t1.Len()+c.t2.Len()+c.b1.Len()+c.b2.Len() >= c.capacity {
+		if c.t1.Len()+c.t2.Len()+c.b1.Len()+c.b2.Len() >= 2*c.capacity {
+			c.removeElement(c.b2.Back())
+		}
+		c.makeRoom(false)
+	}
+	c.items[key] = c.t1.PushFront(&arcEntry[K, V]{key: key, value: value, where: c.t1})
+	c.accesses.record(key)
+	return nil
+}
+
+// peek returns the value of key without counting as an access
+func (c *ARCCache[K, V]) peek(key K) (V, bool) {
+	if value, ok := c.pinned[key]; ok {
+		return value, true
+	}
+	elem, ok := c.items[key]
+	if !ok || !c.resident(elem) {
+		var zero V
+		return zero, false
+	}
+	return elem.Value.(*arcEntry[K, V]).value, true
+}
+
+func (c *ARCCache[K, V]) deleteKey(key K) error {
+	if value, ok := c.pinned[key]; ok {
+		delete(c.pinned, key)
+		c.events.emit(key, value, ReasonDeleted)
+		return nil
+	}
+	elem, ok := c.items[key]
+	if !ok || !c.resident(elem) {
+		return ErrKeyNotFound
+	}
+	e := elem.Value.(*arcEntry[K, V])
+	c.removeElement(elem)
+	c.events.emit(e.key, e.value, ReasonDeleted)
+	return nil
+}
+
+// reset removes all entries
+func (c *ARCCache[K, V]) reset() {
+	if c.events != nil {
+		for key, value := range c.pinned {
+			c.events.emit(key, value, ReasonCleared)
+		}
+		for _, l := range []*list.List{c.t1, c.t2} {
+			for elem := l.Front(); elem != nil; elem = elem.Next() {
+				e := elem.Value.(*arcEntry[K, V])
+				c.events.emit(e.key, e.value, ReasonCleared)
+			}
+		}
+	}
+	c.p = 0
+	c.t1.Init()
+	c.t2.Init()
+	c.b1.Init()
+	c.b2.Init()
+	c.items = make(map[K]*list.Element)
+	c.pinned = nil
+	clear(c.accesses)
+}
+
+// unlock releases the lock, then reports the entries evicted while it was
+// held and any crossed watermark
+func (c *ARCCache[K, V]) unlock() {
+	crossed := c.watermark.check(c.t1.Len()+c.t2.Len(), c.capacity)
+	c.onEvict.unlock(&c.mu)
+	if crossed != nil {
+		crossed()
+	}
+}
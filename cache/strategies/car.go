@@ -0,0 +1,281 @@
+package strategies
+
+import (
+	"container/list"
+	"sync"
+)
+
+// CARCache implements CAR (Clock with Adaptive Replacement), the CLOCK
+// approximation of ARC. Like ARC it splits the capacity between t1, for
+// entries seen once recently, and t2, for entries seen at least twice,
+// adapting the target size p of t1 on hits in the ghost lists b1 and b2.
+// t1 and t2 are clocks rather than LRU lists: a hit only sets the
+// reference bit of an entry, and the hands of the clocks move entries when
+// room is needed.
+//
+// The clocks are lists whose front is under the hand; advancing the hand
+// past an entry moves it to the back. The ghost lists keep their most
+// recently evicted key in front.
+type CARCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	p        int // target size of t1
+	t1, t2   *list.List
+	b1, b2   *list.List
+	items    map[K]*list.Element // entries of t1, t2, b1 and b2
+	onEvict  evictHook[K, V]
+}
+
+// NewCARCache creates a CAR cache holding at most capacity entries
+func NewCARCache[K comparable, V any](capacity int) *CARCache[K, V] {
+	return &CARCache[K, V]{
+		capacity: capacity,
+		t1:       list.New(),
+		t2:       list.New(),
+		b1:       list.New(),
+		b2:       list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+// Get returns the value stored for key and sets its reference bit
+func (c *CARCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key. Updating a resident key sets its reference
+// bit; a key remembered in a ghost list adapts p and enters t2; any other
+// key enters t1.
+func (c *CARCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *CARCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all resident entries in no particular order,
+// without setting their reference bits
+func (c *CARCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, c.t1.Len()+c.t2.Len())
+	for key, elem := range c.items {
+		if c.resident(elem) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access.
+// Ghost keys don't count.
+func (c *CARCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	return ok && c.resident(elem)
+}
+
+// Len returns the number of entries, ghost keys left out
+func (c *CARCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.t1.Len() + c.t2.Len()
+}
+
+// Cap returns the maximum number of entries
+func (c *CARCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *CARCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok || !c.resident(elem) {
+		return ErrKeyNotFound
+	}
+	c.forget(elem)
+	return nil
+}
+
+// Clear removes all entries and forgets the ghost lists
+func (c *CARCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.p = 0
+	c.t1.Init()
+	c.t2.Init()
+	c.b1.Init()
+	c.b2.Init()
+	clear(c.items)
+}
+
+// Resize changes the capacity to newCapacity, moving the clock hands as Set
+// would until the cache fits, and forgetting the ghost keys beyond the new
+// bounds
+func (c *CARCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	capacity := max(newCapacity, 0)
+	c.capacity = newCapacity
+	c.p = min(c.p, capacity)
+	for c.t1.Len()+c.t2.Len() > capacity {
+		c.replace()
+	}
+	for c.b1.Len() > 0 && c.t1.Len()+c.b1.Len() > capacity {
+		c.forget(c.b1.Back())
+	}
+	for c.b2.Len() > 0 && c.t1.Len()+c.t2.Len()+c.b1.Len()+c.b2.Len() > 2*capacity {
+		c.forget(c.b2.Back())
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *CARCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// replace evicts one resident entry into its ghost list. The hand of t1
+// turns while t1 holds at least max(1, p) entries, that of t2 otherwise.
+// Referenced entries under the hand lose their bit: those of t1 move to
+// t2, those of t2 go round again.
+func (c *CARCache[K, V]) replace() {
+	for {
+		if c.t1.Len() >= max(1, c.p) {
+			elem := c.t1.Front()
+			e := elem.Value.(*arcEntry[K, V])
+			if !e.referenced {
+				c.demote(elem, c.b1)
+				return
+			}
+			c.t1.Remove(elem)
+			e.referenced, e.where = false, c.t2
+			c.items[e.key] = c.t2.PushBack(e)
+			continue
+		}
+		elem := c.t2.Front()
+		e := elem.Value.(*arcEntry[K, V])
+		if !e.referenced {
+			c.demote(elem, c.b2)
+			return
+		}
+		e.referenced = false
+		c.t2.MoveToBack(elem)
+	}
+}
+
+// demote evicts the resident elem into the ghost list
+func (c *CARCache[K, V]) demote(elem *list.Element, ghosts *list.List) {
+	e := elem.Value.(*arcEntry[K, V])
+	e.where.Remove(elem)
+	value := e.value
+	e.value, e.where = *new(V), ghosts
+	c.items[e.key] = ghosts.PushFront(e)
+	c.onEvict.report(e.key, value)
+}
+
+// forget drops elem from its list and from the cache
+func (c *CARCache[K, V]) forget(elem *list.Element) {
+	e := elem.Value.(*arcEntry[K, V])
+	e.where.Remove(elem)
+	delete(c.items, e.key)
+}
+
+func (c *CARCache[K, V]) resident(elem *list.Element) bool {
+	where := elem.Value.(*arcEntry[K, V]).where
+	return where == c.t1 || where == c.t2
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *CARCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *CARCache[K, V]) get(key K) (V, error) {
+	elem, ok := c.items[key]
+	if !ok || !c.resident(elem) {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	e := elem.Value.(*arcEntry[K, V])
+	e.referenced = true
+	return e.value, nil
+}
+
+func (c *CARCache[K, V]) set(key K, value V) error {
+	elem, ok := c.items[key]
+	if ok && c.resident(elem) {
+		e := elem.Value.(*arcEntry[K, V])
+		e.value = value
+		e.referenced = true
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+
+	if c.t1.Len()+c.t2.Len() >= c.capacity {
+		c.replace()
+		if !ok {
+			// Keep the directory within twice the capacity
+			if c.t1.Len()+c.b1.Len() >= c.capacity {
+				c.forget(c.b1.Back())
+			} else if c.t1.Len()+c.t2.Len()+c.b1.Len()+c.b2.Len() >= 2*c.capacity {
+				c.forget(c.b2.Back())
+			}
+		}
+	}
+
+	if !ok {
+		c.items[key] = c.t1.PushBack(&arcEntry[K, V]{key: key, value: value, where: c.t1})
+		return nil
+	}
+	e := elem.Value.(*arcEntry[K, V])
+	if e.where == c.b1 {
+		c.p = growTarget(c.p, c.capacity, c.b1.Len(), c.b2.Len())
+	} else {
+		c.p = shrinkTarget(c.p, c.b1.Len(), c.b2.Len())
+	}
+	e.where.Remove(elem)
+	e.value, e.referenced, e.where = value, false, c.t2
+	c.items[key] = c.t2.PushBack(e)
+	return nil
+}
@@ -0,0 +1,24 @@
+package strategies
+
+import "time"
+
+// Clock tells the current time to the caches measuring lifetimes
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the Clock backed by time.Now
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithClock makes a TTL cache, and its janitor, judge expiry by clk instead
+// of the system clock, e.g. to control time in tests. The janitor still
+// wakes up on the real time interval. Other caches ignore it.
+func WithClock(clk Clock) Option {
+	return func(o *options) {
+		o.clock = clk
+	}
+}
@@ -0,0 +1,249 @@
+package strategies
+
+import "sync"
+
+// ClockCache implements the CLOCK policy, an approximation of LRU. Entries
+// sit in a ring of slots, each with a reference bit set by Get. To make
+// room, a hand sweeps the ring, clearing set bits and evicting the first
+// entry whose bit is already clear, so an entry used since the last sweep
+// gets a second chance. Hits only set a bit, which is cheaper than moving
+// entries in a list.
+type ClockCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	items   map[K]int // slot of every entry
+	slots   []clockSlot[K, V]
+	free    []int // unused slots
+	hand    int
+	onEvict evictHook[K, V]
+}
+
+// clockSlot is a slot of the ring
+type clockSlot[K comparable, V any] struct {
+	key        K
+	value      V
+	referenced bool
+}
+
+// NewClockCache creates a CLOCK cache holding at most capacity entries
+func NewClockCache[K comparable, V any](capacity int) *ClockCache[K, V] {
+	capacity = max(capacity, 0)
+	c := &ClockCache[K, V]{
+		items: make(map[K]int, capacity),
+		slots: make([]clockSlot[K, V], capacity),
+		free:  make([]int, capacity),
+	}
+	for i := range c.free {
+		c.free[i] = capacity - 1 - i
+	}
+	return c
+}
+
+// Get returns the value stored for key and sets its reference bit
+func (c *ClockCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key. Updating an existing key sets its reference
+// bit; inserting a new key into a full cache evicts the entry under the
+// hand once the hand found one not referenced since its last pass.
+func (c *ClockCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *ClockCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all entries in no particular order, without
+// setting their reference bits
+func (c *ClockCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *ClockCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries
+func (c *ClockCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *ClockCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.slots)
+}
+
+// Delete removes key from the cache
+func (c *ClockCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	i, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	c.release(i)
+	return nil
+}
+
+// Clear removes all entries
+func (c *ClockCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for _, i := range c.items {
+		c.release(i)
+	}
+	c.hand = 0
+}
+
+// Resize changes the capacity to newCapacity. Shrinking sweeps the ring to
+// evict entries until the cache fits. The remaining entries then move to a
+// ring of the new size, keeping their order from the hand and their
+// reference bits.
+func (c *ClockCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	newCapacity = max(newCapacity, 0)
+	ring := make([]clockSlot[K, V], 0, len(c.items))
+	for j := range c.slots {
+		i := (c.hand + j) % len(c.slots)
+		if slot, ok := c.items[c.slots[i].key]; ok && slot == i {
+			ring = append(ring, c.slots[i])
+		}
+	}
+	// Sweep the gathered entries rather than the ring, where evict would
+	// take the slots freed by earlier evictions for entries
+	for len(ring) > newCapacity {
+		s := ring[0]
+		ring = ring[1:]
+		if s.referenced {
+			s.referenced = false
+			ring = append(ring, s)
+			continue
+		}
+		delete(c.items, s.key)
+		c.onEvict.report(s.key, s.value)
+	}
+	c.slots = make([]clockSlot[K, V], newCapacity)
+	for i, s := range ring {
+		c.slots[i] = s
+		c.items[s.key] = i
+	}
+	c.free = c.free[:0]
+	for i := newCapacity - 1; i >= len(ring); i-- {
+		c.free = append(c.free, i)
+	}
+	c.hand = 0
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *ClockCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// evict sweeps the full ring from the hand and frees the first slot whose
+// reference bit is clear, clearing the bits it passes. It ends within two
+// turns, since the first turn clears every bit.
+func (c *ClockCache[K, V]) evict() {
+	for {
+		s := &c.slots[c.hand]
+		i := c.hand
+		c.hand = (c.hand + 1) % len(c.slots)
+		if s.referenced {
+			s.referenced = false
+			continue
+		}
+		key, value := s.key, s.value
+		c.release(i)
+		c.onEvict.report(key, value)
+		return
+	}
+}
+
+// release empties slot i and makes it available again
+func (c *ClockCache[K, V]) release(i int) {
+	delete(c.items, c.slots[i].key)
+	c.slots[i] = clockSlot[K, V]{}
+	c.free = append(c.free, i)
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *ClockCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *ClockCache[K, V]) get(key K) (V, error) {
+	i, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	c.slots[i].referenced = true
+	return c.slots[i].value, nil
+}
+
+func (c *ClockCache[K, V]) set(key K, value V) error {
+	if i, ok := c.items[key]; ok {
+		c.slots[i].value = value
+		c.slots[i].referenced = true
+		return nil
+	}
+	if len(c.slots) == 0 {
+		return ErrCacheFull
+	}
+	if len(c.free) == 0 {
+		c.evict()
+	}
+	i := c.free[len(c.free)-1]
+	c.free = c.free[:len(c.free)-1]
+	c.slots[i] = clockSlot[K, V]{key: key, value: value}
+	c.items[key] = i
+	return nil
+}
@@ -0,0 +1,146 @@
+package strategies
+
+import (
+	"container/heap"
+	"container/list"
+	"maps"
+	"slices"
+)
+
+// The Clone methods copy a cache along with its eviction metadata, so that
+// the copy evicts exactly like the original would from then on. Settings
+// given to the constructor carry over, the copy getting an eviction event
+// stream of its own, whereas callbacks registered afterwards, RefreshAhead
+// loaders and the janitor of a TTL cache stay with the original. Values are
+// copied by assignment, so both caches share whatever they point to.
+
+// Clone returns a copy of the cache
+func (c *FIFOCache[K, V]) Clone() *FIFOCache[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clone := &FIFOCache[K, V]{
+		capacity: c.capacity,
+		frozen:   c.frozen,
+		events:   newEventStream[K, V](cap(c.events)),
+		overflow: c.overflow,
+		accesses: maps.Clone(c.accesses),
+		pinned:   maps.Clone(c.pinned),
+		items:    make(map[K]*list.Element, len(c.items)),
+		queue:    list.New(),
+	}
+	for elem := c.queue.Front(); elem != nil; elem = elem.Next() {
+		e := *elem.Value.(*entry[K, V])
+		clone.items[e.key] = clone.queue.PushBack(&e)
+	}
+	return clone
+}
+
+// Clone returns a copy of the cache
+func (c *LRUCache[K, V]) Clone() *LRUCache[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return &LRUCache[K, V]{
+		capacity: c.capacity,
+		frozen:   c.frozen,
+		events:   newEventStream[K, V](cap(c.events)),
+		overflow: c.overflow,
+		pinned:   maps.Clone(c.pinned),
+		items:    maps.Clone(c.items),
+		nodes:    slices.Clone(c.nodes),
+		free:     c.free,
+	}
+}
+
+// Clone returns a copy of the cache
+func (c *LFUCache[K, V]) Clone() *LFUCache[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clone := &LFUCache[K, V]{
+		capacity:    c.capacity,
+		frozen:      c.frozen,
+		events:      newEventStream[K, V](cap(c.events)),
+		overflow:    c.overflow,
+		decayPeriod: c.decayPeriod,
+		accesses:    c.accesses,
+		pinned:      maps.Clone(c.pinned),
+		items:       make(map[K]*list.Element, len(c.items)),
+		freqs:       list.New(),
+	}
+	for elem := c.freqs.Front(); elem != nil; elem = elem.Next() {
+		bucket := elem.Value.(*lfuBucket[K, V])
+		copied := clone.freqs.PushBack(&lfuBucket[K, V]{freq: bucket.freq, entries: list.New()})
+		for entry := bucket.entries.Front(); entry != nil; entry = entry.Next() {
+			e := *entry.Value.(*lfuEntry[K, V])
+			e.bucket = copied
+			clone.items[e.key] = copied.Value.(*lfuBucket[K, V]).entries.PushBack(&e)
+		}
+	}
+	return clone
+}
+
+// Clone returns a copy of the cache, including its ghost lists and the
+// target size of T1
+func (c *ARCCache[K, V]) Clone() *ARCCache[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clone := &ARCCache[K, V]{
+		capacity: c.capacity,
+		p:        c.p,
+		t1:       list.New(),
+		t2:       list.New(),
+		b1:       list.New(),
+		b2:       list.New(),
+		items:    make(map[K]*list.Element, len(c.items)),
+		events:   newEventStream[K, V](cap(c.events)),
+		overflow: c.overflow,
+		accesses: maps.Clone(c.accesses),
+		pinned:   maps.Clone(c.pinned),
+	}
+	copyList := func(from, to *list.List) {
+		for elem := from.Front(); elem != nil; elem = elem.Next() {
+			e := *elem.Value.(*arcEntry[K, V])
+			e.where = to
+			clone.items[e.key] = to.PushBack(&e)
+		}
+	}
+	copyList(c.t1, clone.t1)
+	copyList(c.t2, clone.t2)
+	copyList(c.b1, clone.b1)
+	copyList(c.b2, clone.b2)
+	return clone
+}
+
+// Clone returns a copy of the cache, including the entries that expired but
+// were not removed yet
+func (c *TTLCache[K, V]) Clone() *TTLCache[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clone := &TTLCache[K, V]{
+		capacity: c.capacity,
+		events:   newEventStream[K, V](cap(c.events)),
+		overflow: c.overflow,
+		ttl:      c.ttl,
+		maxTTL:   c.maxTTL,
+		grace:    c.grace,
+		items:    make(map[K]*list.Element, len(c.items)),
+		queue:    list.New(),
+		stop:     make(chan struct{}),
+		clock:    c.clock,
+		fifo:     c.fifo,
+	}
+	for elem := c.queue.Front(); elem != nil; elem = elem.Next() {
+		e := *elem.Value.(*ttlEntry[K, V])
+		clone.items[e.key] = clone.queue.PushBack(&e)
+		clone.expiries = append(clone.expiries, &e)
+	}
+	for i, e := range clone.expiries {
+		e.index = i
+	}
+	heap.Init(&clone.expiries)
+	return clone
+}
@@ -0,0 +1,146 @@
+package strategies
+
+import (
+	"container/list"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The String methods render the contents of a cache for debugging. Entries
+// are listed in eviction order, the next victim first, so that failing
+// tests show why an entry was or wasn't evicted. Pinned entries come last,
+// sorted by their rendering to keep the output stable, and are left out of
+// the count of entries since they take up no capacity.
+
+// String renders the queue, oldest entry first, e.g.
+// "FIFO 2/3 [a=1 b=2]"
+func (c *FIFOCache[K, V]) String() string {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "FIFO %d/%d [", c.queue.Len(), c.capacity)
+	for elem := c.queue.Front(); elem != nil; elem = elem.Next() {
+		e := elem.Value.(*entry[K, V])
+		writeEntry(&b, elem != c.queue.Front(), e.key, e.value)
+	}
+	b.WriteString("]")
+	writePinned(&b, c.pinned)
+	return b.String()
+}
+
+// String renders the recency list, least recently used entry first, e.g.
+// "LRU 2/3 [a=1 b=2]"
+func (c *LRUCache[K, V]) String() string {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "LRU %d/%d [", len(c.items), c.capacity)
+	for i := c.nodes[0].prev; i != 0; i = c.nodes[i].prev {
+		writeEntry(&b, i != c.nodes[0].prev, c.nodes[i].key, c.nodes[i].value)
+	}
+	b.WriteString("]")
+	writePinned(&b, c.pinned)
+	return b.String()
+}
+
+// String renders the frequency buckets, lowest frequency first, each listing
+// its least recently used entry first, e.g. "LFU 3/3 [f1: a=1 c=3 | f2: b=2]"
+func (c *LFUCache[K, V]) String() string {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "LFU %d/%d [", len(c.items), c.capacity)
+	for elem := c.freqs.Front(); elem != nil; elem = elem.Next() {
+		bucket := elem.Value.(*lfuBucket[K, V])
+		if elem != c.freqs.Front() {
+			b.WriteString(" | ")
+		}
+		fmt.Fprintf(&b, "f%d:", bucket.freq)
+		for e := bucket.entries.Back(); e != nil; e = e.Prev() {
+			entry := e.Value.(*lfuEntry[K, V])
+			writeEntry(&b, true, entry.key, entry.value)
+		}
+	}
+	b.WriteString("]")
+	writePinned(&b, c.pinned)
+	return b.String()
+}
+
+// String renders the target size p and the resident lists T1 and T2 followed
+// by the ghost lists B1 and B2, each least recently used first, e.g.
+// "ARC 2/2 p=0 T1[c=3] T2[a=1] B1[b] B2[]"
+func (c *ARCCache[K, V]) String() string {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "ARC %d/%d p=%d", c.t1.Len()+c.t2.Len(), c.capacity, c.p)
+	for _, l := range []struct {
+		name  string
+		list  *list.List
+		ghost bool
+	}{{"T1", c.t1, false}, {"T2", c.t2, false}, {"B1", c.b1, true}, {"B2", c.b2, true}} {
+		fmt.Fprintf(&b, " %s[", l.name)
+		for elem := l.list.Back(); elem != nil; elem = elem.Prev() {
+			e := elem.Value.(*arcEntry[K, V])
+			if elem != l.list.Back() {
+				b.WriteString(" ")
+			}
+			if l.ghost {
+				fmt.Fprint(&b, e.key)
+			} else {
+				fmt.Fprintf(&b, "%v=%v", e.key, e.value)
+			}
+		}
+		b.WriteString("]")
+	}
+	writePinned(&b, c.pinned)
+	return b.String()
+}
+
+// String renders the entries in queue order together with the time left
+// before they expire, e.g. "TTL 2/3 [a=1 (4s) b=2 (expired)]"
+func (c *TTLCache[K, V]) String() string {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "TTL %d/%d [", c.queue.Len(), c.capacity)
+	for elem := c.queue.Front(); elem != nil; elem = elem.Next() {
+		e := elem.Value.(*ttlEntry[K, V])
+		writeEntry(&b, elem != c.queue.Front(), e.key, e.value)
+		if e.expired(now) {
+			b.WriteString(" (expired)")
+		} else {
+			fmt.Fprintf(&b, " (%v)", e.expiresAt.Sub(now))
+		}
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// writeEntry renders key=value, preceded by a space if sep is set
+func writeEntry[K comparable, V any](b *strings.Builder, sep bool, key K, value V) {
+	if sep {
+		b.WriteString(" ")
+	}
+	fmt.Fprintf(b, "%v=%v", key, value)
+}
+
+// writePinned renders the pinned entries, if any
+func writePinned[K comparable, V any](b *strings.Builder, pinned map[K]V) {
+	if len(pinned) == 0 {
+		return
+	}
+	entries := make([]string, 0, len(pinned))
+	for key, value := range pinned {
+		entries = append(entries, fmt.Sprintf("%v=%v", key, value))
+	}
+	sort.Strings(entries)
+	fmt.Fprintf(b, " pinned[%s]", strings.Join(entries, " "))
+}
@@ -0,0 +1,56 @@
+package strategies
+
+import "time"
+
+// entry is a key/value pair stored in the lists of the strategies
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// Entry is a key/value pair returned by the strategies
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// entryKeys returns the keys of entries in the same order
+func entryKeys[K comparable, V any](entries []Entry[K, V]) []K {
+	keys := make([]K, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+	return keys
+}
+
+// entryValues returns the values of entries in the same order
+func entryValues[K comparable, V any](entries []Entry[K, V]) []V {
+	values := make([]V, len(entries))
+	for i, e := range entries {
+		values[i] = e.Value
+	}
+	return values
+}
+
+// walkPinned calls fn with the pinned entries in no particular order until
+// it returns false
+func walkPinned[K comparable, V any](pinned map[K]V, fn func(key K, value V) bool) {
+	for key, value := range pinned {
+		if !fn(key, value) {
+			return
+		}
+	}
+}
+
+// Info holds metadata about a cached entry. AccessCount counts the Set and
+// Get calls that hit the entry, including the one inserting it.
+type Info struct {
+	CreatedAt      time.Time
+	LastAccessedAt time.Time
+	AccessCount    uint64
+}
+
+func (info *Info) touch(now time.Time) {
+	info.LastAccessedAt = now
+	info.AccessCount++
+}
@@ -0,0 +1,19 @@
+package strategies
+
+import "errors"
+
+// Common errors returned by all strategies
+var (
+	ErrKeyNotFound = errors.New("key not found")
+	ErrCacheFull   = errors.New("cache is full")
+)
+
+// ErrTooManyPinned is returned by SetPinned when capacity entries are
+// already pinned
+var ErrTooManyPinned = errors.New("too many pinned entries")
+
+// ErrKeyOutOfRange is returned by IntFIFOCache for keys outside its key space
+var ErrKeyOutOfRange = errors.New("key out of range")
+
+// ErrKeyExists is returned by Add for a key that is already cached
+var ErrKeyExists = errors.New("key already exists")
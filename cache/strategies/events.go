@@ -0,0 +1,103 @@
+package strategies
+
+import "sync"
+
+// Reason tells why an entry left a cache
+type Reason int
+
+// Removal reasons reported by eviction events
+const (
+	ReasonCapacity Reason = iota
+	ReasonExpired
+	ReasonDeleted
+	ReasonCleared
+)
+
+// String returns the name of the reason
+func (r Reason) String() string {
+	switch r {
+	case ReasonCapacity:
+		return "capacity"
+	case ReasonExpired:
+		return "expired"
+	case ReasonDeleted:
+		return "deleted"
+	case ReasonCleared:
+		return "cleared"
+	default:
+		return "unknown"
+	}
+}
+
+// EvictionEvent describes an entry removed from a cache
+type EvictionEvent[K comparable, V any] struct {
+	Key    K
+	Value  V
+	Reason Reason
+}
+
+// WithEvictionEvents enables the stream returned by EvictionEvents, buffering
+// up to buffer events. When the buffer is full the oldest event is dropped.
+func WithEvictionEvents(buffer int) Option {
+	return func(o *options) {
+		o.eventBuffer = max(buffer, 1)
+	}
+}
+
+// eventStream delivers eviction events without ever blocking the cache
+type eventStream[K comparable, V any] chan EvictionEvent[K, V]
+
+func newEventStream[K comparable, V any](buffer int) eventStream[K, V] {
+	if buffer <= 0 {
+		return nil
+	}
+	return make(eventStream[K, V], buffer)
+}
+
+// emit sends an event, dropping the oldest buffered one if the buffer is full
+func (s eventStream[K, V]) emit(key K, value V, reason Reason) {
+	if s == nil {
+		return
+	}
+	event := EvictionEvent[K, V]{Key: key, Value: value, Reason: reason}
+	for {
+		select {
+		case s <- event:
+			return
+		default:
+		}
+		select {
+		case <-s:
+		default:
+		}
+	}
+}
+
+// evictHook queues the entries evicted while a cache is locked and reports
+// them to the eviction callback once the lock is released, so that the
+// callback may call back into the cache
+type evictHook[K comparable, V any] struct {
+	fn      func(key K, value V)
+	pending []entry[K, V]
+}
+
+// report queues an evicted entry if a callback is registered
+func (h *evictHook[K, V]) report(key K, value V) {
+	if h.fn != nil {
+		h.pending = append(h.pending, entry[K, V]{key: key, value: value})
+	}
+}
+
+// unlock releases mu, then passes the queued entries to the callback
+func (h *evictHook[K, V]) unlock(mu *sync.Mutex) {
+	if len(h.pending) == 0 {
+		mu.Unlock()
+		return
+	}
+	fn, pending := h.fn, h.pending
+	h.pending = nil
+	mu.Unlock()
+	for _, e := range pending {
+		fn(e.key, e.value)
+	}
+}
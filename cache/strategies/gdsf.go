@@ -0,0 +1,281 @@
+package strategies
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// GDSFCache implements GreedyDual-Size-Frequency. Every entry carries a size
+// and a cost, and the capacity bounds the total size rather than the number
+// of entries. The entry with the lowest priority
+//
+//	L + frequency * cost / size
+//
+// is evicted first, where the inflation term L is the priority of the last
+// evicted entry. Small, costly and frequently read entries are kept over
+// large, cheap ones, and L lets new entries eventually displace entries that
+// were hot long ago.
+type GDSFCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int64
+	used     int64
+	inflate  float64
+	seq      uint64
+	items    map[K]*gdsfEntry[K, V]
+	queue    gdsfHeap[K, V]
+	onEvict  evictHook[K, V]
+}
+
+// gdsfEntry is an entry together with its GDSF priority
+type gdsfEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	size     int64
+	cost     float64
+	freq     int
+	priority float64
+	seq      uint64 // breaks priority ties, oldest update first
+	index    int    // position in the queue, -1 when not queued
+}
+
+// NewGDSFCache creates a GDSF cache holding entries up to a total size of
+// capacity
+func NewGDSFCache[K comparable, V any](capacity int64) *GDSFCache[K, V] {
+	return &GDSFCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*gdsfEntry[K, V]),
+	}
+}
+
+// Get returns the value stored for key and raises its priority
+func (c *GDSFCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key with a size and a cost of 1
+func (c *GDSFCache[K, V]) Set(key K, value V) error {
+	return c.SetWithCost(key, value, 1, 1)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value with a size and a cost of 1 and returns it. loaded reports
+// whether the value was already stored. Either way it counts as a single
+// access.
+func (c *GDSFCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.setWithCost(key, value, 1, 1); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// SetWithCost stores value for key with the given size and cost, evicting
+// the entries with the lowest priority until it fits. Updating an existing
+// key counts as a read. It fails with ErrCacheFull for sizes below 1 or
+// above the capacity, leaving any previous value of key untouched.
+func (c *GDSFCache[K, V]) SetWithCost(key K, value V, size int64, cost float64) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.setWithCost(key, value, size, cost)
+}
+
+// Keys returns the keys of all entries in no particular order, without
+// counting as a read
+func (c *GDSFCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Len returns the number of entries
+func (c *GDSFCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the total size the entries may take, not a number of entries
+func (c *GDSFCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return int(c.capacity)
+}
+
+// Size returns the total size of the entries
+func (c *GDSFCache[K, V]) Size() int64 {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.used
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *GDSFCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Delete removes key from the cache
+func (c *GDSFCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	c.remove(e)
+	return nil
+}
+
+// Clear removes all entries and resets the inflation term
+func (c *GDSFCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+	c.queue = c.queue[:0]
+	c.used = 0
+	c.inflate = 0
+}
+
+// Resize changes the capacity to a total size of newCapacity, evicting the
+// entries with the lowest priority until the remaining ones fit
+func (c *GDSFCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = int64(newCapacity)
+	for c.used > max(c.capacity, 0) {
+		c.evict()
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *GDSFCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// prioritize recomputes the priority of e against the current inflation
+// term, fixing its place in the queue if it is there
+func (c *GDSFCache[K, V]) prioritize(e *gdsfEntry[K, V]) {
+	c.seq++
+	e.seq = c.seq
+	e.priority = c.inflate + float64(e.freq)*e.cost/float64(e.size)
+	if e.index >= 0 {
+		heap.Fix(&c.queue, e.index)
+	}
+}
+
+// evict removes the entry with the lowest priority, raising the inflation
+// term to its priority, and reports it
+func (c *GDSFCache[K, V]) evict() {
+	e := heap.Pop(&c.queue).(*gdsfEntry[K, V])
+	delete(c.items, e.key)
+	c.used -= e.size
+	c.inflate = e.priority
+	c.onEvict.report(e.key, e.value)
+}
+
+// remove drops e from the cache without reporting it
+func (c *GDSFCache[K, V]) remove(e *gdsfEntry[K, V]) {
+	heap.Remove(&c.queue, e.index)
+	delete(c.items, e.key)
+	c.used -= e.size
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *GDSFCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *GDSFCache[K, V]) get(key K) (V, error) {
+	e, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	e.freq++
+	c.prioritize(e)
+	return e.value, nil
+}
+
+func (c *GDSFCache[K, V]) setWithCost(key K, value V, size int64, cost float64) error {
+	if size < 1 || size > c.capacity {
+		return ErrCacheFull
+	}
+	freq := 1
+	if e, ok := c.items[key]; ok {
+		freq = e.freq + 1
+		c.remove(e)
+	}
+	for c.used+size > c.capacity {
+		c.evict()
+	}
+	e := &gdsfEntry[K, V]{key: key, value: value, size: size, cost: cost, freq: freq, index: -1}
+	c.items[key] = e
+	c.used += size
+	c.prioritize(e)
+	heap.Push(&c.queue, e)
+	return nil
+}
+
+// gdsfHeap is a min-heap of entries by priority implementing heap.Interface
+type gdsfHeap[K comparable, V any] []*gdsfEntry[K, V]
+
+func (h gdsfHeap[K, V]) Len() int { return len(h) }
+
+func (h gdsfHeap[K, V]) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority < h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h gdsfHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *gdsfHeap[K, V]) Push(x any) {
+	e := x.(*gdsfEntry[K, V])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *gdsfHeap[K, V]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*h = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,49 @@
+package strategies
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// hashKey hashes keys of any comparable type for the frequency sketches.
+// Hashes are deterministic, so evictions depending on them can be
+// reproduced. Strings and integers are hashed directly; other keys are
+// hashed through their Go syntax representation, which is slower but equal
+// for equal keys.
+func hashKey(key any) uint64 {
+	switch k := key.(type) {
+	case string:
+		return hashString(k)
+	case int:
+		return mix(uint64(k))
+	case int64:
+		return mix(uint64(k))
+	case int32:
+		return mix(uint64(k))
+	case uint:
+		return mix(uint64(k))
+	case uint64:
+		return mix(k)
+	case uint32:
+		return mix(uint64(k))
+	default:
+		return hashString(fmt.Sprintf("%#v", key))
+	}
+}
+
+// hashString returns the FNV-1a hash of s
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return mix(h.Sum64())
+}
+
+// mix scrambles the bits of x with the SplitMix64 finalizer
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
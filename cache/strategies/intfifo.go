@@ -0,0 +1,232 @@
+package strategies
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// noSlot marks the end of the queue
+const noSlot = -1
+
+// IntFIFOCache is a FIFO cache specialized for dense int keys in
+// [0, keySpace). Entries live in arrays indexed by key and presence is
+// tracked in a bitset, so no operation allocates and memory is proportional
+// to the key space rather than to the capacity.
+type IntFIFOCache[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	size     int
+	present  []uint64 // bit k is set when key k is cached
+	values   []V
+	next     []int32 // next newer key in the queue
+	prev     []int32 // next older key in the queue
+	oldest   int32
+	newest   int32
+	onEvict  evictHook[int, V]
+}
+
+// NewIntFIFOCache creates a FIFO cache holding at most capacity entries
+// whose keys lie in [0, keySpace)
+func NewIntFIFOCache[V any](capacity, keySpace int) *IntFIFOCache[V] {
+	return &IntFIFOCache[V]{
+		capacity: capacity,
+		present:  make([]uint64, (keySpace+63)/64),
+		values:   make([]V, keySpace),
+		next:     make([]int32, keySpace),
+		prev:     make([]int32, keySpace),
+		oldest:   noSlot,
+		newest:   noSlot,
+	}
+}
+
+// Get returns the value stored for key
+func (c *IntFIFOCache[V]) Get(key int) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key. Updating an existing key keeps its position in
+// the queue; inserting a new key into a full cache evicts the oldest entry.
+func (c *IntFIFOCache[V]) Set(key int, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *IntFIFOCache[V]) GetOrSet(key int, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Contains reports whether key is stored. Keys out of range are never
+// stored.
+func (c *IntFIFOCache[V]) Contains(key int) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return key >= 0 && key < len(c.values) && c.has(key)
+}
+
+// Len returns the number of entries
+func (c *IntFIFOCache[V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.size
+}
+
+// Cap returns the maximum number of entries
+func (c *IntFIFOCache[V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *IntFIFOCache[V]) Delete(key int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if key < 0 || key >= len(c.values) {
+		return ErrKeyOutOfRange
+	}
+	if !c.has(key) {
+		return ErrKeyNotFound
+	}
+	c.remove(key)
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *IntFIFOCache[V]) SetEvictCallback(fn func(key int, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// Clear removes all entries
+func (c *IntFIFOCache[V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for i, word := range c.present {
+		for word != 0 {
+			var zero V
+			c.values[i*64+bits.TrailingZeros64(word)] = zero
+			word &= word - 1
+		}
+		c.present[i] = 0
+	}
+	c.oldest, c.newest = noSlot, noSlot
+	c.size = 0
+}
+
+// Resize changes the capacity to newCapacity, evicting the oldest entries
+// until the cache fits. The key space stays as it is.
+func (c *IntFIFOCache[V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	for c.size > max(c.capacity, 0) {
+		c.evict()
+	}
+	return nil
+}
+
+// has reports whether key is cached
+func (c *IntFIFOCache[V]) has(key int) bool {
+	return c.present[key/64]&(1<<(key%64)) != 0
+}
+
+// evict removes the oldest entry and reports it
+func (c *IntFIFOCache[V]) evict() {
+	oldest := int(c.oldest)
+	c.onEvict.report(oldest, c.values[oldest])
+	c.remove(oldest)
+}
+
+// remove unlinks the cached key from the queue
+func (c *IntFIFOCache[V]) remove(key int) {
+	prev, next := c.prev[key], c.next[key]
+	if prev != noSlot {
+		c.next[prev] = next
+	} else {
+		c.oldest = next
+	}
+	if next != noSlot {
+		c.prev[next] = prev
+	} else {
+		c.newest = prev
+	}
+
+	var zero V
+	c.values[key] = zero
+	c.present[key/64] &^= 1 << (key % 64)
+	c.size--
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *IntFIFOCache[V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *IntFIFOCache[V]) get(key int) (V, error) {
+	var zero V
+	if key < 0 || key >= len(c.values) {
+		return zero, ErrKeyOutOfRange
+	}
+	if !c.has(key) {
+		return zero, ErrKeyNotFound
+	}
+	return c.values[key], nil
+}
+
+func (c *IntFIFOCache[V]) set(key int, value V) error {
+	if key < 0 || key >= len(c.values) {
+		return ErrKeyOutOfRange
+	}
+	if c.has(key) {
+		c.values[key] = value
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if c.size >= c.capacity {
+		c.evict()
+	}
+
+	c.values[key] = value
+	c.present[key/64] |= 1 << (key % 64)
+	c.prev[key] = c.newest
+	c.next[key] = noSlot
+	if c.newest != noSlot {
+		c.next[c.newest] = int32(key)
+	} else {
+		c.oldest = int32(key)
+	}
+	c.newest = int32(key)
+	c.size++
+	return nil
+}
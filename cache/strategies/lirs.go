@@ -0,0 +1,364 @@
+package strategies
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LIRSCache implements the LIRS (Low Inter-reference Recency Set) policy.
+// Entries are ranked by their reuse distance, the number of distinct keys
+// accessed between their last two accesses. Most of the capacity goes to
+// the LIR entries, those with a short reuse distance; the few remaining
+// slots hold HIR entries, which are evicted first.
+//
+// The stack S orders recently accessed keys, LIR, resident HIR and
+// non-resident HIR alike, by recency, and always has an LIR entry at its
+// bottom. A HIR entry accessed again while still in S has a shorter reuse
+// distance than the oldest LIR entry, so the two swap status. The queue Q
+// lists the resident HIR entries in eviction order. A HIR entry evicted
+// while in S stays there as a non-resident key without a value; at most
+// capacity such keys are remembered.
+type LIRSCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	lirCap   int
+	lirCount int
+	items    map[K]*lirsEntry[K, V] // resident entries and non-resident keys in S
+	stack    *list.List             // S, front is the most recently accessed key
+	queue    *list.List             // Q, front is the newest resident HIR entry
+	ghosts   *list.List             // non-resident keys in S, front is the newest
+	onEvict  evictHook[K, V]
+}
+
+// lirsEntry is an entry together with its status and its positions in S, Q
+// and the list of non-resident keys
+type lirsEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	lir      bool
+	resident bool
+	inStack  *list.Element
+	inQueue  *list.Element
+	inGhosts *list.Element
+}
+
+// NewLIRSCache creates a LIRS cache holding at most capacity entries, about
+// 1% of which, and at least one unless capacity is 1, are reserved for HIR
+// entries
+func NewLIRSCache[K comparable, V any](capacity int) *LIRSCache[K, V] {
+	return &LIRSCache[K, V]{
+		capacity: capacity,
+		lirCap:   max(capacity-max(capacity/100, 1), 1),
+		items:    make(map[K]*lirsEntry[K, V]),
+		stack:    list.New(),
+		queue:    list.New(),
+		ghosts:   list.New(),
+	}
+}
+
+// Get returns the value stored for key and records the access
+func (c *LIRSCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key and records the access. Inserting a new key into
+// a full cache evicts the oldest resident HIR entry.
+func (c *LIRSCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *LIRSCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all resident entries in no particular order,
+// without counting as an access
+func (c *LIRSCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, c.lirCount+c.queue.Len())
+	for key, e := range c.items {
+		if e.resident {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access.
+// Non-resident keys don't count.
+func (c *LIRSCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, ok := c.items[key]
+	return ok && e.resident
+}
+
+// Len returns the number of resident entries
+func (c *LIRSCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.lirCount + c.queue.Len()
+}
+
+// Cap returns the maximum number of entries
+func (c *LIRSCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *LIRSCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	c.remove(e)
+	c.prune()
+	if !e.resident {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+// Clear removes all entries and forgets the non-resident keys
+func (c *LIRSCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+	c.stack.Init()
+	c.queue.Init()
+	c.ghosts.Init()
+	c.lirCount = 0
+}
+
+// Resize changes the capacity to newCapacity and resizes the LIR set as
+// NewLIRSCache does. Surplus LIR entries are demoted from the bottom of S,
+// then the oldest resident HIR entries are evicted until the cache fits
+// and the oldest non-resident keys beyond the new bound are forgotten.
+func (c *LIRSCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	c.lirCap = max(newCapacity-max(newCapacity/100, 1), 1)
+	for c.lirCount > c.lirCap {
+		c.demoteBottom()
+	}
+	for c.lirCount+c.queue.Len() > max(c.capacity, 0) {
+		c.evict()
+	}
+	for c.ghosts.Len() > max(c.capacity, 0) {
+		c.remove(c.ghosts.Back().Value.(*lirsEntry[K, V]))
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *LIRSCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// hit records an access to the resident entry e
+func (c *LIRSCache[K, V]) hit(e *lirsEntry[K, V]) {
+	switch {
+	case e.lir:
+		c.pushStack(e)
+		c.prune()
+	case e.inStack != nil || c.lirCount < c.lirCap:
+		// Reused within S, or the LIR set has room after deletes
+		c.queue.Remove(e.inQueue)
+		e.inQueue = nil
+		e.lir = true
+		c.lirCount++
+		c.pushStack(e)
+		c.balance()
+	default:
+		c.pushStack(e)
+		c.queue.MoveToFront(e.inQueue)
+	}
+}
+
+// pushStack moves e to the top of S
+func (c *LIRSCache[K, V]) pushStack(e *lirsEntry[K, V]) {
+	if e.inStack != nil {
+		c.stack.MoveToFront(e.inStack)
+		return
+	}
+	e.inStack = c.stack.PushFront(e)
+}
+
+// balance demotes the oldest LIR entry if there is one too many
+func (c *LIRSCache[K, V]) balance() {
+	if c.lirCount > c.lirCap {
+		c.demoteBottom()
+	}
+}
+
+// demoteBottom turns the LIR entry at the bottom of S into a resident HIR
+// entry, then prunes S
+func (c *LIRSCache[K, V]) demoteBottom() {
+	e := c.stack.Remove(c.stack.Back()).(*lirsEntry[K, V])
+	e.inStack = nil
+	e.lir = false
+	c.lirCount--
+	e.inQueue = c.queue.PushFront(e)
+	c.prune()
+}
+
+// prune pops HIR keys off the bottom of S until an LIR entry is there,
+// forgetting the non-resident ones
+func (c *LIRSCache[K, V]) prune() {
+	for elem := c.stack.Back(); elem != nil; elem = c.stack.Back() {
+		e := elem.Value.(*lirsEntry[K, V])
+		if e.lir {
+			return
+		}
+		c.stack.Remove(elem)
+		e.inStack = nil
+		if !e.resident {
+			c.forgetGhost(e)
+			delete(c.items, e.key)
+		}
+	}
+}
+
+// evict removes the oldest resident HIR entry, keeping its key as
+// non-resident if it is still in S. Without resident HIR entries, which
+// happens when capacity is 1, the oldest LIR entry is demoted first.
+func (c *LIRSCache[K, V]) evict() {
+	if c.queue.Len() == 0 {
+		c.demoteBottom()
+	}
+	e := c.queue.Remove(c.queue.Back()).(*lirsEntry[K, V])
+	e.inQueue = nil
+	c.onEvict.report(e.key, e.value)
+	if e.inStack == nil {
+		delete(c.items, e.key)
+		return
+	}
+
+	var zero V
+	e.value, e.resident = zero, false
+	e.inGhosts = c.ghosts.PushFront(e)
+	if c.ghosts.Len() > c.capacity {
+		c.remove(c.ghosts.Back().Value.(*lirsEntry[K, V]))
+	}
+}
+
+// remove drops e from every structure
+func (c *LIRSCache[K, V]) remove(e *lirsEntry[K, V]) {
+	if e.inStack != nil {
+		c.stack.Remove(e.inStack)
+		e.inStack = nil
+	}
+	if e.inQueue != nil {
+		c.queue.Remove(e.inQueue)
+		e.inQueue = nil
+	}
+	c.forgetGhost(e)
+	if e.lir {
+		c.lirCount--
+	}
+	delete(c.items, e.key)
+}
+
+// forgetGhost drops e from the list of non-resident keys
+func (c *LIRSCache[K, V]) forgetGhost(e *lirsEntry[K, V]) {
+	if e.inGhosts != nil {
+		c.ghosts.Remove(e.inGhosts)
+		e.inGhosts = nil
+	}
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *LIRSCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *LIRSCache[K, V]) get(key K) (V, error) {
+	e, ok := c.items[key]
+	if !ok || !e.resident {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	c.hit(e)
+	return e.value, nil
+}
+
+func (c *LIRSCache[K, V]) set(key K, value V) error {
+	e, ok := c.items[key]
+	if ok && e.resident {
+		e.value = value
+		c.hit(e)
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if c.lirCount+c.queue.Len() >= c.capacity {
+		c.evict()
+		// The victim may have been the last trace of a non-resident key
+		e, ok = c.items[key]
+	}
+	if c.lirCount < c.lirCap {
+		// Warming up, or refilling after deletes: the LIR set fills first
+		if !ok {
+			e = &lirsEntry[K, V]{key: key}
+			c.items[key] = e
+		}
+		c.forgetGhost(e)
+		e.value, e.resident, e.lir = value, true, true
+		c.lirCount++
+		c.pushStack(e)
+		return nil
+	}
+	if ok {
+		// Reused within S: the reuse distance beats the oldest LIR entry
+		c.forgetGhost(e)
+		e.value, e.resident, e.lir = value, true, true
+		c.lirCount++
+		c.pushStack(e)
+		c.balance()
+		return nil
+	}
+	e = &lirsEntry[K, V]{key: key, value: value, resident: true}
+	c.items[key] = e
+	c.pushStack(e)
+	e.inQueue = c.queue.PushFront(e)
+	return nil
+}
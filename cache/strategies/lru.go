@@ -1,6 +1,769 @@
 package strategies
 
+import (
+	"sync"
+	"time"
+)
+
 // LRUCache implements a Least Recently Used cache
+//
+// Entries are kept in an index-based doubly-linked list stored in a slice.
+// Slot 0 is the sentinel of the circular list; released slots are chained in
+// a free list and reused, so hits and steady-state evictions don't allocate.
 type LRUCache[K comparable, V any] struct {
-	// TODO: Add necessary fields for LRU implementation
+	mu        sync.Mutex
+	capacity  int
+	frozen    bool // evictions are suspended
+	items     map[K]int
+	nodes     []lruNode[K, V] // sentinel.next is the most recently used entry
+	free      int             // head of the free list, 0 if empty
+	onEvict   evictHook[K, V]
+	onMiss    func(key K)
+	watermark watermark
+	pinned    map[K]V // entries kept out of reach of the policy
+	events    eventStream[K, V]
+	overflow  OverflowPolicy
+}
+
+// lruNode is a slot of the recency list
+type lruNode[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next int
+	info       Info
+}
+
+// NewLRUCache creates an LRU cache holding at most capacity entries
+func NewLRUCache[K comparable, V any](capacity int, opts ...Option) *LRUCache[K, V] {
+	o := newOptions(opts)
+	return &LRUCache[K, V]{
+		capacity: capacity,
+		events:   newEventStream[K, V](o.eventBuffer),
+		overflow: o.overflow,
+		items:    make(map[K]int, max(capacity, 0)),
+		nodes:    make([]lruNode[K, V], 1, max(capacity, 0)+1),
+	}
+}
+
+// Get returns the value stored for key and marks it as most recently used
+func (c *LRUCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	value, err := c.get(key)
+	onMiss := c.onMiss
+	c.unlock()
+
+	if err != nil && onMiss != nil {
+		onMiss(key)
+	}
+	return value, err
+}
+
+// GetBatch looks up all keys under a single lock. The results are aligned
+// with keys, including duplicates; misses carry ErrKeyNotFound.
+func (c *LRUCache[K, V]) GetBatch(keys []K) []Result[V] {
+	c.mu.Lock()
+	results := make([]Result[V], len(keys))
+	for i, key := range keys {
+		results[i].Value, results[i].Err = c.get(key)
+	}
+	onMiss := c.onMiss
+	c.unlock()
+
+	if onMiss != nil {
+		for i, key := range keys {
+			if results[i].Err != nil {
+				onMiss(key)
+			}
+		}
+	}
+	return results
+}
+
+// Set stores value for key and marks it as most recently used. Inserting a
+// new key into a full cache evicts the least recently used entry.
+func (c *LRUCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// EntryInfo returns the metadata of the entry stored for key without
+// counting as an access. Pinned entries sit outside the policy without any
+// metadata, so they report ErrKeyNotFound like missing keys.
+func (c *LRUCache[K, V]) EntryInfo(key K) (Info, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	i, ok := c.items[key]
+	if !ok {
+		return Info{}, ErrKeyNotFound
+	}
+	return c.nodes[i].info, nil
+}
+
+// Add stores value for key only if key is not cached yet, and fails with
+// ErrKeyExists otherwise
+func (c *LRUCache[K, V]) Add(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.peek(key); ok {
+		return ErrKeyExists
+	}
+	return c.set(key, value)
+}
+
+// SetIfPresent stores value for key only if key is cached, and fails with
+// ErrKeyNotFound otherwise. Like Set, it counts as an access.
+func (c *LRUCache[K, V]) SetIfPresent(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.peek(key); !ok {
+		return ErrKeyNotFound
+	}
+	return c.set(key, value)
+}
+
+// SetMany stores entries in order in a single locked pass, evicting as
+// usual after each insert. A batch with more keys than the capacity either
+// leaves only its last keys resident or, with RejectExcess, fails with
+// ErrCacheFull before anything is stored. Otherwise SetMany stops at the
+// first failing insert and returns its error.
+func (c *LRUCache[K, V]) SetMany(entries []Entry[K, V]) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.overflow == RejectExcess && overflows(entries, c.capacity, c.pinned) {
+		return ErrCacheFull
+	}
+	for _, e := range entries {
+		if err := c.set(e.Key, e.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Oldest returns the least recently used entry, the next one the policy
+// evicts, without counting as an access. Pinned entries are never returned,
+// so ok is false if no entry can be evicted.
+func (c *LRUCache[K, V]) Oldest() (key K, value V, ok bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	i := c.nodes[0].prev
+	return c.nodes[i].key, c.nodes[i].value, i != 0
+}
+
+// Newest returns the most recently used entry, the last one the policy
+// evicts, without counting as an access. Pinned entries are never returned.
+func (c *LRUCache[K, V]) Newest() (key K, value V, ok bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	i := c.nodes[0].next
+	return c.nodes[i].key, c.nodes[i].value, i != 0
+}
+
+// Keys returns the keys of all entries in eviction order, the next victim
+// first and pinned entries last, without counting as an access
+func (c *LRUCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return entryKeys(c.ordered())
+}
+
+// Values returns the values of all entries in the order of Keys, without
+// counting as an access
+func (c *LRUCache[K, V]) Values() []V {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return entryValues(c.ordered())
+}
+
+// Items returns all entries in the order of Keys, without counting as an
+// access
+func (c *LRUCache[K, V]) Items() []Entry[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.ordered()
+}
+
+// All returns an iterator over the entries in the order of Keys, without
+// counting as accesses. Each iteration walks a snapshot taken when it
+// starts, so the loop body may call back into the cache and sees none of
+// its own changes. The iterator has the type of iter.Seq2[K, V], so that
+// with Go 1.23 or later it can be ranged over directly.
+func (c *LRUCache[K, V]) All() func(yield func(key K, value V) bool) {
+	return func(yield func(key K, value V) bool) {
+		for _, e := range c.Items() {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// ForEach calls fn with the entries in the order of Keys until fn returns
+// false, without counting as accesses. Unlike All it takes no snapshot: fn
+// runs while the cache is locked and must not call back into the cache.
+func (c *LRUCache[K, V]) ForEach(fn func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.walk(fn)
+}
+
+// ToMap returns a copy of all entries without counting as an access
+func (c *LRUCache[K, V]) ToMap() map[K]V {
+	c.mu.Lock()
+	defer c.unlock()
+
+	m := make(map[K]V, len(c.items))
+	for key, i := range c.items {
+		m[key] = c.nodes[i].value
+	}
+	for key, value := range c.pinned {
+		m[key] = value
+	}
+	return m
+}
+
+// FromMap inserts every entry of m as if by Set, applying eviction as
+// usual. It stops at the first failing insert and returns its error.
+func (c *LRUCache[K, V]) FromMap(m map[K]V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for key, value := range m {
+		if err := c.set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update atomically replaces the value of key with fn(value, found), where
+// found reports whether key was present. The update counts as a single
+// access. fn runs while the cache is locked and must not call back into
+// the cache.
+func (c *LRUCache[K, V]) Update(key K, fn func(value V, found bool) V) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value := fn(c.peek(key))
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, err
+	}
+	return value, nil
+}
+
+// Contains reports whether key is stored, pinned or not, without counting
+// as an access
+func (c *LRUCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.peek(key)
+	return ok
+}
+
+// Peek returns the value stored for key like Get, but without counting as
+// an access, so the eviction order stays as it was
+func (c *LRUCache[K, V]) Peek(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, ok := c.peek(key)
+	if !ok {
+		return value, ErrKeyNotFound
+	}
+	return value, nil
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *LRUCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// GetOrCompute returns the value stored for key if there is one, and
+// otherwise calls fn and stores its result. An error from fn is returned
+// and nothing is stored. fn runs while the cache is unlocked, so it may be
+// slow or call back into the cache; concurrent misses on the same key each
+// call fn, and the first result stored is returned to all of them. Wrap the
+// cache in a SingleFlightCache to share a single call instead.
+func (c *LRUCache[K, V]) GetOrCompute(key K, fn func() (V, error)) (V, error) {
+	c.mu.Lock()
+	value, err := c.get(key)
+	c.unlock()
+	if err == nil {
+		return value, nil
+	}
+
+	value, err = fn()
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, ok := c.peek(key); ok {
+		return actual, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, err
+	}
+	return value, nil
+}
+
+// Len returns the number of entries, pinned ones included
+func (c *LRUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items) + len(c.pinned)
+}
+
+// Cap returns the maximum number of entries the policy manages. Pinned
+// entries don't count against it.
+func (c *LRUCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *LRUCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.deleteKey(key)
+}
+
+// Pop removes key and returns the value it stored, or ErrKeyNotFound if key
+// is missing. The removal counts as a Delete, not as an access.
+func (c *LRUCache[K, V]) Pop(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, ok := c.peek(key)
+	if !ok {
+		return value, ErrKeyNotFound
+	}
+	return value, c.deleteKey(key)
+}
+
+// DeleteMany removes keys under a single lock and returns how many of them
+// were cached. Missing keys are skipped.
+func (c *LRUCache[K, V]) DeleteMany(keys []K) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	removed := 0
+	for _, key := range keys {
+		if c.deleteKey(key) == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// SetIf stores value for key only if key is cached and fn reports true for
+// its current value, counting as a single access. It returns ErrKeyNotFound
+// for a missing key and false without error when fn rejects the value. fn
+// runs while the cache is locked and must not call back into the cache.
+func (c *LRUCache[K, V]) SetIf(key K, value V, fn func(current V) bool) (bool, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	current, ok := c.peek(key)
+	if !ok {
+		return false, ErrKeyNotFound
+	}
+	if !fn(current) {
+		return false, nil
+	}
+	return true, c.set(key, value)
+}
+
+// DeleteIf removes key only if fn reports true for its current value. It
+// returns ErrKeyNotFound for a missing key and false without error when fn
+// rejects the value. fn runs while the cache is locked and must not call
+// back into the cache.
+func (c *LRUCache[K, V]) DeleteIf(key K, fn func(value V) bool) (bool, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, ok := c.peek(key)
+	if !ok {
+		return false, ErrKeyNotFound
+	}
+	if !fn(value) {
+		return false, nil
+	}
+	return true, c.deleteKey(key)
+}
+
+// SetPinned stores value for key and pins it: the policy never evicts a
+// pinned entry, and pinned entries don't take up the capacity left to the
+// others. At most capacity entries can be pinned at once; pinning more fails
+// with ErrTooManyPinned.
+func (c *LRUCache[K, V]) SetPinned(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.pinned[key]; !ok && len(c.pinned) >= c.capacity {
+		return ErrTooManyPinned
+	}
+	if i, ok := c.items[key]; ok {
+		c.remove(i)
+	}
+	if c.pinned == nil {
+		c.pinned = make(map[K]V)
+	}
+	c.pinned[key] = value
+	return nil
+}
+
+// Unpin hands a pinned entry back to the policy, inserting it as if by Set
+func (c *LRUCache[K, V]) Unpin(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, ok := c.pinned[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	delete(c.pinned, key)
+	return c.set(key, value)
+}
+
+// DeleteFunc removes every entry for which fn reports true in a single
+// locked pass and returns how many entries it removed. fn runs while the
+// cache is locked and must not call back into the cache.
+func (c *LRUCache[K, V]) DeleteFunc(fn func(key K, value V) bool) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var keys []K
+	for key := range c.items {
+		if value, ok := c.peek(key); ok && fn(key, value) {
+			keys = append(keys, key)
+		}
+	}
+	for key, value := range c.pinned {
+		if fn(key, value) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		_ = c.deleteKey(key)
+	}
+	return len(keys)
+}
+
+// SetOnMiss registers fn to be called with every key a Get or GetBatch
+// misses, including expired keys. fn runs after the cache is unlocked, so it
+// may block or call back into the cache.
+func (c *LRUCache[K, V]) SetOnMiss(fn func(key K)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onMiss = fn
+}
+
+// SetWatermark registers onHigh to be called when the share of the capacity
+// in use reaches highPct percent, and onLow when it then drops below lowPct
+// percent. Each callback fires once per crossing; onHigh can only fire again
+// after onLow did. Either may be nil. The callbacks run once the cache is
+// unlocked, so they may call back into the cache.
+func (c *LRUCache[K, V]) SetWatermark(highPct, lowPct float64, onHigh, onLow func()) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.watermark = watermark{high: highPct, low: lowPct, onHigh: onHigh, onLow: onLow}
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *LRUCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// EvictionEvents returns the stream of removed entries enabled by
+// WithEvictionEvents, or nil if the cache was created without it
+func (c *LRUCache[K, V]) EvictionEvents() <-chan EvictionEvent[K, V] {
+	return c.events
+}
+
+// TopKeys returns the n most accessed resident keys, most accessed first,
+// counting Set and Get hits since the key entered the cache
+func (c *LRUCache[K, V]) TopKeys(n int) []KeyCount[K] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	counts := make([]KeyCount[K], 0, len(c.items))
+	for key, i := range c.items {
+		counts = append(counts, KeyCount[K]{Key: key, Count: c.nodes[i].info.AccessCount})
+	}
+	return topKeys(counts, n)
+}
+
+// Freeze suspends evictions: until Unfreeze, Set keeps inserting new keys
+// beyond the capacity instead of evicting entries.
+func (c *LRUCache[K, V]) Freeze() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.frozen = true
+}
+
+// Unfreeze resumes evictions and immediately evicts entries in policy order
+// until the cache is back within its capacity
+func (c *LRUCache[K, V]) Unfreeze() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.frozen = false
+	c.shrink()
+}
+
+// Clear removes all entries
+func (c *LRUCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.reset()
+}
+
+// Resize changes the capacity to newCapacity, evicting entries in policy
+// order until the cache fits. A frozen cache keeps its entries until
+// Unfreeze. It fails with ErrTooManyPinned, changing nothing, if more
+// entries are pinned than newCapacity allows.
+func (c *LRUCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if len(c.pinned) > max(newCapacity, 0) {
+		return ErrTooManyPinned
+	}
+	c.capacity = newCapacity
+	if !c.frozen {
+		c.shrink()
+	}
+	return nil
+}
+
+// Replace atomically swaps the whole contents of the cache for entries,
+// inserted as if by Set, so readers see either the old or the new entries
+// but never a partially filled cache. A map has no order telling which of
+// its entries should survive an overflow, so more entries than the capacity
+// fail with ErrCacheFull and leave the cache untouched.
+func (c *LRUCache[K, V]) Replace(entries map[K]V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if len(entries) > max(c.capacity, 0) {
+		return ErrCacheFull
+	}
+	c.reset()
+	for key, value := range entries {
+		_ = c.set(key, value)
+	}
+	return nil
+}
+
+// alloc returns an unlinked slot, reusing a released one when possible
+func (c *LRUCache[K, V]) alloc() int {
+	if c.free != 0 {
+		i := c.free
+		c.free = c.nodes[i].next
+		return i
+	}
+	c.nodes = append(c.nodes, lruNode[K, V]{})
+	return len(c.nodes) - 1
+}
+
+// shrink evicts entries in policy order until the cache is within its
+// capacity
+func (c *LRUCache[K, V]) shrink() {
+	for len(c.items) > max(c.capacity, 0) {
+		c.evict(c.nodes[0].prev)
+	}
+}
+
+// ordered returns the entries in eviction order
+func (c *LRUCache[K, V]) ordered() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, len(c.items)+len(c.pinned))
+	c.walk(func(key K, value V) bool {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+		return true
+	})
+	return entries
+}
+
+// walk calls fn with the entries in eviction order until it returns false
+func (c *LRUCache[K, V]) walk(fn func(key K, value V) bool) {
+	for i := c.nodes[0].prev; i != 0; i = c.nodes[i].prev {
+		if !fn(c.nodes[i].key, c.nodes[i].value) {
+			return
+		}
+	}
+	walkPinned(c.pinned, fn)
+}
+
+// evict removes slot i on behalf of the policy and reports its entry
+func (c *LRUCache[K, V]) evict(i int) {
+	key, value := c.nodes[i].key, c.nodes[i].value
+	c.remove(i)
+	c.events.emit(key, value, ReasonCapacity)
+	c.onEvict.report(key, value)
+}
+
+// remove unlinks slot i, drops its key and puts it on the free list
+func (c *LRUCache[K, V]) remove(i int) {
+	c.unlink(i)
+	delete(c.items, c.nodes[i].key)
+	c.nodes[i] = lruNode[K, V]{next: c.free}
+	c.free = i
+}
+
+func (c *LRUCache[K, V]) moveToFront(i int) {
+	c.unlink(i)
+	c.pushFront(i)
+}
+
+func (c *LRUCache[K, V]) pushFront(i int) {
+	c.nodes[i].prev = 0
+	c.nodes[i].next = c.nodes[0].next
+	c.nodes[c.nodes[0].next].prev = i
+	c.nodes[0].next = i
+}
+
+func (c *LRUCache[K, V]) unlink(i int) {
+	c.nodes[c.nodes[i].prev].next = c.nodes[i].next
+	c.nodes[c.nodes[i].next].prev = c.nodes[i].prev
+}
+
+func (c *LRUCache[K, V]) get(key K) (V, error) {
+	if value, ok := c.pinned[key]; ok {
+		return value, nil
+	}
+	i, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	c.nodes[i].info.touch(time.Now())
+	c.moveToFront(i)
+	return c.nodes[i].value, nil
+}
+
+func (c *LRUCache[K, V]) set(key K, value V) error {
+	if _, ok := c.pinned[key]; ok {
+		c.pinned[key] = value
+		return nil
+	}
+	now := time.Now()
+	if i, ok := c.items[key]; ok {
+		c.nodes[i].value = value
+		c.nodes[i].info.touch(now)
+		c.moveToFront(i)
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if !c.frozen && len(c.items) >= c.capacity {
+		c.evict(c.nodes[0].prev)
+	}
+	i := c.alloc()
+	c.nodes[i].key = key
+	c.nodes[i].value = value
+	c.nodes[i].info = Info{CreatedAt: now}
+	c.nodes[i].info.touch(now)
+	c.pushFront(i)
+	c.items[key] = i
+	return nil
+}
+
+// peek returns the value of key without counting as an access
+func (c *LRUCache[K, V]) peek(key K) (V, bool) {
+	if value, ok := c.pinned[key]; ok {
+		return value, true
+	}
+	i, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return c.nodes[i].value, true
+}
+
+func (c *LRUCache[K, V]) deleteKey(key K) error {
+	if value, ok := c.pinned[key]; ok {
+		delete(c.pinned, key)
+		c.events.emit(key, value, ReasonDeleted)
+		return nil
+	}
+	i, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	c.events.emit(key, c.nodes[i].value, ReasonDeleted)
+	c.remove(i)
+	return nil
+}
+
+// reset removes all entries
+func (c *LRUCache[K, V]) reset() {
+	if c.events != nil {
+		for key, value := range c.pinned {
+			c.events.emit(key, value, ReasonCleared)
+		}
+		for i := c.nodes[0].next; i != 0; i = c.nodes[i].next {
+			c.events.emit(c.nodes[i].key, c.nodes[i].value, ReasonCleared)
+		}
+	}
+	clear(c.items)
+	clear(c.nodes)
+	c.nodes = c.nodes[:1]
+	c.free = 0
+	c.pinned = nil
+}
+
+// unlock releases the lock, then reports the entries evicted while it was
+// held and any crossed watermark
+func (c *LRUCache[K, V]) unlock() {
+	crossed := c.watermark.check(len(c.items), c.capacity)
+	c.onEvict.unlock(&c.mu)
+	if crossed != nil {
+		crossed()
+	}
 }
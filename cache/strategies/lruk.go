@@ -0,0 +1,283 @@
+package strategies
+
+import "sync"
+
+// LRUKCache implements the LRU-K policy. It remembers the last k references
+// of every entry and evicts the entry whose k-th most recent reference is
+// the oldest. Entries referenced fewer than k times have an infinite
+// backward distance and are evicted first, least recently used first.
+//
+// References are stamped with a logical clock ticking on every Set and Get
+// hit. WithCorrelationPeriod collapses references close to each other into
+// one. Finding a victim scans all entries, so evictions take O(n).
+type LRUKCache[K comparable, V any] struct {
+	mu        sync.Mutex
+	capacity  int
+	k         int
+	period    uint64
+	clock     uint64
+	items     map[K]*lrukEntry[K, V]
+	onEvict   evictHook[K, V]
+	onMiss    func(key K)
+	watermark watermark
+}
+
+// lrukEntry is an entry together with its reference history
+type lrukEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	history []uint64 // most recent reference first, at most k long
+}
+
+// NewLRUKCache creates an LRU-K cache holding at most capacity entries.
+// Values of k below 1 are treated as 1, which behaves like plain LRU. It
+// honors WithCorrelationPeriod.
+func NewLRUKCache[K comparable, V any](capacity, k int, opts ...Option) *LRUKCache[K, V] {
+	o := newOptions(opts)
+	return &LRUKCache[K, V]{
+		capacity: capacity,
+		k:        max(k, 1),
+		period:   uint64(max(o.correlation, 0)),
+		items:    make(map[K]*lrukEntry[K, V]),
+	}
+}
+
+// Get returns the value stored for key and records the reference
+func (c *LRUKCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	value, err := c.get(key)
+	onMiss := c.onMiss
+	c.unlock()
+
+	if err != nil && onMiss != nil {
+		onMiss(key)
+	}
+	return value, err
+}
+
+// Set stores value for key and records the reference. Inserting a new key
+// into a full cache evicts the entry with the oldest k-th reference.
+func (c *LRUKCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *LRUKCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *LRUKCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Keys returns the keys of all entries in no particular order, without
+// counting as an access
+func (c *LRUKCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Len returns the number of entries
+func (c *LRUKCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *LRUKCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *LRUKCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.items[key]; !ok {
+		return ErrKeyNotFound
+	}
+	delete(c.items, key)
+	return nil
+}
+
+// DeleteFunc removes every entry for which fn reports true in a single
+// locked pass and returns how many entries it removed. fn runs while the
+// cache is locked and must not call back into the cache.
+func (c *LRUKCache[K, V]) DeleteFunc(fn func(key K, value V) bool) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	n := 0
+	for key, e := range c.items {
+		if fn(key, e.value) {
+			delete(c.items, key)
+			n++
+		}
+	}
+	return n
+}
+
+// Clear removes all entries
+func (c *LRUKCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+}
+
+// Resize changes the capacity to newCapacity, evicting the entries with the
+// oldest k-th reference until the cache fits
+func (c *LRUKCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	for len(c.items) > max(c.capacity, 0) {
+		c.evict()
+	}
+	return nil
+}
+
+// SetWatermark registers onHigh to be called when the share of the capacity
+// in use reaches highPct percent, and onLow when it then drops below lowPct
+// percent. Each callback fires once per crossing; onHigh can only fire again
+// after onLow did. Either may be nil. The callbacks run once the cache is
+// unlocked, so they may call back into the cache.
+func (c *LRUKCache[K, V]) SetWatermark(highPct, lowPct float64, onHigh, onLow func()) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.watermark = watermark{high: highPct, low: lowPct, onHigh: onHigh, onLow: onLow}
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *LRUKCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// SetOnMiss registers fn to be called with every key a Get misses. fn runs
+// after the cache is unlocked, so it may block or call back into the cache.
+func (c *LRUKCache[K, V]) SetOnMiss(fn func(key K)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onMiss = fn
+}
+
+// reference stamps a new reference to e, forgetting the oldest one beyond
+// k. A reference correlated with the latest one replaces it instead.
+func (c *LRUKCache[K, V]) reference(e *lrukEntry[K, V]) {
+	c.clock++
+	if len(e.history) > 0 && c.clock-e.history[0] <= c.period {
+		e.history[0] = c.clock
+		return
+	}
+	if len(e.history) < c.k {
+		e.history = append(e.history, 0)
+	}
+	copy(e.history[1:], e.history)
+	e.history[0] = c.clock
+}
+
+// evict removes the entry with the largest backward k-distance
+func (c *LRUKCache[K, V]) evict() {
+	var victim *lrukEntry[K, V]
+	for _, e := range c.items {
+		if victim == nil || c.before(e, victim) {
+			victim = e
+		}
+	}
+	if victim == nil {
+		return
+	}
+	delete(c.items, victim.key)
+	c.onEvict.report(victim.key, victim.value)
+}
+
+// before reports whether a should be evicted before b
+func (c *LRUKCache[K, V]) before(a, b *lrukEntry[K, V]) bool {
+	aFull, bFull := len(a.history) == c.k, len(b.history) == c.k
+	switch {
+	case aFull != bFull:
+		return !aFull
+	case aFull:
+		return a.history[c.k-1] < b.history[c.k-1]
+	default:
+		return a.history[0] < b.history[0]
+	}
+}
+
+// unlock releases the lock, then reports the entries evicted while it was
+// held and any crossed watermark
+func (c *LRUKCache[K, V]) unlock() {
+	crossed := c.watermark.check(len(c.items), c.capacity)
+	c.onEvict.unlock(&c.mu)
+	if crossed != nil {
+		crossed()
+	}
+}
+
+func (c *LRUKCache[K, V]) get(key K) (V, error) {
+	e, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	c.reference(e)
+	return e.value, nil
+}
+
+func (c *LRUKCache[K, V]) set(key K, value V) error {
+	if e, ok := c.items[key]; ok {
+		e.value = value
+		c.reference(e)
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if len(c.items) >= c.capacity {
+		c.evict()
+	}
+	e := &lrukEntry[K, V]{key: key, value: value, history: make([]uint64, 0, c.k)}
+	c.reference(e)
+	c.items[key] = e
+	return nil
+}
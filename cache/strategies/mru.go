@@ -0,0 +1,183 @@
+package strategies
+
+import (
+	"container/list"
+	"sync"
+)
+
+// MRUCache implements a Most Recently Used cache. It evicts the entry used
+// last, which keeps the oldest entries of a cyclic scan larger than the
+// cache resident where LRU would miss on every access.
+type MRUCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[K]*list.Element
+	queue    *list.List // front is the most recently used entry
+	onEvict  evictHook[K, V]
+}
+
+// NewMRUCache creates an MRU cache holding at most capacity entries
+func NewMRUCache[K comparable, V any](capacity int) *MRUCache[K, V] {
+	return &MRUCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element),
+		queue:    list.New(),
+	}
+}
+
+// Get returns the value stored for key and marks it as most recently used
+func (c *MRUCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key and marks it as most recently used. Inserting a
+// new key into a full cache first evicts the most recently used entry.
+func (c *MRUCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *MRUCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all entries in no particular order, without
+// counting as an access
+func (c *MRUCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *MRUCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries
+func (c *MRUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *MRUCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *MRUCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	c.queue.Remove(elem)
+	delete(c.items, key)
+	return nil
+}
+
+// Clear removes all entries
+func (c *MRUCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+	c.queue.Init()
+}
+
+// Resize changes the capacity to newCapacity, evicting the most recently
+// used entries until the cache fits
+func (c *MRUCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	for c.queue.Len() > max(c.capacity, 0) {
+		c.evict()
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *MRUCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// evict removes the most recently used entry and reports it
+func (c *MRUCache[K, V]) evict() {
+	e := c.queue.Remove(c.queue.Front()).(*entry[K, V])
+	delete(c.items, e.key)
+	c.onEvict.report(e.key, e.value)
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *MRUCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *MRUCache[K, V]) get(key K) (V, error) {
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	c.queue.MoveToFront(elem)
+	return elem.Value.(*entry[K, V]).value, nil
+}
+
+func (c *MRUCache[K, V]) set(key K, value V) error {
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*entry[K, V]).value = value
+		c.queue.MoveToFront(elem)
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if c.queue.Len() >= c.capacity {
+		c.evict()
+	}
+	c.items[key] = c.queue.PushFront(&entry[K, V]{key: key, value: value})
+	return nil
+}
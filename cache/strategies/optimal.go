@@ -0,0 +1,213 @@
+package strategies
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// OfflineOptimalCache implements Belady's optimal replacement: it evicts the
+// entry whose next use lies farthest in the future. It needs the whole
+// access trace up front, so it can't serve real traffic, but replaying the
+// trace against it gives the best hit ratio any policy could reach.
+//
+// Every Get is taken to be the next access of the trace, whether or not it
+// requests the key the trace has there; Set, Delete and Keys don't advance
+// the trace. A read-through replay that Sets the key after a missed Get
+// therefore stays in step. Finding a victim scans all entries, so evictions
+// take O(n log m) for traces with m accesses per key.
+type OfflineOptimalCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	pos      int         // index of the next access of the trace
+	uses     map[K][]int // positions of every key in the trace
+	items    map[K]V
+	onEvict  evictHook[K, V]
+}
+
+// NewOfflineOptimalCache creates a cache holding at most capacity entries
+// that knows the future accesses from trace
+func NewOfflineOptimalCache[K comparable, V any](capacity int, trace []K) *OfflineOptimalCache[K, V] {
+	uses := make(map[K][]int)
+	for i, key := range trace {
+		uses[key] = append(uses[key], i)
+	}
+	return &OfflineOptimalCache[K, V]{
+		capacity: capacity,
+		uses:     uses,
+		items:    make(map[K]V),
+	}
+}
+
+// Get returns the value stored for key and advances the trace by one access
+func (c *OfflineOptimalCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key. Inserting a new key into a full cache first
+// evicts the entry used again last, or never.
+func (c *OfflineOptimalCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it advances the trace by a single access.
+func (c *OfflineOptimalCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all entries in no particular order
+func (c *OfflineOptimalCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access. It
+// doesn't advance the trace.
+func (c *OfflineOptimalCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries
+func (c *OfflineOptimalCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *OfflineOptimalCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *OfflineOptimalCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.items[key]; !ok {
+		return ErrKeyNotFound
+	}
+	delete(c.items, key)
+	return nil
+}
+
+// Clear removes all entries. The position in the trace is kept.
+func (c *OfflineOptimalCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+}
+
+// Resize changes the capacity to newCapacity, evicting the entries used
+// again last until the cache fits. It does not advance the trace.
+func (c *OfflineOptimalCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	for len(c.items) > max(c.capacity, 0) {
+		c.evict()
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *OfflineOptimalCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// evict removes the entry whose next use is the farthest
+func (c *OfflineOptimalCache[K, V]) evict() {
+	var victim K
+	farthest := -1
+	for key := range c.items {
+		if next := c.nextUse(key); next > farthest {
+			victim, farthest = key, next
+		}
+	}
+	if farthest < 0 {
+		return
+	}
+	c.onEvict.report(victim, c.items[victim])
+	delete(c.items, victim)
+}
+
+// nextUse returns the position of the next access to key, or math.MaxInt if
+// the trace doesn't use it again
+func (c *OfflineOptimalCache[K, V]) nextUse(key K) int {
+	uses := c.uses[key]
+	i := sort.SearchInts(uses, c.pos)
+	if i == len(uses) {
+		return math.MaxInt
+	}
+	return uses[i]
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *OfflineOptimalCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *OfflineOptimalCache[K, V]) get(key K) (V, error) {
+	c.pos++
+	value, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	return value, nil
+}
+
+func (c *OfflineOptimalCache[K, V]) set(key K, value V) error {
+	if _, ok := c.items[key]; ok {
+		c.items[key] = value
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if len(c.items) >= c.capacity {
+		c.evict()
+	}
+	c.items[key] = value
+	return nil
+}
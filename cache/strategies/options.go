@@ -0,0 +1,56 @@
+package strategies
+
+import "time"
+
+// Option configures a cache at construction
+type Option func(*options)
+
+// options holds the settings shared by the strategies
+type options struct {
+	eventBuffer int
+	janitor     time.Duration
+	tracking    bool
+	clock       Clock
+	overflow    OverflowPolicy
+	decayPeriod int
+	correlation int
+}
+
+// WithJanitor makes a TTL cache remove expired entries in the background
+// every interval, in addition to the lazy removal on access. Other caches
+// ignore it. StopJanitor ends the background goroutine.
+func WithJanitor(interval time.Duration) Option {
+	return func(o *options) {
+		o.janitor = interval
+	}
+}
+
+// WithFrequencyDecay makes an LFU cache halve the frequencies of all its
+// entries after every period accesses, Get hits and Sets alike, so that
+// entries hot long ago eventually make room for new ones. Frequencies never
+// drop below 1. Other caches ignore it, as do values below 1.
+func WithFrequencyDecay(period int) Option {
+	return func(o *options) {
+		o.decayPeriod = period
+	}
+}
+
+// WithCorrelationPeriod makes an LRU-K cache treat a reference arriving
+// within n references of an entry's latest one as correlated with it, such
+// as the reads of a single transaction: it moves the latest reference
+// forward instead of adding a new one to the history, so a burst of reads
+// does not make an entry look frequently used. Other caches ignore it, as
+// do values below 1.
+func WithCorrelationPeriod(n int) Option {
+	return func(o *options) {
+		o.correlation = n
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{clock: realClock{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
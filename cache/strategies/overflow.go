@@ -0,0 +1,36 @@
+package strategies
+
+// OverflowPolicy decides what SetMany does with a batch holding more keys
+// than the cache has room for
+type OverflowPolicy int
+
+// Overflow policies
+const (
+	// EvictOldest inserts the whole batch in order, evicting as usual after
+	// each insert, so that only the last capacity keys of the batch may
+	// survive it
+	EvictOldest OverflowPolicy = iota
+	// RejectExcess fails the whole batch with ErrCacheFull, leaving the
+	// cache untouched
+	RejectExcess
+)
+
+// WithOverflowPolicy sets how SetMany handles batches larger than the
+// capacity. The default is EvictOldest.
+func WithOverflowPolicy(p OverflowPolicy) Option {
+	return func(o *options) {
+		o.overflow = p
+	}
+}
+
+// overflows reports whether entries hold more distinct keys than capacity.
+// Pinned keys are not counted since they take up no capacity.
+func overflows[K comparable, V any](entries []Entry[K, V], capacity int, pinned map[K]V) bool {
+	keys := make(map[K]struct{}, len(entries))
+	for _, e := range entries {
+		if _, ok := pinned[e.Key]; !ok {
+			keys[e.Key] = struct{}{}
+		}
+	}
+	return len(keys) > capacity
+}
@@ -0,0 +1,258 @@
+package strategies
+
+import "container/list"
+
+// FIFOPolicy evicts keys in insertion order
+type FIFOPolicy[K comparable] struct {
+	keys  map[K]*list.Element
+	queue *list.List // front is the newest key
+}
+
+// NewFIFOPolicy creates a FIFO eviction policy
+func NewFIFOPolicy[K comparable]() *FIFOPolicy[K] {
+	return &FIFOPolicy[K]{keys: make(map[K]*list.Element), queue: list.New()}
+}
+
+// OnAdd queues key as the newest one
+func (p *FIFOPolicy[K]) OnAdd(key K) {
+	p.keys[key] = p.queue.PushFront(key)
+}
+
+// OnAccess does nothing, accesses don't change the insertion order
+func (p *FIFOPolicy[K]) OnAccess(key K) {}
+
+// OnRemove forgets key
+func (p *FIFOPolicy[K]) OnRemove(key K) {
+	if elem, ok := p.keys[key]; ok {
+		p.queue.Remove(elem)
+		delete(p.keys, key)
+	}
+}
+
+// Victim returns the oldest key
+func (p *FIFOPolicy[K]) Victim() (K, bool) {
+	return popBack(p.queue, p.keys)
+}
+
+// LRUPolicy evicts the least recently used key
+type LRUPolicy[K comparable] struct {
+	keys  map[K]*list.Element
+	queue *list.List // front is the most recently used key
+}
+
+// NewLRUPolicy creates an LRU eviction policy
+func NewLRUPolicy[K comparable]() *LRUPolicy[K] {
+	return &LRUPolicy[K]{keys: make(map[K]*list.Element), queue: list.New()}
+}
+
+// OnAdd marks key as the most recently used one
+func (p *LRUPolicy[K]) OnAdd(key K) {
+	p.keys[key] = p.queue.PushFront(key)
+}
+
+// OnAccess marks key as the most recently used one
+func (p *LRUPolicy[K]) OnAccess(key K) {
+	if elem, ok := p.keys[key]; ok {
+		p.queue.MoveToFront(elem)
+	}
+}
+
+// OnRemove forgets key
+func (p *LRUPolicy[K]) OnRemove(key K) {
+	if elem, ok := p.keys[key]; ok {
+		p.queue.Remove(elem)
+		delete(p.keys, key)
+	}
+}
+
+// Victim returns the least recently used key
+func (p *LRUPolicy[K]) Victim() (K, bool) {
+	return popBack(p.queue, p.keys)
+}
+
+// LFUPolicy evicts the least frequently used key, the least recently used
+// one among keys of the same frequency
+type LFUPolicy[K comparable] struct {
+	keys    map[K]*list.Element
+	buckets map[int]*list.List // keys by frequency, front is the most recent
+	minFreq int
+}
+
+// lfuKey is a key together with its frequency
+type lfuKey[K comparable] struct {
+	key  K
+	freq int
+}
+
+// NewLFUPolicy creates an LFU eviction policy
+func NewLFUPolicy[K comparable]() *LFUPolicy[K] {
+	return &LFUPolicy[K]{keys: make(map[K]*list.Element), buckets: make(map[int]*list.List)}
+}
+
+// OnAdd tracks key with a frequency of 1
+func (p *LFUPolicy[K]) OnAdd(key K) {
+	p.keys[key] = p.bucket(1).PushFront(&lfuKey[K]{key: key, freq: 1})
+	p.minFreq = 1
+}
+
+// OnAccess increments the frequency of key
+func (p *LFUPolicy[K]) OnAccess(key K) {
+	elem, ok := p.keys[key]
+	if !ok {
+		return
+	}
+	k := p.unlink(elem)
+	k.freq++
+	p.keys[key] = p.bucket(k.freq).PushFront(k)
+}
+
+// OnRemove forgets key
+func (p *LFUPolicy[K]) OnRemove(key K) {
+	if elem, ok := p.keys[key]; ok {
+		p.unlink(elem)
+		delete(p.keys, key)
+	}
+}
+
+// Victim returns the least recently used key of the lowest frequency
+func (p *LFUPolicy[K]) Victim() (K, bool) {
+	if len(p.keys) == 0 {
+		var zero K
+		return zero, false
+	}
+	for p.buckets[p.minFreq] == nil {
+		p.minFreq++
+	}
+	k := p.unlink(p.buckets[p.minFreq].Back())
+	delete(p.keys, k.key)
+	return k.key, true
+}
+
+// bucket returns the list of keys with frequency freq, creating it if needed
+func (p *LFUPolicy[K]) bucket(freq int) *list.List {
+	b, ok := p.buckets[freq]
+	if !ok {
+		b = list.New()
+		p.buckets[freq] = b
+	}
+	return b
+}
+
+// unlink removes elem from its bucket, dropping the bucket once empty. The
+// minimum frequency is only fixed lazily by Victim.
+func (p *LFUPolicy[K]) unlink(elem *list.Element) *lfuKey[K] {
+	k := elem.Value.(*lfuKey[K])
+	b := p.buckets[k.freq]
+	b.Remove(elem)
+	if b.Len() == 0 {
+		delete(p.buckets, k.freq)
+	}
+	return k
+}
+
+// ARCPolicy evicts like ARCCache: keys seen once live in t1, keys seen
+// again in t2, and the ghost lists b1 and b2 of keys evicted from them adapt
+// the target size p of t1. Victim can't see the key about to be added, so
+// when t1 is exactly at its target it evicts from t2 even if the new key
+// is a ghost of b2, where ARCCache would evict from t1.
+type ARCPolicy[K comparable] struct {
+	capacity int
+	p        int
+	t1, t2   *list.List
+	b1, b2   *list.List
+	keys     map[K]*list.Element // keys of t1, t2, b1 and b2
+}
+
+// NewARCPolicy creates an ARC eviction policy for a cache holding at most
+// capacity entries
+func NewARCPolicy[K comparable](capacity int) *ARCPolicy[K] {
+	return &ARCPolicy[K]{
+		capacity: capacity,
+		t1:       list.New(),
+		t2:       list.New(),
+		b1:       list.New(),
+		b2:       list.New(),
+		keys:     make(map[K]*list.Element),
+	}
+}
+
+// OnAdd tracks key in t1, or in t2 if it is a ghost, adapting the target
+// size of t1 to the ghost list it was found in
+func (p *ARCPolicy[K]) OnAdd(key K) {
+	if elem, ok := p.keys[key]; ok {
+		switch elem.Value.(*arcEntry[K, struct{}]).where {
+		case p.b1:
+			p.p = growTarget(p.p, p.capacity, p.b1.Len(), p.b2.Len())
+		case p.b2:
+			p.p = shrinkTarget(p.p, p.b1.Len(), p.b2.Len())
+		}
+		p.move(elem, p.t2)
+		return
+	}
+	if p.t1.Len()+p.b1.Len() >= p.capacity && p.b1.Len() > 0 {
+		p.forget(p.b1.Back())
+	} else if p.t1.Len()+p.t2.Len()+p.b1.Len()+p.b2.Len() >= 2*p.capacity && p.b2.Len() > 0 {
+		p.forget(p.b2.Back())
+	}
+	e := &arcEntry[K, struct{}]{key: key, where: p.t1}
+	p.keys[key] = p.t1.PushFront(e)
+}
+
+// OnAccess promotes key to the front of t2
+func (p *ARCPolicy[K]) OnAccess(key K) {
+	if elem, ok := p.keys[key]; ok {
+		p.move(elem, p.t2)
+	}
+}
+
+// OnRemove forgets key without remembering it as a ghost
+func (p *ARCPolicy[K]) OnRemove(key K) {
+	if elem, ok := p.keys[key]; ok {
+		p.forget(elem)
+	}
+}
+
+// Victim moves the least recently used key of t1, if t1 is above its
+// target size, or else of t2 to the matching ghost list and returns it
+func (p *ARCPolicy[K]) Victim() (K, bool) {
+	var elem *list.Element
+	switch {
+	case p.t1.Len() > 0 && (p.t1.Len() > p.p || p.t2.Len() == 0):
+		elem = p.move(p.t1.Back(), p.b1)
+	case p.t2.Len() > 0:
+		elem = p.move(p.t2.Back(), p.b2)
+	default:
+		var zero K
+		return zero, false
+	}
+	return elem.Value.(*arcEntry[K, struct{}]).key, true
+}
+
+// move transfers elem to the front of the target list
+func (p *ARCPolicy[K]) move(elem *list.Element, target *list.List) *list.Element {
+	e := elem.Value.(*arcEntry[K, struct{}])
+	e.where.Remove(elem)
+	e.where = target
+	elem = target.PushFront(e)
+	p.keys[e.key] = elem
+	return elem
+}
+
+// forget drops elem from its list and from the tracked keys
+func (p *ARCPolicy[K]) forget(elem *list.Element) {
+	e := elem.Value.(*arcEntry[K, struct{}])
+	e.where.Remove(elem)
+	delete(p.keys, e.key)
+}
+
+// popBack removes the key at the back of queue from queue and keys
+func popBack[K comparable](queue *list.List, keys map[K]*list.Element) (K, bool) {
+	elem := queue.Back()
+	if elem == nil {
+		var zero K
+		return zero, false
+	}
+	key := queue.Remove(elem).(K)
+	delete(keys, key)
+	return key, true
+}
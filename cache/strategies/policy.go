@@ -0,0 +1,215 @@
+package strategies
+
+import "sync"
+
+// EvictionPolicy decides which key a PolicyCache evicts. It only tracks
+// keys; the cache stores the values and calls the policy under its lock, so
+// implementations need no locking of their own.
+//
+// Victim picks the key to evict from a full cache and forgets it, just
+// before the key of a new entry is passed to OnAdd. OnRemove is only called
+// for keys removed by Delete or Clear.
+type EvictionPolicy[K comparable] interface {
+	// OnAdd is called when key is inserted
+	OnAdd(key K)
+	// OnAccess is called when a Get or Set hits key
+	OnAccess(key K)
+	// OnRemove is called when key is removed without being evicted
+	OnRemove(key K)
+	// Victim returns the key to evict and forgets it, or false if the
+	// policy tracks no keys
+	Victim() (K, bool)
+}
+
+// PolicyCache is a cache storing its entries in a map and leaving the
+// choice of the entries to evict to an EvictionPolicy. It lets a new policy
+// be written without any of the bookkeeping of a cache.
+//
+// The FIFO, LRU, LFU and ARC caches do not run on PolicyCache and keep
+// stores of their own. Pinning, eviction events with their reasons,
+// freezing, snapshots and TTL expiry need per-entry state and eviction
+// order that an EvictionPolicy tracking bare keys does not expose, so
+// moving them onto it would mean growing the interface for every policy.
+type PolicyCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[K]V
+	policy   EvictionPolicy[K]
+	onEvict  evictHook[K, V]
+}
+
+// NewPolicyCache creates a cache holding at most capacity entries and
+// evicting the entries chosen by policy. The cache owns policy from then on.
+func NewPolicyCache[K comparable, V any](capacity int, policy EvictionPolicy[K]) *PolicyCache[K, V] {
+	return &PolicyCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]V),
+		policy:   policy,
+	}
+}
+
+// Get returns the value stored for key and reports the access to the policy
+func (c *PolicyCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key. Inserting a new key into a full cache first
+// evicts the victim of the policy.
+func (c *PolicyCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *PolicyCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all entries in no particular order, without
+// counting as an access
+func (c *PolicyCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *PolicyCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries
+func (c *PolicyCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *PolicyCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *PolicyCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.items[key]; !ok {
+		return ErrKeyNotFound
+	}
+	delete(c.items, key)
+	c.policy.OnRemove(key)
+	return nil
+}
+
+// Clear removes all entries
+func (c *PolicyCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for key := range c.items {
+		c.policy.OnRemove(key)
+	}
+	clear(c.items)
+}
+
+// Resize changes the capacity to newCapacity, evicting the victims of the
+// policy until the cache fits
+func (c *PolicyCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	for len(c.items) > max(c.capacity, 0) {
+		if !c.evict() {
+			break
+		}
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *PolicyCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// evict removes the victim of the policy and reports it. It returns false
+// if the policy had no victim.
+func (c *PolicyCache[K, V]) evict() bool {
+	victim, ok := c.policy.Victim()
+	if !ok {
+		return false
+	}
+	c.onEvict.report(victim, c.items[victim])
+	delete(c.items, victim)
+	return true
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *PolicyCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *PolicyCache[K, V]) get(key K) (V, error) {
+	value, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	c.policy.OnAccess(key)
+	return value, nil
+}
+
+func (c *PolicyCache[K, V]) set(key K, value V) error {
+	if _, ok := c.items[key]; ok {
+		c.items[key] = value
+		c.policy.OnAccess(key)
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if len(c.items) >= c.capacity && !c.evict() {
+		return ErrCacheFull
+	}
+	c.items[key] = value
+	c.policy.OnAdd(key)
+	return nil
+}
@@ -0,0 +1,256 @@
+package strategies
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// PriorityCache evicts the entry with the lowest priority, least recently
+// used first among equal priorities. Giving critical entries a high
+// priority keeps them while entries of lower priority churn.
+type PriorityCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	seq      uint64
+	items    map[K]*priorityEntry[K, V]
+	queue    priorityHeap[K, V]
+	onEvict  evictHook[K, V]
+}
+
+// priorityEntry is an entry together with its priority and last use
+type priorityEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	priority int
+	seq      uint64
+	index    int
+}
+
+// NewPriorityCache creates a priority cache holding at most capacity entries
+func NewPriorityCache[K comparable, V any](capacity int) *PriorityCache[K, V] {
+	return &PriorityCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*priorityEntry[K, V]),
+	}
+}
+
+// Get returns the value stored for key and marks it as recently used
+func (c *PriorityCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key, keeping the priority of an existing key. New
+// keys get priority 0.
+func (c *PriorityCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *PriorityCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// SetWithPriority stores value for key with the given priority. Inserting a
+// new key into a full cache evicts the least recently used entry of the
+// lowest priority, which may be lower than prio or not.
+func (c *PriorityCache[K, V]) SetWithPriority(key K, value V, prio int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.items[key]; ok {
+		e.value = value
+		e.priority = prio
+		c.touch(e)
+		return nil
+	}
+	return c.insert(key, value, prio)
+}
+
+// Keys returns the keys of all entries in no particular order, without
+// counting as a use
+func (c *PriorityCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *PriorityCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries
+func (c *PriorityCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *PriorityCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *PriorityCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	heap.Remove(&c.queue, e.index)
+	delete(c.items, key)
+	return nil
+}
+
+// Clear removes all entries
+func (c *PriorityCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+	c.queue = c.queue[:0]
+}
+
+// Resize changes the capacity to newCapacity, evicting the entries with the
+// lowest priority until the cache fits
+func (c *PriorityCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	for len(c.items) > max(c.capacity, 0) {
+		c.evict()
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *PriorityCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// insert adds a new entry, evicting one first if the cache is full
+func (c *PriorityCache[K, V]) insert(key K, value V, prio int) error {
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if len(c.items) >= c.capacity {
+		c.evict()
+	}
+	c.seq++
+	e := &priorityEntry[K, V]{key: key, value: value, priority: prio, seq: c.seq}
+	c.items[key] = e
+	heap.Push(&c.queue, e)
+	return nil
+}
+
+// touch marks e as the most recently used entry of its priority
+func (c *PriorityCache[K, V]) touch(e *priorityEntry[K, V]) {
+	c.seq++
+	e.seq = c.seq
+	heap.Fix(&c.queue, e.index)
+}
+
+// evict removes the entry with the lowest priority and reports it
+func (c *PriorityCache[K, V]) evict() {
+	e := heap.Pop(&c.queue).(*priorityEntry[K, V])
+	delete(c.items, e.key)
+	c.onEvict.report(e.key, e.value)
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *PriorityCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *PriorityCache[K, V]) get(key K) (V, error) {
+	e, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	c.touch(e)
+	return e.value, nil
+}
+
+func (c *PriorityCache[K, V]) set(key K, value V) error {
+	if e, ok := c.items[key]; ok {
+		e.value = value
+		c.touch(e)
+		return nil
+	}
+	return c.insert(key, value, 0)
+}
+
+// priorityHeap is a min-heap of entries by priority, then by last use,
+// implementing heap.Interface
+type priorityHeap[K comparable, V any] []*priorityEntry[K, V]
+
+func (h priorityHeap[K, V]) Len() int { return len(h) }
+
+func (h priorityHeap[K, V]) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority < h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h priorityHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *priorityHeap[K, V]) Push(x any) {
+	e := x.(*priorityEntry[K, V])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *priorityHeap[K, V]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,201 @@
+package strategies
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RandomCache implements Random Replacement: it evicts a uniformly random
+// entry, which makes it a baseline for comparing the hit ratios of the
+// other policies. Entries are kept in a slice so that picking and removing
+// a victim takes O(1).
+type RandomCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	rand     *rand.Rand
+	items    map[K]int // index of the entry in entries
+	entries  []entry[K, V]
+	onEvict  evictHook[K, V]
+}
+
+// NewRandomCache creates a random replacement cache holding at most
+// capacity entries and picking victims from src. A nil src is seeded from
+// the current time; tests pass a fixed seed to get reproducible evictions.
+func NewRandomCache[K comparable, V any](capacity int, src rand.Source) *RandomCache[K, V] {
+	if src == nil {
+		src = rand.NewSource(time.Now().UnixNano())
+	}
+	return &RandomCache[K, V]{
+		capacity: capacity,
+		rand:     rand.New(src),
+		items:    make(map[K]int),
+	}
+}
+
+// Get returns the value stored for key
+func (c *RandomCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key. Inserting a new key into a full cache evicts a
+// random entry.
+func (c *RandomCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *RandomCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all entries in no particular order
+func (c *RandomCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, len(c.entries))
+	for i, e := range c.entries {
+		keys[i] = e.key
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *RandomCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries
+func (c *RandomCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *RandomCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *RandomCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.items[key]; !ok {
+		return ErrKeyNotFound
+	}
+	c.remove(key)
+	return nil
+}
+
+// Clear removes all entries
+func (c *RandomCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+	clear(c.entries)
+	c.entries = c.entries[:0]
+}
+
+// Resize changes the capacity to newCapacity, evicting random entries until
+// the cache fits
+func (c *RandomCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	for len(c.entries) > max(c.capacity, 0) {
+		c.evict()
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *RandomCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// evict removes a random entry and reports it
+func (c *RandomCache[K, V]) evict() {
+	victim := c.entries[c.rand.Intn(len(c.entries))]
+	c.remove(victim.key)
+	c.onEvict.report(victim.key, victim.value)
+}
+
+// remove deletes the cached key by moving the last entry into its slot
+func (c *RandomCache[K, V]) remove(key K) {
+	i := c.items[key]
+	last := len(c.entries) - 1
+	if i != last {
+		c.entries[i] = c.entries[last]
+		c.items[c.entries[i].key] = i
+	}
+	c.entries[last] = entry[K, V]{}
+	c.entries = c.entries[:last]
+	delete(c.items, key)
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *RandomCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *RandomCache[K, V]) get(key K) (V, error) {
+	i, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	return c.entries[i].value, nil
+}
+
+func (c *RandomCache[K, V]) set(key K, value V) error {
+	if i, ok := c.items[key]; ok {
+		c.entries[i].value = value
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if len(c.entries) >= c.capacity {
+		c.evict()
+	}
+	c.items[key] = len(c.entries)
+	c.entries = append(c.entries, entry[K, V]{key: key, value: value})
+	return nil
+}
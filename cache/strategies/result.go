@@ -0,0 +1,7 @@
+package strategies
+
+// Result is the outcome of a single lookup in a batch
+type Result[V any] struct {
+	Value V
+	Err   error
+}
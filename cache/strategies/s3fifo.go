@@ -0,0 +1,274 @@
+package strategies
+
+import (
+	"container/list"
+	"sync"
+)
+
+// s3MaxFreq caps the access counter of S3-FIFO entries
+const s3MaxFreq = 3
+
+// S3FIFOCache implements S3-FIFO. New entries enter a small FIFO queue of
+// about 10% of the capacity; the rest goes to a main FIFO queue. An entry
+// leaving the small queue moves to the main queue if it was read while
+// there, and is otherwise evicted with its key remembered in a ghost queue,
+// so that most one-hit wonders leave quickly. A key set again while
+// remembered enters the main queue directly. The main queue evicts like
+// CLOCK, with a counter of up to 3 reads instead of a single bit.
+type S3FIFOCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	smallCap int
+	ghostCap int
+	items    map[K]*list.Element
+	small    *list.List // front is the newest entry
+	main     *list.List // front is the newest entry
+	ghost    *list.List // keys, front is the newest
+	ghosts   map[K]*list.Element
+	onEvict  evictHook[K, V]
+}
+
+// s3Entry is an entry together with its read counter
+type s3Entry[K comparable, V any] struct {
+	key    K
+	value  V
+	freq   uint8
+	inMain bool
+}
+
+// NewS3FIFOCache creates an S3-FIFO cache holding at most capacity entries
+// and remembering as many evicted keys as its main queue holds entries
+func NewS3FIFOCache[K comparable, V any](capacity int) *S3FIFOCache[K, V] {
+	smallCap := max(capacity/10, 1)
+	return &S3FIFOCache[K, V]{
+		capacity: capacity,
+		smallCap: smallCap,
+		ghostCap: max(capacity-smallCap, 1),
+		items:    make(map[K]*list.Element),
+		small:    list.New(),
+		main:     list.New(),
+		ghost:    list.New(),
+		ghosts:   make(map[K]*list.Element),
+	}
+}
+
+// Get returns the value stored for key and counts the read
+func (c *S3FIFOCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key. Updating an existing key counts as a read; a
+// new key enters the small queue, or the main queue if the ghost queue
+// remembers it.
+func (c *S3FIFOCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *S3FIFOCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all entries in no particular order, without
+// counting as a read
+func (c *S3FIFOCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *S3FIFOCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries, ghost keys left out
+func (c *S3FIFOCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *S3FIFOCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *S3FIFOCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	c.queue(elem).Remove(elem)
+	delete(c.items, key)
+	return nil
+}
+
+// Clear removes all entries and forgets the ghost queue
+func (c *S3FIFOCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+	clear(c.ghosts)
+	c.small.Init()
+	c.main.Init()
+	c.ghost.Init()
+}
+
+// Resize changes the capacity to newCapacity and resizes the small and
+// ghost queues as NewS3FIFOCache does. It evicts as Set would until the
+// cache fits and forgets the oldest ghost keys beyond the new bound.
+func (c *S3FIFOCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	c.smallCap = max(newCapacity/10, 1)
+	c.ghostCap = max(newCapacity-c.smallCap, 1)
+	for c.small.Len()+c.main.Len() > max(c.capacity, 0) {
+		c.evict()
+	}
+	for c.ghost.Len() > c.ghostCap {
+		delete(c.ghosts, c.ghost.Remove(c.ghost.Back()).(K))
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *S3FIFOCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// evict removes one entry: from the small queue while it holds its share
+// of the capacity, or if the main queue is empty, and from the main queue
+// otherwise. Read entries leaving the small queue move to the main queue
+// instead; read entries leaving the main queue go round again with one
+// read less.
+func (c *S3FIFOCache[K, V]) evict() {
+	for {
+		if c.small.Len() >= c.smallCap || c.main.Len() == 0 {
+			elem := c.small.Back()
+			e := elem.Value.(*s3Entry[K, V])
+			c.small.Remove(elem)
+			if e.freq > 0 {
+				e.freq, e.inMain = 0, true
+				c.items[e.key] = c.main.PushFront(e)
+				continue
+			}
+			delete(c.items, e.key)
+			c.remember(e.key)
+			c.onEvict.report(e.key, e.value)
+			return
+		}
+		elem := c.main.Back()
+		e := elem.Value.(*s3Entry[K, V])
+		if e.freq > 0 {
+			e.freq--
+			c.main.MoveToFront(elem)
+			continue
+		}
+		c.main.Remove(elem)
+		delete(c.items, e.key)
+		c.onEvict.report(e.key, e.value)
+		return
+	}
+}
+
+// remember adds key to the ghost queue, forgetting the oldest key if full
+func (c *S3FIFOCache[K, V]) remember(key K) {
+	c.ghosts[key] = c.ghost.PushFront(key)
+	if c.ghost.Len() > c.ghostCap {
+		delete(c.ghosts, c.ghost.Remove(c.ghost.Back()).(K))
+	}
+}
+
+// queue returns the list holding elem
+func (c *S3FIFOCache[K, V]) queue(elem *list.Element) *list.List {
+	if elem.Value.(*s3Entry[K, V]).inMain {
+		return c.main
+	}
+	return c.small
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *S3FIFOCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *S3FIFOCache[K, V]) get(key K) (V, error) {
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	e := elem.Value.(*s3Entry[K, V])
+	e.freq = min(e.freq+1, s3MaxFreq)
+	return e.value, nil
+}
+
+func (c *S3FIFOCache[K, V]) set(key K, value V) error {
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*s3Entry[K, V])
+		e.value = value
+		e.freq = min(e.freq+1, s3MaxFreq)
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if c.small.Len()+c.main.Len() >= c.capacity {
+		c.evict()
+	}
+	e := &s3Entry[K, V]{key: key, value: value}
+	if elem, ok := c.ghosts[key]; ok {
+		c.ghost.Remove(elem)
+		delete(c.ghosts, key)
+		e.inMain = true
+		c.items[key] = c.main.PushFront(e)
+		return nil
+	}
+	c.items[key] = c.small.PushFront(e)
+	return nil
+}
@@ -0,0 +1,205 @@
+package strategies
+
+import (
+	"container/list"
+	"sync"
+)
+
+// SecondChanceCache implements FIFO with a second chance. Entries are
+// evicted in insertion order, except that an entry read since it entered
+// the queue is moved to the back once, its read bit cleared, instead of
+// being evicted. It behaves like CLOCK, with a queue in place of the ring.
+type SecondChanceCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[K]*list.Element
+	queue    *list.List // front is the next entry to consider for eviction
+	onEvict  evictHook[K, V]
+}
+
+// secondChanceEntry is an entry together with its read bit
+type secondChanceEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	accessed bool
+}
+
+// NewSecondChanceCache creates a second chance FIFO cache holding at most
+// capacity entries
+func NewSecondChanceCache[K comparable, V any](capacity int) *SecondChanceCache[K, V] {
+	return &SecondChanceCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element),
+		queue:    list.New(),
+	}
+}
+
+// Get returns the value stored for key and sets its read bit
+func (c *SecondChanceCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key. Updating an existing key keeps its position in
+// the queue and its read bit; inserting a new key into a full cache evicts
+// the first entry of the queue not read since it was last enqueued.
+func (c *SecondChanceCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *SecondChanceCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all entries in no particular order, without
+// setting their read bits
+func (c *SecondChanceCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *SecondChanceCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries
+func (c *SecondChanceCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *SecondChanceCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *SecondChanceCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	c.queue.Remove(elem)
+	delete(c.items, key)
+	return nil
+}
+
+// Clear removes all entries
+func (c *SecondChanceCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+	c.queue.Init()
+}
+
+// Resize changes the capacity to newCapacity, evicting entries in policy
+// order until the cache fits
+func (c *SecondChanceCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	for c.queue.Len() > max(c.capacity, 0) {
+		c.evict()
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *SecondChanceCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// evict re-enqueues read entries from the front of the queue until it finds
+// one that was not read, and removes that one. It ends within one pass over
+// the queue since re-enqueued entries lose their read bit.
+func (c *SecondChanceCache[K, V]) evict() {
+	for {
+		elem := c.queue.Front()
+		e := elem.Value.(*secondChanceEntry[K, V])
+		if e.accessed {
+			e.accessed = false
+			c.queue.MoveToBack(elem)
+			continue
+		}
+		c.queue.Remove(elem)
+		delete(c.items, e.key)
+		c.onEvict.report(e.key, e.value)
+		return
+	}
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *SecondChanceCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *SecondChanceCache[K, V]) get(key K) (V, error) {
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	e := elem.Value.(*secondChanceEntry[K, V])
+	e.accessed = true
+	return e.value, nil
+}
+
+func (c *SecondChanceCache[K, V]) set(key K, value V) error {
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*secondChanceEntry[K, V]).value = value
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if c.queue.Len() >= c.capacity {
+		c.evict()
+	}
+	c.items[key] = c.queue.PushBack(&secondChanceEntry[K, V]{key: key, value: value})
+	return nil
+}
@@ -0,0 +1,220 @@
+package strategies
+
+import (
+	"container/list"
+	"sync"
+)
+
+// SieveCache implements SIEVE. Entries are queued in insertion order and
+// hits only set a visited bit, so they never move. To make room, a hand
+// walks from the oldest towards the newest entry, clearing visited bits,
+// and evicts the first unvisited entry it meets; the hand stays there for
+// the next eviction and wraps around to the oldest entry at the end. New
+// entries thus get evicted quickly unless they are reused, while popular
+// old entries stay put.
+type SieveCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[K]*list.Element
+	queue    *list.List    // front is the newest entry
+	hand     *list.Element // next entry to examine, nil to start at the back
+	onEvict  evictHook[K, V]
+}
+
+// sieveEntry is an entry together with its visited bit
+type sieveEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	visited bool
+}
+
+// NewSieveCache creates a SIEVE cache holding at most capacity entries
+func NewSieveCache[K comparable, V any](capacity int) *SieveCache[K, V] {
+	return &SieveCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element),
+		queue:    list.New(),
+	}
+}
+
+// Get returns the value stored for key and marks it as visited
+func (c *SieveCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key. Updating an existing key marks it as visited;
+// inserting a new key into a full cache evicts the first unvisited entry
+// from the hand.
+func (c *SieveCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *SieveCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all entries in no particular order, without
+// marking them as visited
+func (c *SieveCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *SieveCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries
+func (c *SieveCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *SieveCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *SieveCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	c.remove(elem)
+	return nil
+}
+
+// Clear removes all entries
+func (c *SieveCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+	c.queue.Init()
+	c.hand = nil
+}
+
+// Resize changes the capacity to newCapacity, evicting entries in policy
+// order until the cache fits
+func (c *SieveCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	for c.queue.Len() > max(c.capacity, 0) {
+		c.evict()
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *SieveCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// evict moves the hand to the first unvisited entry, clearing the visited
+// bits on its way, and removes that entry
+func (c *SieveCache[K, V]) evict() {
+	elem := c.hand
+	if elem == nil {
+		elem = c.queue.Back()
+	}
+	for elem.Value.(*sieveEntry[K, V]).visited {
+		elem.Value.(*sieveEntry[K, V]).visited = false
+		if elem = elem.Prev(); elem == nil {
+			elem = c.queue.Back()
+		}
+	}
+	e := elem.Value.(*sieveEntry[K, V])
+	c.hand = elem
+	c.remove(elem)
+	c.onEvict.report(e.key, e.value)
+}
+
+// remove unlinks elem, moving the hand on to the next newer entry if it
+// pointed at elem
+func (c *SieveCache[K, V]) remove(elem *list.Element) {
+	if c.hand == elem {
+		c.hand = elem.Prev()
+	}
+	c.queue.Remove(elem)
+	delete(c.items, elem.Value.(*sieveEntry[K, V]).key)
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *SieveCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *SieveCache[K, V]) get(key K) (V, error) {
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	e := elem.Value.(*sieveEntry[K, V])
+	e.visited = true
+	return e.value, nil
+}
+
+func (c *SieveCache[K, V]) set(key K, value V) error {
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*sieveEntry[K, V])
+		e.value = value
+		e.visited = true
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if c.queue.Len() >= c.capacity {
+		c.evict()
+	}
+	c.items[key] = c.queue.PushFront(&sieveEntry[K, V]{key: key, value: value})
+	return nil
+}
@@ -0,0 +1,253 @@
+package strategies
+
+import (
+	"container/list"
+	"sync"
+)
+
+// SLRUCache implements Segmented LRU. New entries enter a probationary
+// segment and move to a protected segment on their second hit, so a scan of
+// entries used once only churns the probationary segment. Both segments are
+// LRU lists; an entry pushed out of a full protected segment goes back to
+// probation instead of leaving the cache.
+type SLRUCache[K comparable, V any] struct {
+	mu           sync.Mutex
+	probationCap int
+	protectedCap int
+	share        float64 // protected share of the capacity, kept by Resize
+	items        map[K]*list.Element
+	probation    *list.List // front is the most recently used entry
+	protected    *list.List // front is the most recently used entry
+	onEvict      evictHook[K, V]
+}
+
+// slruEntry is an entry together with the segment it belongs to
+type slruEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	protected bool
+}
+
+// NewSLRUCache creates an SLRU cache holding at most probationaryCap
+// entries on probation and protectedCap protected ones
+func NewSLRUCache[K comparable, V any](probationaryCap, protectedCap int) *SLRUCache[K, V] {
+	var share float64
+	if total := probationaryCap + protectedCap; total > 0 {
+		share = float64(protectedCap) / float64(total)
+	}
+	return &SLRUCache[K, V]{
+		probationCap: probationaryCap,
+		protectedCap: protectedCap,
+		share:        share,
+		items:        make(map[K]*list.Element),
+		probation:    list.New(),
+		protected:    list.New(),
+	}
+}
+
+// Get returns the value stored for key, promoting it to the protected
+// segment if it was on probation
+func (c *SLRUCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key. Updating an existing key counts as a hit;
+// inserting a new key puts it on probation, evicting the least recently
+// used entry on probation if the segment is full.
+func (c *SLRUCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *SLRUCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all entries in no particular order, without
+// counting as an access
+func (c *SLRUCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *SLRUCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries
+func (c *SLRUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *SLRUCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.probationCap + c.protectedCap
+}
+
+// Delete removes key from the cache
+func (c *SLRUCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	c.segment(elem).Remove(elem)
+	delete(c.items, key)
+	return nil
+}
+
+// Clear removes all entries
+func (c *SLRUCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+	c.probation.Init()
+	c.protected.Init()
+}
+
+// Resize changes the capacity to newCapacity, splitting it between the
+// segments in the proportion NewSLRUCache was given while leaving room on
+// probation. Protected entries beyond the new bound go back to probation,
+// which then evicts its least recently used entries until the cache fits.
+func (c *SLRUCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	newCapacity = max(newCapacity, 0)
+	protectedCap := 0
+	if newCapacity > 0 {
+		protectedCap = min(int(float64(newCapacity)*c.share), newCapacity-1)
+	}
+	c.probationCap, c.protectedCap = newCapacity-protectedCap, protectedCap
+	for c.protected.Len() > c.protectedCap {
+		c.demote()
+	}
+	c.trimProbation()
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *SLRUCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// hit marks elem as most recently used, promoting it out of probation. A
+// protected segment overflowing as a result demotes its least recently used
+// entry to probation.
+func (c *SLRUCache[K, V]) hit(elem *list.Element) {
+	e := elem.Value.(*slruEntry[K, V])
+	if e.protected {
+		c.protected.MoveToFront(elem)
+		return
+	}
+	if c.protectedCap <= 0 {
+		c.probation.MoveToFront(elem)
+		return
+	}
+	c.probation.Remove(elem)
+	e.protected = true
+	c.items[e.key] = c.protected.PushFront(e)
+	if c.protected.Len() > c.protectedCap {
+		c.demote()
+		c.trimProbation()
+	}
+}
+
+// demote moves the least recently used protected entry back to probation
+func (c *SLRUCache[K, V]) demote() {
+	e := c.protected.Remove(c.protected.Back()).(*slruEntry[K, V])
+	e.protected = false
+	c.items[e.key] = c.probation.PushFront(e)
+}
+
+// trimProbation evicts the least recently used entries on probation until
+// the segment fits its capacity
+func (c *SLRUCache[K, V]) trimProbation() {
+	for c.probation.Len() > c.probationCap {
+		e := c.probation.Remove(c.probation.Back()).(*slruEntry[K, V])
+		delete(c.items, e.key)
+		c.onEvict.report(e.key, e.value)
+	}
+}
+
+// segment returns the list holding elem
+func (c *SLRUCache[K, V]) segment(elem *list.Element) *list.List {
+	if elem.Value.(*slruEntry[K, V]).protected {
+		return c.protected
+	}
+	return c.probation
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *SLRUCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *SLRUCache[K, V]) get(key K) (V, error) {
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	value := elem.Value.(*slruEntry[K, V]).value
+	c.hit(elem)
+	return value, nil
+}
+
+func (c *SLRUCache[K, V]) set(key K, value V) error {
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*slruEntry[K, V]).value = value
+		c.hit(elem)
+		return nil
+	}
+	if c.probationCap <= 0 {
+		return ErrCacheFull
+	}
+	c.items[key] = c.probation.PushFront(&slruEntry[K, V]{key: key, value: value})
+	c.trimProbation()
+	return nil
+}
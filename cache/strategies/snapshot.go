@@ -0,0 +1,263 @@
+package strategies
+
+import (
+	"container/list"
+	"time"
+)
+
+// The Snapshot methods capture the entries of a cache in eviction order, the
+// next victim first and pinned entries last, together with the policy
+// metadata deciding that order. Restore swaps the whole contents of a cache
+// for such a snapshot, reporting the replaced entries as cleared, so that
+// the restored cache evicts its entries in the order they were captured.
+// Restore fails with ErrTooManyPinned or ErrCacheFull, leaving the cache
+// untouched, when the snapshot does not fit, and expects distinct keys as
+// returned by Snapshot.
+
+// SnapshotEntry is an entry captured by Snapshot. The metadata fields only
+// apply to some policies and are left zero by the others.
+type SnapshotEntry[K comparable, V any] struct {
+	Key       K
+	Value     V
+	Pinned    bool      // kept out of reach of the policy
+	Frequency int       // LFU access frequency
+	Frequent  bool      // ARC entry in the frequency list T2
+	ExpiresAt time.Time // TTL expiry
+}
+
+// checkSnapshot reports whether entries fit into a cache of capacity entries
+// that keeps pinned entries apart
+func checkSnapshot[K comparable, V any](entries []SnapshotEntry[K, V], capacity int) error {
+	pinned := 0
+	for _, e := range entries {
+		if e.Pinned {
+			pinned++
+		}
+	}
+	if pinned > max(capacity, 0) {
+		return ErrTooManyPinned
+	}
+	if len(entries)-pinned > max(capacity, 0) {
+		return ErrCacheFull
+	}
+	return nil
+}
+
+// restorePinned pins the entries of entries marked as such
+func restorePinned[K comparable, V any](pinned *map[K]V, entries []SnapshotEntry[K, V]) {
+	for _, e := range entries {
+		if !e.Pinned {
+			continue
+		}
+		if *pinned == nil {
+			*pinned = make(map[K]V)
+		}
+		(*pinned)[e.Key] = e.Value
+	}
+}
+
+// Snapshot returns the entries in insertion order
+func (c *FIFOCache[K, V]) Snapshot() []SnapshotEntry[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entries := make([]SnapshotEntry[K, V], 0, len(c.items)+len(c.pinned))
+	c.walk(func(key K, value V) bool {
+		_, pinned := c.pinned[key]
+		entries = append(entries, SnapshotEntry[K, V]{Key: key, Value: value, Pinned: pinned})
+		return true
+	})
+	return entries
+}
+
+// Restore replaces the contents of the cache with entries
+func (c *FIFOCache[K, V]) Restore(entries []SnapshotEntry[K, V]) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if err := checkSnapshot(entries, c.capacity); err != nil {
+		return err
+	}
+	c.reset()
+	for _, e := range entries {
+		if !e.Pinned {
+			c.items[e.Key] = c.queue.PushBack(&entry[K, V]{key: e.Key, value: e.Value})
+		}
+	}
+	restorePinned(&c.pinned, entries)
+	return nil
+}
+
+// Snapshot returns the entries from the least to the most recently used
+func (c *LRUCache[K, V]) Snapshot() []SnapshotEntry[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entries := make([]SnapshotEntry[K, V], 0, len(c.items)+len(c.pinned))
+	c.walk(func(key K, value V) bool {
+		_, pinned := c.pinned[key]
+		entries = append(entries, SnapshotEntry[K, V]{Key: key, Value: value, Pinned: pinned})
+		return true
+	})
+	return entries
+}
+
+// Restore replaces the contents of the cache with entries
+func (c *LRUCache[K, V]) Restore(entries []SnapshotEntry[K, V]) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if err := checkSnapshot(entries, c.capacity); err != nil {
+		return err
+	}
+	c.reset()
+	for _, e := range entries {
+		if !e.Pinned {
+			_ = c.set(e.Key, e.Value)
+		}
+	}
+	restorePinned(&c.pinned, entries)
+	return nil
+}
+
+// Snapshot returns the entries with their frequency, from the lowest to the
+// highest one and the least recently used first among equal frequencies
+func (c *LFUCache[K, V]) Snapshot() []SnapshotEntry[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entries := make([]SnapshotEntry[K, V], 0, len(c.items)+len(c.pinned))
+	c.walk(func(key K, value V) bool {
+		e := SnapshotEntry[K, V]{Key: key, Value: value}
+		if elem, ok := c.items[key]; ok {
+			e.Frequency = elem.Value.(*lfuEntry[K, V]).bucket.Value.(*lfuBucket[K, V]).freq
+		} else {
+			e.Pinned = true
+		}
+		entries = append(entries, e)
+		return true
+	})
+	return entries
+}
+
+// Restore replaces the contents of the cache with entries. A frequency
+// below 1 counts as 1.
+func (c *LFUCache[K, V]) Restore(entries []SnapshotEntry[K, V]) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if err := checkSnapshot(entries, c.capacity); err != nil {
+		return err
+	}
+	c.reset()
+	c.accesses = 0
+	now := time.Now()
+	for _, e := range entries {
+		if e.Pinned {
+			continue
+		}
+		bucket := c.bucket(max(e.Frequency, 1))
+		entry := &lfuEntry[K, V]{key: e.Key, value: e.Value, bucket: bucket, created: now, accessed: now}
+		c.items[e.Key] = bucket.Value.(*lfuBucket[K, V]).entries.PushFront(entry)
+	}
+	restorePinned(&c.pinned, entries)
+	return nil
+}
+
+// bucket returns the bucket of freq, creating it if needed
+func (c *LFUCache[K, V]) bucket(freq int) *list.Element {
+	elem := c.freqs.Back()
+	for elem != nil && elem.Value.(*lfuBucket[K, V]).freq > freq {
+		elem = elem.Prev()
+	}
+	if elem != nil && elem.Value.(*lfuBucket[K, V]).freq == freq {
+		return elem
+	}
+	bucket := &lfuBucket[K, V]{freq: freq, entries: list.New()}
+	if elem == nil {
+		return c.freqs.PushFront(bucket)
+	}
+	return c.freqs.InsertAfter(bucket, elem)
+}
+
+// Snapshot returns the resident entries in the order of Keys, marking those
+// of T2. The ghost lists and the target size of T1 are not captured, so the
+// restored cache learns them afresh.
+func (c *ARCCache[K, V]) Snapshot() []SnapshotEntry[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entries := make([]SnapshotEntry[K, V], 0, c.t1.Len()+c.t2.Len()+len(c.pinned))
+	c.walk(func(key K, value V) bool {
+		e := SnapshotEntry[K, V]{Key: key, Value: value}
+		if elem, ok := c.items[key]; ok {
+			e.Frequent = elem.Value.(*arcEntry[K, V]).where == c.t2
+		} else {
+			e.Pinned = true
+		}
+		entries = append(entries, e)
+		return true
+	})
+	return entries
+}
+
+// Restore replaces the contents of the cache with entries, keeping their
+// order within T1 and T2
+func (c *ARCCache[K, V]) Restore(entries []SnapshotEntry[K, V]) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if err := checkSnapshot(entries, c.capacity); err != nil {
+		return err
+	}
+	c.reset()
+	for _, e := range entries {
+		if e.Pinned {
+			continue
+		}
+		l := c.t1
+		if e.Frequent {
+			l = c.t2
+		}
+		c.items[e.Key] = l.PushFront(&arcEntry[K, V]{key: e.Key, value: e.Value, where: l})
+		c.accesses.record(e.Key)
+	}
+	restorePinned(&c.pinned, entries)
+	return nil
+}
+
+// Snapshot returns the live entries from the least to the most recently
+// refreshed, with their expiry
+func (c *TTLCache[K, V]) Snapshot() []SnapshotEntry[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entries := make([]SnapshotEntry[K, V], 0, len(c.items))
+	c.walk(func(key K, value V) bool {
+		expiresAt := c.items[key].Value.(*ttlEntry[K, V]).expiresAt
+		entries = append(entries, SnapshotEntry[K, V]{Key: key, Value: value, ExpiresAt: expiresAt})
+		return true
+	})
+	return entries
+}
+
+// Restore replaces the contents of the cache with entries, dropping those
+// already expired. Entries without an expiry get the default TTL. TTL caches
+// do not pin entries, so Pinned is ignored.
+func (c *TTLCache[K, V]) Restore(entries []SnapshotEntry[K, V]) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if len(entries) > max(c.capacity, 0) {
+		return ErrCacheFull
+	}
+	c.reset()
+	now := c.clock.Now()
+	for _, e := range entries {
+		if !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt) {
+			continue
+		}
+		_ = c.setExpiring(e.Key, e.Value, now, e.ExpiresAt)
+	}
+	return nil
+}
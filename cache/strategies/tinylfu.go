@@ -0,0 +1,304 @@
+package strategies
+
+import (
+	"container/list"
+	"sync"
+
+	"caching-labwork/cache/sketch"
+)
+
+// TinyLFUCache implements W-TinyLFU. New entries enter a small LRU window;
+// an entry leaving the window is admitted to the main space, a segmented
+// LRU, only if it was accessed more often than the entry the main space
+// would evict for it. Access frequencies are estimated by a Count-Min Sketch
+// recording every Get and Set, hits and misses alike, and halved after every
+// ten accesses per entry of capacity so that old popularity fades.
+//
+// About 1% of the capacity goes to the window; the main space keeps 80% of
+// the rest for the protected segment of entries hit at least twice.
+type TinyLFUCache[K comparable, V any] struct {
+	mu           sync.Mutex
+	windowCap    int
+	probationCap int
+	protectedCap int
+	items        map[K]*list.Element
+	window       *list.List // front is the most recently used entry
+	probation    *list.List // front is the most recently used entry
+	protected    *list.List // front is the most recently used entry
+	freqs        *sketch.CountMinSketch
+	accesses     int // recorded since the last halving
+	sampleSize   int
+	onEvict      evictHook[K, V]
+}
+
+// tinyLFUEntry is an entry together with the list it belongs to
+type tinyLFUEntry[K comparable, V any] struct {
+	key   K
+	value V
+	where *list.List
+}
+
+// NewTinyLFUCache creates a W-TinyLFU cache holding at most capacity
+// entries
+func NewTinyLFUCache[K comparable, V any](capacity int) *TinyLFUCache[K, V] {
+	windowCap := 0
+	if capacity > 0 {
+		windowCap = max(capacity/100, 1)
+	}
+	mainCap := max(capacity-windowCap, 0)
+	protectedCap := mainCap * 8 / 10
+	return &TinyLFUCache[K, V]{
+		windowCap:    windowCap,
+		probationCap: mainCap - protectedCap,
+		protectedCap: protectedCap,
+		items:        make(map[K]*list.Element),
+		window:       list.New(),
+		probation:    list.New(),
+		protected:    list.New(),
+		freqs:        sketch.NewCountMinSketch(max(4*capacity, 64), 4),
+		sampleSize:   max(10*capacity, 1),
+	}
+}
+
+// Get returns the value stored for key and records the access
+func (c *TinyLFUCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.record(key)
+	return c.get(key)
+}
+
+// Set stores value for key and records the access. A new key enters the
+// window, which may push its oldest entry through admission to the main
+// space.
+func (c *TinyLFUCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.record(key)
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way the access is recorded once.
+func (c *TinyLFUCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.record(key)
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all entries in no particular order, without
+// counting as an access
+func (c *TinyLFUCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *TinyLFUCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries
+func (c *TinyLFUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *TinyLFUCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.windowCap + c.probationCap + c.protectedCap
+}
+
+// Delete removes key from the cache. The frequency sketch keeps counting
+// its past accesses.
+func (c *TinyLFUCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	elem.Value.(*tinyLFUEntry[K, V]).where.Remove(elem)
+	delete(c.items, key)
+	return nil
+}
+
+// Clear removes all entries and resets the frequency sketch
+func (c *TinyLFUCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+	c.window.Init()
+	c.probation.Init()
+	c.protected.Init()
+	c.freqs.Reset()
+	c.accesses = 0
+}
+
+// Resize changes the capacity to newCapacity and splits it between the
+// window and the main space as NewTinyLFUCache does. Surplus protected
+// entries go back to probation, the least recently used probation entries
+// are evicted until the main space fits, and window entries beyond the new
+// bound go through admission. The recorded frequencies are kept.
+func (c *TinyLFUCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	newCapacity = max(newCapacity, 0)
+	c.windowCap = 0
+	if newCapacity > 0 {
+		c.windowCap = max(newCapacity/100, 1)
+	}
+	mainCap := max(newCapacity-c.windowCap, 0)
+	c.protectedCap = mainCap * 8 / 10
+	c.probationCap = mainCap - c.protectedCap
+	c.sampleSize = max(10*newCapacity, 1)
+	for c.protected.Len() > c.protectedCap {
+		c.demote()
+	}
+	for c.probation.Len()+c.protected.Len() > mainCap {
+		c.drop(c.probation.Remove(c.probation.Back()).(*tinyLFUEntry[K, V]))
+	}
+	for c.window.Len() > c.windowCap {
+		c.admit(c.window.Back())
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts, including new entries denied admission to the main space.
+// Explicit Delete and Clear calls are not reported. fn runs once the call
+// that evicted the entries has released the lock, so it may call back into
+// the cache, for instance to Delete related keys.
+func (c *TinyLFUCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// record counts an access to key, halving all frequencies once enough
+// accesses were sampled
+func (c *TinyLFUCache[K, V]) record(key K) {
+	c.freqs.AddHash(hashKey(key))
+	c.accesses++
+	if c.accesses >= c.sampleSize {
+		c.freqs.Halve()
+		c.accesses = 0
+	}
+}
+
+// frequency returns the estimated access count of key
+func (c *TinyLFUCache[K, V]) frequency(key K) uint64 {
+	return c.freqs.EstimateHash(hashKey(key))
+}
+
+// hit marks elem as most recently used in its list, promoting it to the
+// protected segment if it was on probation
+func (c *TinyLFUCache[K, V]) hit(elem *list.Element) {
+	e := elem.Value.(*tinyLFUEntry[K, V])
+	if e.where != c.probation || c.protectedCap <= 0 {
+		e.where.MoveToFront(elem)
+		return
+	}
+	c.probation.Remove(elem)
+	e.where = c.protected
+	c.items[e.key] = c.protected.PushFront(e)
+	if c.protected.Len() > c.protectedCap {
+		c.demote()
+	}
+}
+
+// demote moves the least recently used protected entry back to probation
+func (c *TinyLFUCache[K, V]) demote() {
+	e := c.protected.Remove(c.protected.Back()).(*tinyLFUEntry[K, V])
+	e.where = c.probation
+	c.items[e.key] = c.probation.PushFront(e)
+}
+
+// admit moves the candidate leaving the window to the main space. With the
+// main space full, the more frequent of the candidate and the main victim
+// stays and the other is evicted; ties favor the victim.
+func (c *TinyLFUCache[K, V]) admit(elem *list.Element) {
+	candidate := c.window.Remove(elem).(*tinyLFUEntry[K, V])
+	if c.probation.Len()+c.protected.Len() >= c.probationCap+c.protectedCap {
+		victims := c.probation
+		if victims.Len() == 0 {
+			victims = c.protected
+		}
+		victim := victims.Back()
+		if victim == nil || c.frequency(candidate.key) <= c.frequency(victim.Value.(*tinyLFUEntry[K, V]).key) {
+			c.drop(candidate)
+			return
+		}
+		c.drop(victims.Remove(victim).(*tinyLFUEntry[K, V]))
+	}
+	candidate.where = c.probation
+	c.items[candidate.key] = c.probation.PushFront(candidate)
+}
+
+// drop forgets e, which was already unlinked from its list, and reports it
+func (c *TinyLFUCache[K, V]) drop(e *tinyLFUEntry[K, V]) {
+	delete(c.items, e.key)
+	c.onEvict.report(e.key, e.value)
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *TinyLFUCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *TinyLFUCache[K, V]) get(key K) (V, error) {
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	c.hit(elem)
+	return elem.Value.(*tinyLFUEntry[K, V]).value, nil
+}
+
+func (c *TinyLFUCache[K, V]) set(key K, value V) error {
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*tinyLFUEntry[K, V]).value = value
+		c.hit(elem)
+		return nil
+	}
+	if c.windowCap <= 0 {
+		return ErrCacheFull
+	}
+	c.items[key] = c.window.PushFront(&tinyLFUEntry[K, V]{key: key, value: value, where: c.window})
+	if c.window.Len() > c.windowCap {
+		c.admit(c.window.Back())
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package strategies
+
+import "sort"
+
+// KeyCount is a key together with the number of times it was accessed
+type KeyCount[K comparable] struct {
+	Key   K
+	Count uint64
+}
+
+// WithAccessTracking makes FIFO and ARC caches count the accesses of every
+// resident key for TopKeys. LRU, LFU and TTL caches always count accesses
+// and ignore it.
+func WithAccessTracking() Option {
+	return func(o *options) {
+		o.tracking = true
+	}
+}
+
+// accessCounts counts the Set and Get hits of resident keys. A nil
+// accessCounts tracks nothing.
+type accessCounts[K comparable] map[K]uint64
+
+func newAccessCounts[K comparable](enabled bool) accessCounts[K] {
+	if !enabled {
+		return nil
+	}
+	return make(accessCounts[K])
+}
+
+func (a accessCounts[K]) record(key K) {
+	if a != nil {
+		a[key]++
+	}
+}
+
+func (a accessCounts[K]) keyCounts() []KeyCount[K] {
+	counts := make([]KeyCount[K], 0, len(a))
+	for key, count := range a {
+		counts = append(counts, KeyCount[K]{Key: key, Count: count})
+	}
+	return counts
+}
+
+// topKeys sorts counts by decreasing count and keeps the first n. Keys with
+// equal counts come in no particular order.
+func topKeys[K comparable](counts []KeyCount[K], n int) []KeyCount[K] {
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].Count > counts[j].Count
+	})
+	return counts[:min(max(n, 0), len(counts))]
+}
@@ -1,6 +1,1070 @@
 package strategies
 
+import (
+	"container/heap"
+	"container/list"
+	"sync"
+	"time"
+)
+
 // TTLCache implements a Time To Live cache
 type TTLCache[K comparable, V any] struct {
-	// TODO: Add necessary fields for TTL implementation
-}
\ No newline at end of file
+	mu        sync.Mutex
+	capacity  int
+	ttl       time.Duration
+	maxTTL    time.Duration // adaptive lifetime bound, unused if not above ttl
+	grace     time.Duration // how long expired entries stay available to GetSWR
+	items     map[K]*list.Element
+	queue     *list.List    // front is the least recently refreshed entry
+	expiries  ttlHeap[K, V] // entries by expiry, soonest first
+	onEvict   evictHook[K, V]
+	onMiss    func(key K)
+	watermark watermark
+	events    eventStream[K, V]
+	overflow  OverflowPolicy
+
+	clock    Clock
+	fifo     bool // overwrites keep the position and expiry of entries
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	refreshing map[K]struct{}         // keys with a GetSWR refresh in flight
+	ahead      map[K]*aheadRefresh[V] // keys registered with RefreshAhead
+}
+
+// aheadRefresh is the registration of a key with RefreshAhead
+type aheadRefresh[V any] struct {
+	loader   func() (V, error)
+	lead     time.Duration
+	accessed bool // read since the last refresh started
+	running  bool
+}
+
+// State tells apart the outcomes of a TTL cache lookup
+type State int
+
+// Lookup states reported by GetWithState
+const (
+	StateMissing State = iota
+	StateHit
+	StateExpired
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case StateHit:
+		return "hit"
+	case StateExpired:
+		return "expired"
+	default:
+		return "missing"
+	}
+}
+
+// ttlEntry is an entry together with its expiration time
+type ttlEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+	info      Info
+	index     int // position in the expiry heap
+}
+
+// NewTTLCache creates a TTL cache holding at most capacity entries, each
+// expiring ttl after it was last set
+func NewTTLCache[K comparable, V any](capacity int, ttl time.Duration, opts ...Option) *TTLCache[K, V] {
+	o := newOptions(opts)
+	c := &TTLCache[K, V]{
+		capacity: capacity,
+		events:   newEventStream[K, V](o.eventBuffer),
+		overflow: o.overflow,
+		ttl:      ttl,
+		items:    make(map[K]*list.Element),
+		queue:    list.New(),
+		stop:     make(chan struct{}),
+		clock:    o.clock,
+	}
+	if o.janitor > 0 {
+		go c.janitor(o.janitor)
+	}
+	return c
+}
+
+// NewFIFOTTLCache creates a cache bounded both by capacity, evicting in
+// insertion order, and by ttl counted from the first insertion of a key.
+// Overwriting a key updates its value but neither its position nor its
+// expiry, which suits fixed windows such as rate limits.
+func NewFIFOTTLCache[K comparable, V any](capacity int, ttl time.Duration, opts ...Option) *TTLCache[K, V] {
+	c := NewTTLCache[K, V](capacity, ttl, opts...)
+	c.fifo = true
+	return c
+}
+
+// NewAdaptiveTTLCache creates a TTL cache whose entries live longer the more
+// they are accessed. Every Set or Get hit restarts the lifetime of an entry
+// as baseTTL times its access count, bounded by maxTTL, so an entry that is
+// only set expires after baseTTL.
+func NewAdaptiveTTLCache[K comparable, V any](capacity int, baseTTL, maxTTL time.Duration, opts ...Option) *TTLCache[K, V] {
+	c := NewTTLCache[K, V](capacity, baseTTL, opts...)
+	c.maxTTL = maxTTL
+	return c
+}
+
+// Get returns the value stored for key. Expired entries are removed and
+// reported as missing.
+func (c *TTLCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	value, err := c.get(key)
+	onMiss := c.onMiss
+	c.unlock()
+
+	if err != nil && onMiss != nil {
+		onMiss(key)
+	}
+	return value, err
+}
+
+// GetWithState is like Get but also reports why a lookup missed. An expired
+// entry is removed like in Get, and its stale value is returned together
+// with StateExpired and ErrKeyNotFound.
+func (c *TTLCache[K, V]) GetWithState(key K) (V, State, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, StateMissing, ErrKeyNotFound
+	}
+	e := elem.Value.(*ttlEntry[K, V])
+	if e.expired(c.clock.Now()) {
+		stale := e.value
+		_, err := c.get(key)
+		return stale, StateExpired, err
+	}
+	value, err := c.get(key)
+	return value, StateHit, err
+}
+
+// GetBatch looks up all keys under a single lock. The results are aligned
+// with keys, including duplicates; misses carry ErrKeyNotFound.
+func (c *TTLCache[K, V]) GetBatch(keys []K) []Result[V] {
+	c.mu.Lock()
+	results := make([]Result[V], len(keys))
+	for i, key := range keys {
+		results[i].Value, results[i].Err = c.get(key)
+	}
+	onMiss := c.onMiss
+	c.unlock()
+
+	if onMiss != nil {
+		for i, key := range keys {
+			if results[i].Err != nil {
+				onMiss(key)
+			}
+		}
+	}
+	return results
+}
+
+// Set stores value for key and restarts its TTL. Inserting a new key into a
+// full cache first drops expired entries and then evicts the least recently
+// refreshed one.
+func (c *TTLCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// Lock stores key with the zero value and a lifetime of ttl if key is
+// missing or expired, and reports whether it did. The key then acts as a
+// lock held until Unlock or until it expires. Like any entry, a held lock
+// may be evicted when the cache runs out of capacity.
+func (c *TTLCache[K, V]) Lock(key K, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	if elem, ok := c.items[key]; ok {
+		if !elem.Value.(*ttlEntry[K, V]).expired(now) {
+			return false
+		}
+		c.evict(elem, ReasonExpired)
+	}
+	var zero V
+	return c.setExpiring(key, zero, now, now.Add(ttl)) == nil
+}
+
+// Unlock releases a lock taken by Lock. It returns ErrKeyNotFound if the
+// lock is not held, for instance because it expired.
+func (c *TTLCache[K, V]) Unlock(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.deleteKey(key)
+}
+
+// SetManyWithTTL stores all entries in a single locked pass. They share one
+// expiration time, computed once as now plus ttl, instead of the default
+// TTL. With RejectExcess, more entries than the capacity fail with
+// ErrCacheFull before anything is stored. Otherwise it stops at the first
+// failing insert and returns its error.
+func (c *TTLCache[K, V]) SetManyWithTTL(entries map[K]V, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.overflow == RejectExcess && len(entries) > c.capacity {
+		return ErrCacheFull
+	}
+	now := c.clock.Now()
+	expiresAt := now.Add(ttl)
+	for key, value := range entries {
+		if err := c.setExpiring(key, value, now, expiresAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EvictionEvents returns the stream of removed entries enabled by
+// WithEvictionEvents, or nil if the cache was created without it
+func (c *TTLCache[K, V]) EvictionEvents() <-chan EvictionEvent[K, V] {
+	return c.events
+}
+
+// Expiry returns when the entry stored for key expires
+func (c *TTLCache[K, V]) Expiry(key K) (time.Time, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok || elem.Value.(*ttlEntry[K, V]).expired(c.clock.Now()) {
+		return time.Time{}, ErrKeyNotFound
+	}
+	return elem.Value.(*ttlEntry[K, V]).expiresAt, nil
+}
+
+// SetGracePeriod sets how long entries stay available to GetSWR after they
+// expired. Expired entries remain invisible to every other method.
+func (c *TTLCache[K, V]) SetGracePeriod(grace time.Duration) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.grace = grace
+}
+
+// GetSWR returns the value stored for key following stale-while-revalidate:
+// a fresh entry is returned as is; an entry expired less than the grace
+// period ago is returned with stale set while a single background goroutine
+// reloads it; otherwise loader runs synchronously and its value is stored.
+// A failed background reload keeps the stale entry until the grace period
+// ends, and one finishing after key was removed is dropped.
+func (c *TTLCache[K, V]) GetSWR(key K, loader func() (V, error)) (value V, stale bool, err error) {
+	c.mu.Lock()
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*ttlEntry[K, V])
+		now := c.clock.Now()
+		if !e.expired(now) {
+			value, err = c.get(key)
+			c.unlock()
+			return value, false, err
+		}
+		if !c.dead(e, now) {
+			value = e.value
+			if _, ok := c.refreshing[key]; !ok {
+				if c.refreshing == nil {
+					c.refreshing = make(map[K]struct{})
+				}
+				c.refreshing[key] = struct{}{}
+				go c.refresh(key, loader)
+			}
+			c.unlock()
+			return value, true, nil
+		}
+	}
+	c.unlock()
+
+	value, err = loader()
+	if err != nil {
+		var zero V
+		return zero, false, err
+	}
+	c.mu.Lock()
+	defer c.unlock()
+	return value, false, c.set(key, value)
+}
+
+// refresh reloads key in the background on behalf of GetSWR
+func (c *TTLCache[K, V]) refresh(key K, loader func() (V, error)) {
+	value, err := loader()
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	delete(c.refreshing, key)
+	if _, ok := c.items[key]; ok && err == nil {
+		_ = c.set(key, value)
+	}
+}
+
+// RefreshAhead registers key to be reloaded with loader leadTime before it
+// expires, so that readers of a hot key never see it expire. A refresh
+// replaces the value and restarts its lifetime; a failed one keeps the
+// current value. Refreshes start from Get hits within the lead time and from
+// the janitor, if enabled with WithJanitor. The registration ends once key
+// is removed, or when it comes due without having been read since the
+// previous refresh. Registering key again replaces its loader and lead time.
+// FIFO TTL caches keep the expiry of overwritten entries, so on them a
+// refresh only replaces the value.
+func (c *TTLCache[K, V]) RefreshAhead(key K, loader func() (V, error), leadTime time.Duration) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.ahead == nil {
+		c.ahead = make(map[K]*aheadRefresh[V])
+	}
+	c.ahead[key] = &aheadRefresh[V]{loader: loader, lead: leadTime, accessed: true}
+}
+
+// refreshAhead starts the refreshes that came due and drops the
+// registrations of keys gone or no longer read
+func (c *TTLCache[K, V]) refreshAhead(now time.Time) {
+	for key, r := range c.ahead {
+		elem, ok := c.items[key]
+		if !ok || elem.Value.(*ttlEntry[K, V]).expired(now) {
+			delete(c.ahead, key)
+			continue
+		}
+		c.startRefreshAhead(key, elem.Value.(*ttlEntry[K, V]), r, now)
+	}
+}
+
+// startRefreshAhead reloads e in the background if its refresh is due, or
+// drops its registration if it wasn't read since the previous refresh
+func (c *TTLCache[K, V]) startRefreshAhead(key K, e *ttlEntry[K, V], r *aheadRefresh[V], now time.Time) {
+	if r.running || now.Before(e.expiresAt.Add(-r.lead)) {
+		return
+	}
+	if !r.accessed {
+		delete(c.ahead, key)
+		return
+	}
+	r.running, r.accessed = true, false
+	go func() {
+		value, err := r.loader()
+
+		c.mu.Lock()
+		defer c.unlock()
+
+		r.running = false
+		if err != nil || c.ahead[key] != r {
+			return
+		}
+		if _, ok := c.peek(key); ok {
+			_ = c.set(key, value)
+		}
+	}()
+}
+
+func (c *TTLCache[K, V]) set(key K, value V) error {
+	return c.setExpiring(key, value, c.clock.Now(), time.Time{})
+}
+
+// setExpiring stores value for key. A non-zero expiresAt overrides the
+// lifetime the cache would give the entry.
+func (c *TTLCache[K, V]) setExpiring(key K, value V, now, expiresAt time.Time) error {
+	elem, ok := c.items[key]
+	if ok && c.fifo && elem.Value.(*ttlEntry[K, V]).expired(now) {
+		// Overwrites keep the expiry in FIFO mode, so an expired entry the
+		// janitor has not removed yet makes way for a fresh one
+		c.evict(elem, ReasonExpired)
+		ok = false
+	}
+	if ok {
+		e := elem.Value.(*ttlEntry[K, V])
+		e.value = value
+		e.info.touch(now)
+		if c.fifo {
+			return nil
+		}
+		e.expiresAt = expiresAt
+		if expiresAt.IsZero() {
+			e.expiresAt = now.Add(c.lifetime(e.info.AccessCount))
+		}
+		heap.Fix(&c.expiries, e.index)
+		c.queue.MoveToBack(elem)
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	c.removeExpired(now)
+	if c.queue.Len() >= c.capacity {
+		c.evict(c.queue.Front(), ReasonCapacity)
+	}
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(c.ttl)
+	}
+	e := &ttlEntry[K, V]{key: key, value: value, expiresAt: expiresAt, info: Info{CreatedAt: now}}
+	e.info.touch(now)
+	c.items[key] = c.queue.PushBack(e)
+	heap.Push(&c.expiries, e)
+	return nil
+}
+
+// EntryInfo returns the metadata of the entry stored for key without
+// counting as an access
+func (c *TTLCache[K, V]) EntryInfo(key K) (Info, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return Info{}, ErrKeyNotFound
+	}
+	e := elem.Value.(*ttlEntry[K, V])
+	if now := c.clock.Now(); e.expired(now) {
+		if c.dead(e, now) {
+			c.evict(elem, ReasonExpired)
+		}
+		return Info{}, ErrKeyNotFound
+	}
+	return e.info, nil
+}
+
+// TopKeys returns the n most accessed live keys, most accessed first,
+// counting Set and Get hits since the key entered the cache
+func (c *TTLCache[K, V]) TopKeys(n int) []KeyCount[K] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	counts := make([]KeyCount[K], 0, len(c.items))
+	for key, elem := range c.items {
+		if e := elem.Value.(*ttlEntry[K, V]); !e.expired(now) {
+			counts = append(counts, KeyCount[K]{Key: key, Count: e.info.AccessCount})
+		}
+	}
+	return topKeys(counts, n)
+}
+
+// Add stores value for key only if key is not cached yet, and fails with
+// ErrKeyExists otherwise
+func (c *TTLCache[K, V]) Add(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.peek(key); ok {
+		return ErrKeyExists
+	}
+	return c.set(key, value)
+}
+
+// SetIfPresent stores value for key only if key is cached, and fails with
+// ErrKeyNotFound otherwise. Like Set, it counts as an access.
+func (c *TTLCache[K, V]) SetIfPresent(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.peek(key); !ok {
+		return ErrKeyNotFound
+	}
+	return c.set(key, value)
+}
+
+// SetMany stores entries in order in a single locked pass, evicting as
+// usual after each insert. A batch with more keys than the capacity either
+// leaves only its last keys resident or, with RejectExcess, fails with
+// ErrCacheFull before anything is stored. Otherwise SetMany stops at the
+// first failing insert and returns its error.
+func (c *TTLCache[K, V]) SetMany(entries []Entry[K, V]) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.overflow == RejectExcess && overflows[K, V](entries, c.capacity, nil) {
+		return ErrCacheFull
+	}
+	for _, e := range entries {
+		if err := c.set(e.Key, e.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Keys returns the keys of all live entries, least recently refreshed
+// first, which is the order they are evicted in, without counting as an
+// access
+func (c *TTLCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return entryKeys(c.ordered())
+}
+
+// Values returns the values of all entries in the order of Keys, without
+// counting as an access
+func (c *TTLCache[K, V]) Values() []V {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return entryValues(c.ordered())
+}
+
+// Items returns all entries in the order of Keys, without counting as an
+// access
+func (c *TTLCache[K, V]) Items() []Entry[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.ordered()
+}
+
+// All returns an iterator over the entries in the order of Keys, without
+// counting as accesses. Each iteration walks a snapshot taken when it
+// starts, so the loop body may call back into the cache and sees none of
+// its own changes. The iterator has the type of iter.Seq2[K, V], so that
+// with Go 1.23 or later it can be ranged over directly.
+func (c *TTLCache[K, V]) All() func(yield func(key K, value V) bool) {
+	return func(yield func(key K, value V) bool) {
+		for _, e := range c.Items() {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// ForEach calls fn with the entries in the order of Keys until fn returns
+// false, without counting as accesses. Unlike All it takes no snapshot: fn
+// runs while the cache is locked and must not call back into the cache.
+func (c *TTLCache[K, V]) ForEach(fn func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.walk(fn)
+}
+
+// ToMap returns a copy of all live entries without counting as an access
+func (c *TTLCache[K, V]) ToMap() map[K]V {
+	c.mu.Lock()
+	defer c.unlock()
+
+	m := make(map[K]V, len(c.items))
+	now := c.clock.Now()
+	for key, elem := range c.items {
+		if e := elem.Value.(*ttlEntry[K, V]); !e.expired(now) {
+			m[key] = e.value
+		}
+	}
+	return m
+}
+
+// FromMap inserts every entry of m as if by Set, so each entry gets the
+// default TTL and eviction applies as usual. It stops at the first failing
+// insert and returns its error.
+func (c *TTLCache[K, V]) FromMap(m map[K]V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for key, value := range m {
+		if err := c.set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update atomically replaces the value of key with fn(value, found), where
+// found reports whether key was present. The update counts as a single
+// access. fn runs while the cache is locked and must not call back into
+// the cache.
+func (c *TTLCache[K, V]) Update(key K, fn func(value V, found bool) V) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value := fn(c.peek(key))
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, err
+	}
+	return value, nil
+}
+
+// Contains reports whether key is stored and not expired, without counting
+// as an access
+func (c *TTLCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.peek(key)
+	return ok
+}
+
+// Peek returns the value stored for key like Get, but without counting as
+// an access, so the eviction order stays as it was
+func (c *TTLCache[K, V]) Peek(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, ok := c.peek(key)
+	if !ok {
+		return value, ErrKeyNotFound
+	}
+	return value, nil
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *TTLCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// GetOrCompute returns the value stored for key if there is one, and
+// otherwise calls fn and stores its result. An error from fn is returned
+// and nothing is stored. fn runs while the cache is unlocked, so it may be
+// slow or call back into the cache; concurrent misses on the same key each
+// call fn, and the first result stored is returned to all of them. Wrap the
+// cache in a SingleFlightCache to share a single call instead.
+func (c *TTLCache[K, V]) GetOrCompute(key K, fn func() (V, error)) (V, error) {
+	c.mu.Lock()
+	value, err := c.get(key)
+	c.unlock()
+	if err == nil {
+		return value, nil
+	}
+
+	value, err = fn()
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, ok := c.peek(key); ok {
+		return actual, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, err
+	}
+	return value, nil
+}
+
+// Len returns the number of entries that have not expired yet
+func (c *TTLCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	n := 0
+	for elem := c.queue.Front(); elem != nil; elem = elem.Next() {
+		if !elem.Value.(*ttlEntry[K, V]).expired(now) {
+			n++
+		}
+	}
+	return n
+}
+
+// Cap returns the maximum number of entries
+func (c *TTLCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *TTLCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.deleteKey(key)
+}
+
+// Pop removes key and returns the value it stored, or ErrKeyNotFound if key
+// is missing. The removal counts as a Delete, not as an access.
+func (c *TTLCache[K, V]) Pop(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, ok := c.peek(key)
+	if !ok {
+		return value, ErrKeyNotFound
+	}
+	return value, c.deleteKey(key)
+}
+
+// DeleteMany removes keys under a single lock and returns how many of them
+// were cached. Missing keys are skipped.
+func (c *TTLCache[K, V]) DeleteMany(keys []K) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	removed := 0
+	for _, key := range keys {
+		if c.deleteKey(key) == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// SetIf stores value for key only if key is cached and fn reports true for
+// its current value, counting as a single access. It returns ErrKeyNotFound
+// for a missing key and false without error when fn rejects the value. fn
+// runs while the cache is locked and must not call back into the cache.
+func (c *TTLCache[K, V]) SetIf(key K, value V, fn func(current V) bool) (bool, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	current, ok := c.peek(key)
+	if !ok {
+		return false, ErrKeyNotFound
+	}
+	if !fn(current) {
+		return false, nil
+	}
+	return true, c.set(key, value)
+}
+
+// DeleteIf removes key only if fn reports true for its current value. It
+// returns ErrKeyNotFound for a missing key and false without error when fn
+// rejects the value. fn runs while the cache is locked and must not call
+// back into the cache.
+func (c *TTLCache[K, V]) DeleteIf(key K, fn func(value V) bool) (bool, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, ok := c.peek(key)
+	if !ok {
+		return false, ErrKeyNotFound
+	}
+	if !fn(value) {
+		return false, nil
+	}
+	return true, c.deleteKey(key)
+}
+
+// DeleteFunc removes every entry for which fn reports true in a single
+// locked pass and returns how many entries it removed. fn runs while the
+// cache is locked and must not call back into the cache.
+func (c *TTLCache[K, V]) DeleteFunc(fn func(key K, value V) bool) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var keys []K
+	for key := range c.items {
+		if value, ok := c.peek(key); ok && fn(key, value) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		_ = c.deleteKey(key)
+	}
+	return len(keys)
+}
+
+// SetOnMiss registers fn to be called with every key a Get or GetBatch
+// misses, including expired keys. fn runs after the cache is unlocked, so it
+// may block or call back into the cache.
+func (c *TTLCache[K, V]) SetOnMiss(fn func(key K)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onMiss = fn
+}
+
+// SetWatermark registers onHigh to be called when the share of the capacity
+// in use reaches highPct percent, and onLow when it then drops below lowPct
+// percent. Each callback fires once per crossing; onHigh can only fire again
+// after onLow did. Either may be nil. The callbacks run once the cache is
+// unlocked, so they may call back into the cache.
+func (c *TTLCache[K, V]) SetWatermark(highPct, lowPct float64, onHigh, onLow func()) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.watermark = watermark{high: highPct, low: lowPct, onHigh: onHigh, onLow: onLow}
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts, including expired entries. Explicit Delete and Clear calls are not
+// reported. fn runs once the call that evicted the entries has released the
+// lock, so it may call back into the cache, for instance to Delete related
+// keys.
+func (c *TTLCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// Clear removes all entries
+func (c *TTLCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.reset()
+}
+
+// Resize changes the capacity to newCapacity. Shrinking removes expired
+// entries first, then the least recently refreshed ones until the cache
+// fits.
+func (c *TTLCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	c.removeExpired(c.clock.Now())
+	for c.queue.Len() > max(c.capacity, 0) {
+		c.evict(c.queue.Front(), ReasonCapacity)
+	}
+	return nil
+}
+
+// Replace atomically swaps the whole contents of the cache for entries,
+// inserted as if by Set, so readers see either the old or the new entries
+// but never a partially filled cache. A map has no order telling which of
+// its entries should survive an overflow, so more entries than the capacity
+// fail with ErrCacheFull and leave the cache untouched.
+func (c *TTLCache[K, V]) Replace(entries map[K]V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if len(entries) > max(c.capacity, 0) {
+		return ErrCacheFull
+	}
+	c.reset()
+	for key, value := range entries {
+		_ = c.set(key, value)
+	}
+	return nil
+}
+
+// lifetime returns how long an entry accessed the given number of times lives
+func (c *TTLCache[K, V]) lifetime(accesses uint64) time.Duration {
+	if c.maxTTL <= c.ttl || c.ttl <= 0 {
+		return c.ttl
+	}
+	if accesses >= uint64(c.maxTTL/c.ttl) {
+		return c.maxTTL
+	}
+	return c.ttl * time.Duration(accesses)
+}
+
+// DrainExpired removes and returns every entry past its expiry, oldest
+// first, ignoring the grace period. Each removal is reported to the eviction
+// callback and the event stream like any other expiration.
+func (c *TTLCache[K, V]) DrainExpired() []Entry[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var drained []Entry[K, V]
+	now := c.clock.Now()
+	for elem := c.queue.Front(); elem != nil; {
+		next := elem.Next()
+		if e := elem.Value.(*ttlEntry[K, V]); e.expired(now) {
+			drained = append(drained, Entry[K, V]{Key: e.key, Value: e.value})
+			c.evict(elem, ReasonExpired)
+		}
+		elem = next
+	}
+	return drained
+}
+
+// StopJanitor ends the background removal started by WithJanitor. Expired
+// entries are still removed lazily afterwards.
+func (c *TTLCache[K, V]) StopJanitor() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
+// janitor removes expired entries every interval until StopJanitor
+func (c *TTLCache[K, V]) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.mu.Lock()
+			now := c.clock.Now()
+			c.removeExpired(now)
+			c.refreshAhead(now)
+			c.unlock()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// removeExpired drops entries past their grace period, soonest expiry first.
+// Custom lifetimes leave the queue out of expiry order, so they are found
+// through the expiry heap.
+func (c *TTLCache[K, V]) removeExpired(now time.Time) {
+	for len(c.expiries) > 0 && c.dead(c.expiries[0], now) {
+		c.evict(c.items[c.expiries[0].key], ReasonExpired)
+	}
+}
+
+// ordered returns the entries in eviction order
+func (c *TTLCache[K, V]) ordered() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, len(c.items))
+	c.walk(func(key K, value V) bool {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+		return true
+	})
+	return entries
+}
+
+// walk calls fn with the entries in eviction order until it returns false
+func (c *TTLCache[K, V]) walk(fn func(key K, value V) bool) {
+	now := c.clock.Now()
+	for elem := c.queue.Front(); elem != nil; elem = elem.Next() {
+		if e := elem.Value.(*ttlEntry[K, V]); !e.expired(now) && !fn(e.key, e.value) {
+			return
+		}
+	}
+}
+
+// evict removes elem on behalf of the policy and reports it
+func (c *TTLCache[K, V]) evict(elem *list.Element, reason Reason) {
+	e := elem.Value.(*ttlEntry[K, V])
+	c.removeElement(elem)
+	c.events.emit(e.key, e.value, reason)
+	c.onEvict.report(e.key, e.value)
+}
+
+func (c *TTLCache[K, V]) removeElement(elem *list.Element) {
+	e := c.queue.Remove(elem).(*ttlEntry[K, V])
+	heap.Remove(&c.expiries, e.index)
+	delete(c.items, e.key)
+}
+
+func (e *ttlEntry[K, V]) expired(now time.Time) bool {
+	return !now.Before(e.expiresAt)
+}
+
+// dead reports whether e expired and its grace period is over as well
+func (c *TTLCache[K, V]) dead(e *ttlEntry[K, V], now time.Time) bool {
+	return !now.Before(e.expiresAt.Add(c.grace))
+}
+
+func (c *TTLCache[K, V]) get(key K) (V, error) {
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	e := elem.Value.(*ttlEntry[K, V])
+	now := c.clock.Now()
+	if e.expired(now) {
+		if c.dead(e, now) {
+			c.evict(elem, ReasonExpired)
+		}
+		delete(c.ahead, key)
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	e.info.touch(now)
+	if c.maxTTL > c.ttl {
+		e.expiresAt = now.Add(c.lifetime(e.info.AccessCount))
+		heap.Fix(&c.expiries, e.index)
+		c.queue.MoveToBack(elem)
+	}
+	if r, ok := c.ahead[key]; ok {
+		r.accessed = true
+		c.startRefreshAhead(key, e, r, now)
+	}
+	return e.value, nil
+}
+
+// peek returns the value of key without counting as an access
+func (c *TTLCache[K, V]) peek(key K) (V, bool) {
+	elem, ok := c.items[key]
+	if !ok || elem.Value.(*ttlEntry[K, V]).expired(c.clock.Now()) {
+		var zero V
+		return zero, false
+	}
+	return elem.Value.(*ttlEntry[K, V]).value, true
+}
+
+func (c *TTLCache[K, V]) deleteKey(key K) error {
+	elem, ok := c.items[key]
+	if !ok || elem.Value.(*ttlEntry[K, V]).expired(c.clock.Now()) {
+		if ok {
+			c.evict(elem, ReasonExpired)
+		}
+		return ErrKeyNotFound
+	}
+	e := elem.Value.(*ttlEntry[K, V])
+	c.removeElement(elem)
+	delete(c.ahead, key)
+	c.events.emit(e.key, e.value, ReasonDeleted)
+	return nil
+}
+
+// reset removes all entries
+func (c *TTLCache[K, V]) reset() {
+	if c.events != nil {
+		for elem := c.queue.Front(); elem != nil; elem = elem.Next() {
+			e := elem.Value.(*ttlEntry[K, V])
+			c.events.emit(e.key, e.value, ReasonCleared)
+		}
+	}
+	c.items = make(map[K]*list.Element)
+	c.queue.Init()
+	c.expiries = nil
+	c.ahead = nil
+}
+
+// unlock releases the lock, then reports the entries evicted while it was
+// held and any crossed watermark
+func (c *TTLCache[K, V]) unlock() {
+	crossed := c.watermark.check(c.queue.Len(), c.capacity)
+	c.onEvict.unlock(&c.mu)
+	if crossed != nil {
+		crossed()
+	}
+}
+
+// ttlHeap is a min-heap of entries by expiration time implementing
+// heap.Interface
+type ttlHeap[K comparable, V any] []*ttlEntry[K, V]
+
+func (h ttlHeap[K, V]) Len() int { return len(h) }
+
+func (h ttlHeap[K, V]) Less(i, j int) bool { return h[i].expiresAt.Before(h[j].expiresAt) }
+
+func (h ttlHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *ttlHeap[K, V]) Push(x any) {
+	e := x.(*ttlEntry[K, V])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *ttlHeap[K, V]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*h = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,263 @@
+package strategies
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Default 2Q tuning from the original paper
+const (
+	DefaultTwoQueueRecentRatio = 0.25
+	DefaultTwoQueueGhostRatio  = 0.5
+)
+
+// TwoQueueCache implements the full 2Q policy. New entries enter A1in, a
+// FIFO queue that hits leave alone. Entries pushed out of A1in are
+// remembered in A1out, a ghost queue of keys only; a key set again while
+// remembered there is deemed hot and enters Am, an LRU list holding the
+// rest of the capacity.
+type TwoQueueCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	size    int
+	kin     int        // target size of A1in
+	kout    int        // maximum size of A1out
+	ratios  [2]float64 // recent and ghost ratios kin and kout derive from
+	items   map[K]*list.Element
+	recent  *list.List // A1in, front is the newest entry
+	ghost   *list.List // A1out, front is the newest key
+	hot     *list.List // Am, front is the most recently used entry
+	onEvict evictHook[K, V]
+}
+
+// twoQueueEntry is an entry together with the queue it belongs to. Ghost
+// entries carry no value.
+type twoQueueEntry[K comparable, V any] struct {
+	key   K
+	value V
+	where *list.List
+}
+
+// NewTwoQueueCache creates a 2Q cache holding at most size entries with the
+// default ratios
+func NewTwoQueueCache[K comparable, V any](size int) *TwoQueueCache[K, V] {
+	return NewTwoQueueCacheWithRatios[K, V](size, DefaultTwoQueueRecentRatio, DefaultTwoQueueGhostRatio)
+}
+
+// NewTwoQueueCacheWithRatios creates a 2Q cache holding at most size
+// entries, where A1in targets recentRatio of size and A1out remembers up to
+// ghostRatio of size keys
+func NewTwoQueueCacheWithRatios[K comparable, V any](size int, recentRatio, ghostRatio float64) *TwoQueueCache[K, V] {
+	return &TwoQueueCache[K, V]{
+		size:   size,
+		kin:    int(float64(size) * recentRatio),
+		kout:   int(float64(size) * ghostRatio),
+		ratios: [2]float64{recentRatio, ghostRatio},
+		items:  make(map[K]*list.Element),
+		recent: list.New(),
+		ghost:  list.New(),
+		hot:    list.New(),
+	}
+}
+
+// Get returns the value stored for key. A hit in Am marks the entry as most
+// recently used; a hit in A1in changes nothing.
+func (c *TwoQueueCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key. A new key enters A1in, or Am if A1out remembers
+// it; making room evicts from A1in while it is above its target size and
+// from Am otherwise.
+func (c *TwoQueueCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *TwoQueueCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Keys returns the keys of all resident entries in no particular order,
+// without counting as an access
+func (c *TwoQueueCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, c.recent.Len()+c.hot.Len())
+	for key, elem := range c.items {
+		if elem.Value.(*twoQueueEntry[K, V]).where != c.ghost {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Contains reports whether key is stored, without counting as an access.
+// Ghost keys don't count.
+func (c *TwoQueueCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	return ok && elem.Value.(*twoQueueEntry[K, V]).where != c.ghost
+}
+
+// Len returns the number of entries, ghost keys left out
+func (c *TwoQueueCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.recent.Len() + c.hot.Len()
+}
+
+// Cap returns the maximum number of entries
+func (c *TwoQueueCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.size
+}
+
+// Delete removes key from the cache and forgets it in A1out
+func (c *TwoQueueCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	where := elem.Value.(*twoQueueEntry[K, V]).where
+	where.Remove(elem)
+	delete(c.items, key)
+	if where == c.ghost {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+// Clear removes all entries and forgets A1out
+func (c *TwoQueueCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+	c.recent.Init()
+	c.ghost.Init()
+	c.hot.Init()
+}
+
+// Resize changes the capacity to newCapacity and rescales A1in and A1out by
+// the ratios the cache was created with. It reclaims entries as Set does
+// until the cache fits and forgets the oldest ghost keys beyond the new
+// bound.
+func (c *TwoQueueCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.size = newCapacity
+	c.kin = int(float64(newCapacity) * c.ratios[0])
+	c.kout = int(float64(newCapacity) * c.ratios[1])
+	for c.recent.Len()+c.hot.Len() > max(c.size, 0) {
+		c.reclaim()
+	}
+	for c.ghost.Len() > max(c.kout, 0) {
+		delete(c.items, c.ghost.Remove(c.ghost.Back()).(*twoQueueEntry[K, V]).key)
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *TwoQueueCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// reclaim makes room for one entry if the cache is full. An entry evicted
+// from A1in is remembered in A1out, one evicted from Am is forgotten.
+func (c *TwoQueueCache[K, V]) reclaim() {
+	if c.recent.Len()+c.hot.Len() < c.size {
+		return
+	}
+	if c.recent.Len() > c.kin || c.hot.Len() == 0 {
+		elem := c.recent.Back()
+		e := elem.Value.(*twoQueueEntry[K, V])
+		c.recent.Remove(elem)
+		c.onEvict.report(e.key, e.value)
+
+		var zero V
+		e.value, e.where = zero, c.ghost
+		c.items[e.key] = c.ghost.PushFront(e)
+		if c.ghost.Len() > c.kout {
+			delete(c.items, c.ghost.Remove(c.ghost.Back()).(*twoQueueEntry[K, V]).key)
+		}
+		return
+	}
+	e := c.hot.Remove(c.hot.Back()).(*twoQueueEntry[K, V])
+	delete(c.items, e.key)
+	c.onEvict.report(e.key, e.value)
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *TwoQueueCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *TwoQueueCache[K, V]) get(key K) (V, error) {
+	elem, ok := c.items[key]
+	if !ok || elem.Value.(*twoQueueEntry[K, V]).where == c.ghost {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	if elem.Value.(*twoQueueEntry[K, V]).where == c.hot {
+		c.hot.MoveToFront(elem)
+	}
+	return elem.Value.(*twoQueueEntry[K, V]).value, nil
+}
+
+func (c *TwoQueueCache[K, V]) set(key K, value V) error {
+	elem, ok := c.items[key]
+	if ok && elem.Value.(*twoQueueEntry[K, V]).where != c.ghost {
+		e := elem.Value.(*twoQueueEntry[K, V])
+		e.value = value
+		if e.where == c.hot {
+			c.hot.MoveToFront(elem)
+		}
+		return nil
+	}
+	if c.size <= 0 {
+		return ErrCacheFull
+	}
+	if ok {
+		c.ghost.Remove(elem)
+		c.reclaim()
+		c.items[key] = c.hot.PushFront(&twoQueueEntry[K, V]{key: key, value: value, where: c.hot})
+		return nil
+	}
+	c.reclaim()
+	c.items[key] = c.recent.PushFront(&twoQueueEntry[K, V]{key: key, value: value, where: c.recent})
+	return nil
+}
@@ -0,0 +1,28 @@
+package strategies
+
+// watermark tracks the utilization of a cache against a high and a low
+// threshold, with hysteresis: after crossing the high one it only fires again
+// once the utilization dropped below the low one.
+type watermark struct {
+	high, low     float64
+	onHigh, onLow func()
+	above         bool
+}
+
+// check returns the callback to run for a cache holding n entries out of
+// capacity, or nil if no threshold was crossed
+func (w *watermark) check(n, capacity int) func() {
+	if w.onHigh == nil && w.onLow == nil || capacity <= 0 {
+		return nil
+	}
+	pct := float64(n) * 100 / float64(capacity)
+	switch {
+	case !w.above && pct >= w.high:
+		w.above = true
+		return w.onHigh
+	case w.above && pct < w.low:
+		w.above = false
+		return w.onLow
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package strategies
+
+import (
+	"sync"
+	"time"
+)
+
+// WindowedLFUCache is an LFU cache counting only the accesses made within a
+// trailing time window, so that past popularity fades away. Access times
+// outside the window are pruned lazily. Ties between equally used entries go
+// to the least recently used one.
+//
+// Finding a victim scans all entries, so evictions take O(n).
+type WindowedLFUCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	window   time.Duration
+	clock    Clock
+	items    map[K]*windowedEntry[K, V]
+	onEvict  evictHook[K, V]
+}
+
+// windowedEntry is an entry together with its recent access times
+type windowedEntry[K comparable, V any] struct {
+	key   K
+	value V
+	hits  []time.Time // oldest first
+}
+
+// NewWindowedLFUCache creates an LFU cache holding at most capacity entries
+// whose frequencies count the accesses of the last window. It honors
+// WithClock.
+func NewWindowedLFUCache[K comparable, V any](capacity int, window time.Duration, opts ...Option) *WindowedLFUCache[K, V] {
+	o := newOptions(opts)
+	return &WindowedLFUCache[K, V]{
+		capacity: capacity,
+		window:   window,
+		clock:    o.clock,
+		items:    make(map[K]*windowedEntry[K, V]),
+	}
+}
+
+// Get returns the value stored for key and records the access
+func (c *WindowedLFUCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key and records the access. Inserting a new key into
+// a full cache evicts the entry with the fewest accesses within the window.
+func (c *WindowedLFUCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value)
+}
+
+// GetOrSet returns the value stored for key if there is one, and otherwise
+// stores value and returns it. loaded reports whether the value was already
+// stored. Either way it counts as a single access.
+func (c *WindowedLFUCache[K, V]) GetOrSet(key K, value V) (actual V, loaded bool, err error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if actual, err := c.get(key); err == nil {
+		return actual, true, nil
+	}
+	if err := c.set(key, value); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return value, false, nil
+}
+
+// Contains reports whether key is stored, without counting as an access
+func (c *WindowedLFUCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
+// Len returns the number of entries
+func (c *WindowedLFUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the maximum number of entries
+func (c *WindowedLFUCache[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.capacity
+}
+
+// Delete removes key from the cache
+func (c *WindowedLFUCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.items[key]; !ok {
+		return ErrKeyNotFound
+	}
+	delete(c.items, key)
+	return nil
+}
+
+// Clear removes all entries
+func (c *WindowedLFUCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	clear(c.items)
+}
+
+// Resize changes the capacity to newCapacity, evicting the entries with the
+// fewest accesses within the window until the cache fits
+func (c *WindowedLFUCache[K, V]) Resize(newCapacity int) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	now := c.clock.Now()
+	for len(c.items) > max(c.capacity, 0) {
+		c.evict(now)
+	}
+	return nil
+}
+
+// SetEvictCallback registers fn to be called with every entry the policy
+// evicts. Explicit Delete and Clear calls are not reported. fn runs once the
+// call that evicted the entries has released the lock, so it may call back
+// into the cache, for instance to Delete related keys.
+func (c *WindowedLFUCache[K, V]) SetEvictCallback(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.onEvict.fn = fn
+}
+
+// hit records an access to e at now
+func (c *WindowedLFUCache[K, V]) hit(e *windowedEntry[K, V], now time.Time) {
+	c.prune(e, now)
+	e.hits = append(e.hits, now)
+}
+
+// prune forgets the accesses of e that fell out of the window
+func (c *WindowedLFUCache[K, V]) prune(e *windowedEntry[K, V], now time.Time) {
+	cutoff := now.Add(-c.window)
+	n := 0
+	for n < len(e.hits) && !e.hits[n].After(cutoff) {
+		n++
+	}
+	e.hits = e.hits[n:]
+}
+
+// evict removes the entry with the fewest accesses within the window
+func (c *WindowedLFUCache[K, V]) evict(now time.Time) {
+	var victim *windowedEntry[K, V]
+	for _, e := range c.items {
+		c.prune(e, now)
+		if victim == nil || len(e.hits) < len(victim.hits) ||
+			len(e.hits) == len(victim.hits) && lastHit(e.hits).Before(lastHit(victim.hits)) {
+			victim = e
+		}
+	}
+	if victim == nil {
+		return
+	}
+	delete(c.items, victim.key)
+	c.onEvict.report(victim.key, victim.value)
+}
+
+// lastHit returns the most recent access time, or the zero time if none
+// is left in the window
+func lastHit(hits []time.Time) time.Time {
+	if len(hits) == 0 {
+		return time.Time{}
+	}
+	return hits[len(hits)-1]
+}
+
+// unlock releases the lock and reports the entries evicted while it was held
+func (c *WindowedLFUCache[K, V]) unlock() {
+	c.onEvict.unlock(&c.mu)
+}
+
+func (c *WindowedLFUCache[K, V]) get(key K) (V, error) {
+	e, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, ErrKeyNotFound
+	}
+	c.hit(e, c.clock.Now())
+	return e.value, nil
+}
+
+func (c *WindowedLFUCache[K, V]) set(key K, value V) error {
+	now := c.clock.Now()
+	if e, ok := c.items[key]; ok {
+		e.value = value
+		c.hit(e, now)
+		return nil
+	}
+	if c.capacity <= 0 {
+		return ErrCacheFull
+	}
+	if len(c.items) >= c.capacity {
+		c.evict(now)
+	}
+	e := &windowedEntry[K, V]{key: key, value: value}
+	c.hit(e, now)
+	c.items[key] = e
+	return nil
+}
@@ -0,0 +1,150 @@
+package cache
+
+import "sync"
+
+// EvictingCache is a cache reporting the entries its policy evicts
+type EvictingCache[K comparable, V any] interface {
+	Cache[K, V]
+	SetEvictCallback(fn func(key K, value V))
+}
+
+// keyChecker is a cache telling whether it holds a key without counting as
+// an access
+type keyChecker[K comparable] interface {
+	Contains(key K) bool
+}
+
+// TaggedCache groups entries under tags that can be invalidated at once.
+// Evicted entries are dropped from the tag index, so a tag never references
+// a key that is no longer cached.
+type TaggedCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	inner   EvictingCache[K, V]
+	keyTags map[K][]string
+	tagKeys map[string]map[K]struct{}
+
+	evictedMu sync.Mutex
+	evicted   []K // keys reported by inner, untagged before mu is released
+}
+
+// NewTaggedCache wraps inner into a tagged cache. The wrapper takes over the
+// eviction callback of inner, which must only be used through the wrapper
+// afterwards.
+func NewTaggedCache[K comparable, V any](inner EvictingCache[K, V]) *TaggedCache[K, V] {
+	c := &TaggedCache[K, V]{
+		inner:   inner,
+		keyTags: make(map[K][]string),
+		tagKeys: make(map[string]map[K]struct{}),
+	}
+	// Evictions are reported during calls made by the wrapper, which holds
+	// the lock, but also by the janitor of a TTL cache on its own goroutine,
+	// so they are queued and applied under the lock
+	inner.SetEvictCallback(func(key K, _ V) {
+		c.evictedMu.Lock()
+		c.evicted = append(c.evicted, key)
+		c.evictedMu.Unlock()
+	})
+	return c
+}
+
+// Get returns the value stored for key
+func (c *TaggedCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.inner.Get(key)
+}
+
+// Set stores value for key without tags, dropping any previous tags of key
+func (c *TaggedCache[K, V]) Set(key K, value V) error {
+	return c.SetTagged(key, value)
+}
+
+// SetTagged stores value for key under the given tags, replacing any
+// previous tags of key
+func (c *TaggedCache[K, V]) SetTagged(key K, value V, tags ...string) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if err := c.inner.Set(key, value); err != nil {
+		return err
+	}
+	c.untag(key)
+	if len(tags) == 0 {
+		return nil
+	}
+	c.keyTags[key] = append([]string(nil), tags...)
+	for _, tag := range tags {
+		keys, ok := c.tagKeys[tag]
+		if !ok {
+			keys = make(map[K]struct{})
+			c.tagKeys[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+	return nil
+}
+
+// InvalidateTag removes every entry tagged with tag and returns how many
+// entries were removed
+func (c *TaggedCache[K, V]) InvalidateTag(tag string) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	removed := 0
+	for key := range c.tagKeys[tag] {
+		if c.inner.Delete(key) == nil {
+			removed++
+		}
+		c.untag(key)
+	}
+	return removed
+}
+
+// Delete removes key from the cache
+func (c *TaggedCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.untag(key)
+	return c.inner.Delete(key)
+}
+
+// Clear removes all entries and tags
+func (c *TaggedCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.inner.Clear()
+	c.keyTags = make(map[K][]string)
+	c.tagKeys = make(map[string]map[K]struct{})
+}
+
+// untag removes key from the tag index
+func (c *TaggedCache[K, V]) untag(key K) {
+	for _, tag := range c.keyTags[key] {
+		delete(c.tagKeys[tag], key)
+		if len(c.tagKeys[tag]) == 0 {
+			delete(c.tagKeys, tag)
+		}
+	}
+	delete(c.keyTags, key)
+}
+
+// unlock untags the keys evicted so far, then releases the lock. A key the
+// janitor expired may have been stored again before its eviction was
+// applied, so keys inner still holds keep their tags.
+func (c *TaggedCache[K, V]) unlock() {
+	c.evictedMu.Lock()
+	evicted := c.evicted
+	c.evicted = nil
+	c.evictedMu.Unlock()
+
+	checker, _ := c.inner.(keyChecker[K])
+	for _, key := range evicted {
+		if checker == nil || !checker.Contains(key) {
+			c.untag(key)
+		}
+	}
+	c.mu.Unlock()
+}
@@ -0,0 +1,102 @@
+package cache
+
+import "sync"
+
+// VersionedCache tags every entry with a version for optimistic concurrency
+// control: a writer reads a value together with its version and later
+// writes back only if nobody else wrote in between.
+//
+// Versions come from a single counter shared by all keys, so a key that is
+// evicted and inserted again never gets one of its previous versions back.
+type VersionedCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	inner   Cache[K, versioned[V]]
+	version uint64 // last version handed out
+}
+
+// versioned is a value together with its version
+type versioned[V any] struct {
+	value   V
+	version uint64
+}
+
+// NewVersionedCache creates a versioned cache on top of a cache built by New
+// from policy, capacity and opts
+func NewVersionedCache[K comparable, V any](policy string, capacity int, opts ...Option) (*VersionedCache[K, V], error) {
+	inner, err := New[K, versioned[V]](policy, capacity, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionedCache[K, V]{inner: inner}, nil
+}
+
+// Get returns the value stored for key
+func (c *VersionedCache[K, V]) Get(key K) (V, error) {
+	value, _, err := c.GetVersioned(key)
+	return value, err
+}
+
+// GetVersioned returns the value stored for key and its current version
+func (c *VersionedCache[K, V]) GetVersioned(key K) (V, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, err := c.inner.Get(key)
+	if err != nil {
+		var zero V
+		return zero, 0, err
+	}
+	return e.value, e.version, nil
+}
+
+// Set unconditionally stores value for key under a new version
+func (c *VersionedCache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.set(key, value)
+	return err
+}
+
+// SetVersioned stores value for key only if the current version of key is
+// expectedVersion, 0 meaning that key must be absent. It returns the new
+// version, or ErrVersionConflict if key changed in the meantime.
+func (c *VersionedCache[K, V]) SetVersioned(key K, value V, expectedVersion uint64) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var current uint64
+	if e, err := c.inner.Get(key); err == nil {
+		current = e.version
+	}
+	if current != expectedVersion {
+		return 0, ErrVersionConflict
+	}
+	return c.set(key, value)
+}
+
+// Delete removes key from the cache
+func (c *VersionedCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.inner.Delete(key)
+}
+
+// Clear removes all entries. Versions keep increasing afterwards.
+func (c *VersionedCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inner.Clear()
+}
+
+// set stores value for key under the next version
+func (c *VersionedCache[K, V]) set(key K, value V) (uint64, error) {
+	version := c.version + 1
+	if err := c.inner.Set(key, versioned[V]{value: value, version: version}); err != nil {
+		return 0, err
+	}
+	c.version = version
+	return version, nil
+}
@@ -0,0 +1,60 @@
+// Package workload generates reproducible synthetic key sequences for tests
+// and benchmarks.
+package workload
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+// Zipfian returns count keys drawn from [0, n) where key i is picked with a
+// probability proportional to 1/(i+1)^skew. Key 0 is the most popular one and
+// a skew of 0 yields a uniform distribution. The same seed always produces
+// the same sequence.
+func Zipfian(n int, skew float64, count int, seed int64) []int {
+	if n <= 0 || count <= 0 {
+		return nil
+	}
+
+	cumulative := make([]float64, n)
+	total := 0.0
+	for i := range cumulative {
+		total += 1 / math.Pow(float64(i+1), skew)
+		cumulative[i] = total
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	keys := make([]int, count)
+	for i := range keys {
+		target := rng.Float64() * total
+		keys[i] = sort.SearchFloat64s(cumulative, target)
+		if keys[i] == n {
+			keys[i] = n - 1
+		}
+	}
+	return keys
+}
+
+// Sequential returns count keys cycling through 0, 1, ..., n-1
+func Sequential(n, count int) []int {
+	if n <= 0 || count <= 0 {
+		return nil
+	}
+
+	keys := make([]int, count)
+	for i := range keys {
+		keys[i] = i % n
+	}
+	return keys
+}
+
+// Strings converts a key sequence into string keys, e.g. for bench.RunTrace
+func Strings(keys []int) []string {
+	trace := make([]string, len(keys))
+	for i, key := range keys {
+		trace[i] = strconv.Itoa(key)
+	}
+	return trace
+}
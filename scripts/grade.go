@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -27,70 +28,50 @@ type GradingResult struct {
 	Output    string `json:"output"`
 }
 
-func main() {
-	// Define test suites and their point values
-	testSuites := map[string]int{
-		"TestFIFOCache": 10,
-		"TestLRUCache":  10,
-		"TestLFUCache":  10,
-		"TestTTLCache":  10,
-	}
-
-	var results []TestResult
-	var gradingResults []GradingResult
-
-	// Run each test suite
-	for testName, maxPoints := range testSuites {
-		fmt.Printf("Running %s...\n", testName)
+// suiteTimeout bounds the run time of a single test suite
+const suiteTimeout = 30 * time.Second
 
-		// Run the specific test
-		cmd := exec.Command("go", "test", "./tests", "-run", testName, "-v", "-json")
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			fmt.Printf("  Warning: Error running %s: %v\n", testName, err)
-		}
+// testSuites defines the graded test suites and their point values
+var testSuites = map[string]int{
+	"TestFIFOCache": 10,
+	"TestLRUCache":  10,
+	"TestLFUCache":  10,
+	"TestTTLCache":  10,
+}
 
-		// Parse test results
-		lines := strings.Split(string(output), "\n")
-		var passed bool
-		var testOutput strings.Builder
+// subtestPoints maps ParentTest/SubTest names to the share of the parent
+// suite's points they carry. The shares of each suite sum to its max points.
+// Suites without entries here are graded all-or-nothing.
+var subtestPoints = map[string]int{
+	"TestFIFOCache/Basic":    3,
+	"TestFIFOCache/Eviction": 3,
+	"TestFIFOCache/Delete":   2,
+	"TestFIFOCache/Clear":    2,
 
-		for _, line := range lines {
-			if line == "" {
-				continue
-			}
+	"TestLRUCache/Basic":    4,
+	"TestLRUCache/Eviction": 6,
 
-			var result TestResult
-			if err := json.Unmarshal([]byte(line), &result); err == nil {
-				results = append(results, result)
+	"TestLFUCache/Basic":    4,
+	"TestLFUCache/Eviction": 6,
 
-				if result.Action == "pass" && strings.Contains(result.Test, testName) {
-					passed = true
-				}
+	"TestTTLCache/Basic":              3,
+	"TestTTLCache/Expiration":         4,
+	"TestTTLCache/SetAfterExpiration": 3,
+}
 
-				if result.Output != "" {
-					testOutput.WriteString(result.Output)
-				}
-			}
-		}
+func main() {
+	var results []TestResult
+	var gradingResults []GradingResult
 
-		// Determine points based on test results
-		points := 0
-		status := "FAIL"
-		if passed {
-			points = maxPoints
-			status = "PASS"
-		}
+	// Run each test suite
+	for testName, maxPoints := range testSuites {
+		fmt.Printf("Running %s...\n", testName)
 
-		gradingResults = append(gradingResults, GradingResult{
-			TestName:  testName,
-			Points:    points,
-			MaxPoints: maxPoints,
-			Status:    status,
-			Output:    testOutput.String(),
-		})
+		events, result := gradeSuite(testName, maxPoints)
+		results = append(results, events...)
+		gradingResults = append(gradingResults, result)
 
-		fmt.Printf("  %s: %d/%d points\n", testName, points, maxPoints)
+		fmt.Printf("  %s: %d/%d points\n", testName, result.Points, result.MaxPoints)
 	}
 
 	// Calculate total score
@@ -113,6 +94,119 @@ func main() {
 	fmt.Printf("Total: %d/%d points (%.1f%%)\n", totalPoints, totalMaxPoints, float64(totalPoints)/float64(totalMaxPoints)*100)
 }
 
+// gradeSuite runs a single test suite and scores it. A crash while grading
+// one suite is reported as a failed result so the remaining suites still run.
+func gradeSuite(testName string, maxPoints int) (events []TestResult, result GradingResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = GradingResult{
+				TestName:  testName,
+				MaxPoints: maxPoints,
+				Status:    "FAIL",
+				Output:    fmt.Sprintf("grader panic: %v", r),
+			}
+		}
+	}()
+
+	// The go tool enforces the timeout itself; the context only guards
+	// against the toolchain hanging before the tests start.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*suiteTimeout)
+	defer cancel()
+
+	// Run the specific test
+	cmd := exec.CommandContext(ctx, "go", "test", "./tests", "-run", testName, "-v", "-json",
+		"-timeout", suiteTimeout.String())
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		fmt.Printf("  Warning: Error running %s: %v\n", testName, err)
+	}
+
+	events = parseEvents(output)
+	return events, scoreSuite(testName, maxPoints, events)
+}
+
+// parseEvents decodes a `go test -json` stream, skipping lines that are not
+// valid test events (e.g. build output).
+func parseEvents(output []byte) []TestResult {
+	var events []TestResult
+	for _, line := range strings.Split(string(output), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var result TestResult
+		if err := json.Unmarshal([]byte(line), &result); err == nil {
+			events = append(events, result)
+		}
+	}
+	return events
+}
+
+// scoreSuite computes the grading result of a single suite from its test
+// events. Each passed subtest listed in subtestPoints earns its share of the
+// points; a suite without configured subtests earns everything or nothing.
+// A suite whose stream ends without a terminal pass/fail event crashed or
+// timed out and scores zero with status INCOMPLETE.
+func scoreSuite(testName string, maxPoints int, events []TestResult) GradingResult {
+	var testOutput strings.Builder
+	var passed, finished bool
+	points := 0
+	weighted := false
+	for name := range subtestPoints {
+		if strings.HasPrefix(name, testName+"/") {
+			weighted = true
+			break
+		}
+	}
+
+	for _, event := range events {
+		if event.Output != "" {
+			testOutput.WriteString(event.Output)
+		}
+		if event.Test == testName && (event.Action == "pass" || event.Action == "fail") {
+			finished = true
+		}
+		if event.Action != "pass" {
+			continue
+		}
+		if event.Test == testName {
+			passed = true
+		}
+		if share, ok := subtestPoints[event.Test]; ok && strings.HasPrefix(event.Test, testName+"/") {
+			points += share
+		}
+	}
+
+	if !weighted {
+		points = 0
+		if passed {
+			points = maxPoints
+		}
+	}
+	if points > maxPoints {
+		points = maxPoints
+	}
+
+	status := "FAIL"
+	switch {
+	case !finished:
+		points = 0
+		status = "INCOMPLETE"
+	case points == maxPoints:
+		status = "PASS"
+	case points > 0:
+		status = "PARTIAL"
+	}
+
+	return GradingResult{
+		TestName:  testName,
+		Points:    points,
+		MaxPoints: maxPoints,
+		Status:    status,
+		Output:    testOutput.String(),
+	}
+}
+
 func writeTestResults(results []TestResult) error {
 	file, err := os.Create("test-results.json")
 	if err != nil {
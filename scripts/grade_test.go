@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestScoreSuitePartialCredit feeds a synthetic event stream to the scorer
+func TestScoreSuitePartialCredit(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"Action":"run","Test":"TestFIFOCache"}`,
+		`{"Action":"run","Test":"TestFIFOCache/Basic"}`,
+		`{"Action":"pass","Test":"TestFIFOCache/Basic"}`,
+		`{"Action":"run","Test":"TestFIFOCache/Eviction"}`,
+		`{"Action":"output","Test":"TestFIFOCache/Eviction","Output":"eviction failed\n"}`,
+		`{"Action":"fail","Test":"TestFIFOCache/Eviction"}`,
+		`{"Action":"run","Test":"TestFIFOCache/Delete"}`,
+		`{"Action":"pass","Test":"TestFIFOCache/Delete"}`,
+		`{"Action":"run","Test":"TestFIFOCache/Clear"}`,
+		`{"Action":"fail","Test":"TestFIFOCache/Clear"}`,
+		`{"Action":"fail","Test":"TestFIFOCache"}`,
+		`not a json line`,
+	}, "\n")
+
+	events := parseEvents([]byte(stream))
+	assert.Len(t, events, 11)
+
+	result := scoreSuite("TestFIFOCache", 10, events)
+	assert.Equal(t, 5, result.Points)
+	assert.Equal(t, 10, result.MaxPoints)
+	assert.Equal(t, "PARTIAL", result.Status)
+	assert.Contains(t, result.Output, "eviction failed")
+
+	// A fully passing suite earns the maximum
+	all := parseEvents([]byte(strings.Join([]string{
+		`{"Action":"pass","Test":"TestLRUCache/Basic"}`,
+		`{"Action":"pass","Test":"TestLRUCache/Eviction"}`,
+		`{"Action":"pass","Test":"TestLRUCache"}`,
+	}, "\n")))
+	result = scoreSuite("TestLRUCache", 10, all)
+	assert.Equal(t, 10, result.Points)
+	assert.Equal(t, "PASS", result.Status)
+}
+
+// TestScoreSuiteIncomplete drives the scorer with a stream that ends mid-test
+func TestScoreSuiteIncomplete(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"Action":"run","Test":"TestTTLCache"}`,
+		`{"Action":"run","Test":"TestTTLCache/Basic"}`,
+		`{"Action":"pass","Test":"TestTTLCache/Basic"}`,
+		`{"Action":"run","Test":"TestTTLCache/Expiration"}`,
+		`{"Action":"output","Test":"TestTTLCache/Expiration","Output":"panic: runtime error: invalid memory address\n"}`,
+	}, "\n")
+
+	result := scoreSuite("TestTTLCache", 10, parseEvents([]byte(stream)))
+	assert.Equal(t, 0, result.Points)
+	assert.Equal(t, "INCOMPLETE", result.Status)
+	assert.Contains(t, result.Output, "panic: runtime error")
+}
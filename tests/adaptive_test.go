@@ -0,0 +1,100 @@
+package cache_test
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAdaptiveCache tests switching a live cache from FIFO to LRU
+func TestAdaptiveCache(t *testing.T) {
+	c, err := cache.NewAdaptiveCache[string, int]("fifo", 3)
+	require.NoError(t, err)
+	require.NoError(t, c.Set("a", 1))
+	require.NoError(t, c.Set("b", 2))
+	require.NoError(t, c.Set("c", 3))
+
+	require.NoError(t, c.SwitchPolicy("lru"))
+	assert.Equal(t, "lru", c.Policy())
+	for key, want := range map[string]int{"a": 1, "b": 2, "c": 3} {
+		val, err := c.Get(key)
+		require.NoError(t, err)
+		assert.Equal(t, want, val)
+	}
+
+	// Under FIFO a would go first; under LRU the recent hit protects it
+	_, err = c.Get("a")
+	require.NoError(t, err)
+	require.NoError(t, c.Set("d", 4))
+	require.NoError(t, c.Set("e", 5))
+	for key, want := range map[string]int{"a": 1, "d": 4, "e": 5} {
+		val, err := c.Get(key)
+		require.NoError(t, err)
+		assert.Equal(t, want, val)
+	}
+
+	// A failed switch keeps the current policy and entries
+	assert.ErrorIs(t, c.SwitchPolicy("mru-ish"), cache.ErrUnknownPolicy)
+	assert.Equal(t, "lru", c.Policy())
+	val, err := c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+}
+
+// TestAdaptiveCacheConcurrent tests that readers never miss an entry while
+// the policy is switched
+func TestAdaptiveCacheConcurrent(t *testing.T) {
+	c, err := cache.NewAdaptiveCache[int, int]("lru", 100)
+	require.NoError(t, err)
+	require.NoError(t, c.Set(0, 0))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				// Only a handful of keys, so nothing is ever evicted
+				_ = c.Set(1+i%10, i)
+				val, err := c.Get(0)
+				assert.NoError(t, err)
+				assert.Equal(t, 0, val)
+			}
+		}()
+	}
+	for _, policy := range []string{"fifo", "lfu", "arc", "lru", "fifo"} {
+		require.NoError(t, c.SwitchPolicy(policy))
+	}
+	wg.Wait()
+}
+
+// TestAdaptiveCacheJanitor tests that switching away from a TTL cache stops
+// its janitor, and that the stats of a cache survive the switch
+func TestAdaptiveCacheJanitor(t *testing.T) {
+	var stats cache.Stats
+	before := runtime.NumGoroutine()
+	c, err := cache.NewAdaptiveCache[string, int]("ttl", 4, cache.WithTTL(time.Minute), cache.WithStats(&stats),
+		cache.WithStrategyOptions(strategies.WithJanitor(time.Hour)))
+	require.NoError(t, err)
+	require.NoError(t, c.Set("a", 1))
+	withJanitor := runtime.NumGoroutine()
+	assert.Greater(t, withJanitor, before)
+
+	require.NoError(t, c.SwitchPolicy("lru", cache.WithStats(&stats)))
+	// Polled by hand, as Eventually runs goroutines of its own
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() >= withJanitor && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Less(t, runtime.NumGoroutine(), withJanitor)
+	val, err := c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	assert.Equal(t, uint64(1), stats.Hits())
+}
@@ -0,0 +1,79 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inserter is implemented by every cache supporting Add and SetIfPresent
+type inserter interface {
+	cache.Cache[string, int]
+	Add(key string, value int) error
+	SetIfPresent(key string, value int) error
+}
+
+// TestAddSetIfPresent tests insert-only and update-only stores
+func TestAddSetIfPresent(t *testing.T) {
+	caches := map[string]inserter{
+		"FIFO": strategies.NewFIFOCache[string, int](4),
+		"LRU":  strategies.NewLRUCache[string, int](4),
+		"LFU":  strategies.NewLFUCache[string, int](4),
+		"TTL":  strategies.NewTTLCache[string, int](4, time.Hour),
+		"ARC":  strategies.NewARCCache[string, int](4),
+	}
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, cache.ErrKeyNotFound, c.SetIfPresent("a", 1))
+			_, err := c.Get("a")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+
+			require.NoError(t, c.Add("a", 1))
+			assert.Equal(t, cache.ErrKeyExists, c.Add("a", 2))
+			val, err := c.Get("a")
+			require.NoError(t, err)
+			assert.Equal(t, 1, val)
+
+			require.NoError(t, c.SetIfPresent("a", 3))
+			val, err = c.Get("a")
+			require.NoError(t, err)
+			assert.Equal(t, 3, val)
+
+			// Racing adds of the same key succeed exactly once
+			var wg sync.WaitGroup
+			errs := make([]error, 8)
+			for i := range errs {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					errs[i] = c.Add("b", i)
+				}(i)
+			}
+			wg.Wait()
+			added := 0
+			for _, err := range errs {
+				if err == nil {
+					added++
+				} else {
+					assert.Equal(t, cache.ErrKeyExists, err)
+				}
+			}
+			assert.Equal(t, 1, added)
+		})
+	}
+
+	t.Run("Expired", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Unix(0, 0))
+		c := strategies.NewTTLCache[string, int](4, time.Minute, strategies.WithClock(clock))
+		require.NoError(t, c.Add("a", 1))
+		clock.Advance(2 * time.Minute)
+		assert.Equal(t, cache.ErrKeyNotFound, c.SetIfPresent("a", 2))
+		require.NoError(t, c.Add("a", 3))
+	})
+}
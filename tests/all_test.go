@@ -0,0 +1,59 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAll tests the iterators over the entries of every policy
+func TestAll(t *testing.T) {
+	type iterable interface {
+		cache.Cache[string, int]
+		Keys() []string
+		All() func(yield func(key string, value int) bool)
+	}
+	caches := map[string]iterable{
+		"FIFO": strategies.NewFIFOCache[string, int](4),
+		"LRU":  strategies.NewLRUCache[string, int](4),
+		"LFU":  strategies.NewLFUCache[string, int](4),
+		"ARC":  strategies.NewARCCache[string, int](4),
+		"TTL":  strategies.NewTTLCache[string, int](4, time.Hour),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			for i, key := range []string{"a", "b", "c"} {
+				require.NoError(t, c.Set(key, i))
+			}
+			_, err := c.Get("a")
+			require.NoError(t, err)
+
+			// Entries come in the order of Keys, and the loop body may
+			// modify the cache
+			want := c.Keys()
+			var keys []string
+			c.All()(func(key string, value int) bool {
+				keys = append(keys, key)
+				require.NoError(t, c.Delete(key))
+				return true
+			})
+			assert.Equal(t, want, keys)
+			assert.Empty(t, c.Keys())
+
+			// Returning false stops the iteration
+			require.NoError(t, c.Set("x", 1))
+			require.NoError(t, c.Set("y", 2))
+			n := 0
+			c.All()(func(string, int) bool {
+				n++
+				return false
+			})
+			assert.Equal(t, 1, n)
+		})
+	}
+}
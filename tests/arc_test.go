@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -43,4 +44,31 @@ func TestARCCache(t *testing.T) {
 	if err == nil {
 		assert.Equal(t, 2, val)
 	}
-} 
\ No newline at end of file
+} 
+
+// TestARCState tests the adaptive state reported by the ARC cache
+func TestARCState(t *testing.T) {
+	c := strategies.NewARCCache[string, int](4)
+	for i, key := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, c.Set(key, i))
+	}
+	_, err := c.Get("a")
+	require.NoError(t, err)
+	_, err = c.Get("b")
+	require.NoError(t, err)
+	assert.Equal(t, strategies.ARCState[string]{
+		T1: []string{"c", "d"}, T2: []string{"a", "b"}, B1: []string{}, B2: []string{},
+	}, c.State())
+
+	// The once-seen c is evicted to B1
+	require.NoError(t, c.Set("e", 5))
+	assert.Equal(t, strategies.ARCState[string]{
+		T1: []string{"d", "e"}, T2: []string{"a", "b"}, B1: []string{"c"}, B2: []string{},
+	}, c.State())
+
+	// The hit on the ghost c grows the target of T1 and takes c to T2
+	require.NoError(t, c.Set("c", 3))
+	assert.Equal(t, strategies.ARCState[string]{
+		P: 1, T1: []string{"e"}, T2: []string{"a", "b", "c"}, B1: []string{"d"}, B2: []string{},
+	}, c.State())
+}
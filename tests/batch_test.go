@@ -0,0 +1,46 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchCache is a cache supporting ordered batch lookups
+type batchCache interface {
+	cache.Cache[string, int]
+	GetBatch(keys []string) []strategies.Result[int]
+}
+
+// TestGetBatch tests that batch results are aligned with the requested keys
+func TestGetBatch(t *testing.T) {
+	caches := map[string]batchCache{
+		"FIFO": strategies.NewFIFOCache[string, int](3),
+		"LRU":  strategies.NewLRUCache[string, int](3),
+		"LFU":  strategies.NewLFUCache[string, int](3),
+		"TTL":  strategies.NewTTLCache[string, int](3, time.Minute),
+		"ARC":  strategies.NewARCCache[string, int](3),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, c.Set("a", 1))
+			require.NoError(t, c.Set("b", 2))
+
+			results := c.GetBatch([]string{"b", "x", "a", "b", "y"})
+			require.Len(t, results, 5)
+			assert.Equal(t, strategies.Result[int]{Value: 2}, results[0])
+			assert.Equal(t, cache.ErrKeyNotFound, results[1].Err)
+			assert.Equal(t, strategies.Result[int]{Value: 1}, results[2])
+			assert.Equal(t, strategies.Result[int]{Value: 2}, results[3])
+			assert.Equal(t, cache.ErrKeyNotFound, results[4].Err)
+			assert.Zero(t, results[4].Value)
+
+			assert.Empty(t, c.GetBatch(nil))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package cache_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/bench"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBenchHarness tests trace replay and policy comparison
+func TestBenchHarness(t *testing.T) {
+	// A recency-friendly trace: "a" is re-used before it ages out of LRU
+	recency := strings.Split("a b a c a b a c a", " ")
+	stats := bench.RunTrace(cache.NewFIFOCache[string, int](2), recency)
+	assert.Equal(t, bench.Stats{Hits: 2, Misses: 7}, stats)
+	stats = bench.RunTrace(cache.NewLRUCache[string, int](2), recency)
+	assert.Equal(t, bench.Stats{Hits: 4, Misses: 5}, stats)
+
+	// A frequency-friendly trace: a scan must not flush the hot key "a"
+	frequency := strings.Split("a a a b c a d e a", " ")
+	results := bench.CompareTrace(frequency, 2)
+	require.Len(t, results, 5)
+	byPolicy := map[string]bench.Result{}
+	for _, result := range results {
+		assert.Equal(t, len(frequency), result.Hits+result.Misses)
+		byPolicy[result.Policy] = result
+	}
+	assert.Equal(t, 4, byPolicy["LFU"].Hits)
+	assert.Equal(t, 2, byPolicy["LRU"].Hits)
+	assert.Greater(t, byPolicy["LFU"].HitRate(), byPolicy["LRU"].HitRate())
+	assert.GreaterOrEqual(t, byPolicy["LRU"].HitRate(), byPolicy["FIFO"].HitRate())
+	assert.Equal(t, 4, byPolicy["OPT"].Hits)
+
+	// Traces load from one-key-per-line and CSV files alike
+	path := filepath.Join(t.TempDir(), "trace.csv")
+	require.NoError(t, os.WriteFile(path, []byte("a\nb, c\n\na,d\n"), 0o644))
+	trace, err := bench.LoadTraceFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "a", "d"}, trace)
+
+	var out bytes.Buffer
+	require.NoError(t, bench.WriteCSV(&out, results))
+	assert.True(t, strings.HasPrefix(out.String(), "policy,hits,misses,hit_rate\nFIFO,"))
+}
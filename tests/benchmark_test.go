@@ -0,0 +1,95 @@
+package cache_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+)
+
+// benchPolicies lists the caches covered by the benchmarks
+var benchPolicies = []struct {
+	name string
+	new  func(capacity int) cache.Cache[int, int]
+}{
+	{"FIFO", cache.NewFIFOCache[int, int]},
+	{"LRU", cache.NewLRUCache[int, int]},
+	{"LFU", cache.NewLFUCache[int, int]},
+	{"TTL", func(capacity int) cache.Cache[int, int] { return cache.NewTTLCache[int, int](capacity, time.Hour) }},
+	{"ARC", cache.NewARCCache[int, int]},
+}
+
+var benchCapacities = []int{100, 10000}
+
+// benchPatterns maps an access pattern to the size of its key space relative
+// to the capacity: hit-heavy keys mostly fit, miss-heavy keys mostly don't
+var benchPatterns = []struct {
+	name  string
+	scale int
+}{
+	{"HitHeavy", 1},
+	{"MissHeavy", 10},
+}
+
+// benchKeys returns a fixed pseudo-random key sequence in [0, keySpace)
+func benchKeys(keySpace int) []int {
+	rng := rand.New(rand.NewSource(1))
+	keys := make([]int, 1<<16)
+	for i := range keys {
+		keys[i] = rng.Intn(keySpace)
+	}
+	return keys
+}
+
+// runBenchmarks runs op for every policy, capacity and access pattern
+func runBenchmarks(b *testing.B, op func(b *testing.B, c cache.Cache[int, int], keys []int)) {
+	for _, policy := range benchPolicies {
+		for _, capacity := range benchCapacities {
+			for _, pattern := range benchPatterns {
+				name := fmt.Sprintf("%s/cap=%d/%s", policy.name, capacity, pattern.name)
+				b.Run(name, func(b *testing.B) {
+					c := policy.new(capacity)
+					for i := 0; i < capacity; i++ {
+						_ = c.Set(i, i)
+					}
+					keys := benchKeys(capacity * pattern.scale)
+					b.ReportAllocs()
+					b.ResetTimer()
+					op(b, c, keys)
+				})
+			}
+		}
+	}
+}
+
+// BenchmarkSet measures Set throughput
+func BenchmarkSet(b *testing.B) {
+	runBenchmarks(b, func(b *testing.B, c cache.Cache[int, int], keys []int) {
+		for i := 0; i < b.N; i++ {
+			_ = c.Set(keys[i%len(keys)], i)
+		}
+	})
+}
+
+// BenchmarkGet measures Get throughput on a warm cache
+func BenchmarkGet(b *testing.B) {
+	runBenchmarks(b, func(b *testing.B, c cache.Cache[int, int], keys []int) {
+		for i := 0; i < b.N; i++ {
+			_, _ = c.Get(keys[i%len(keys)])
+		}
+	})
+}
+
+// BenchmarkMixed measures a read-through workload setting keys on a miss
+func BenchmarkMixed(b *testing.B) {
+	runBenchmarks(b, func(b *testing.B, c cache.Cache[int, int], keys []int) {
+		for i := 0; i < b.N; i++ {
+			key := keys[i%len(keys)]
+			if _, err := c.Get(key); err != nil {
+				_ = c.Set(key, i)
+			}
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAssertEvictionOrder tests the eviction order helper against FIFO and LRU
+func TestAssertEvictionOrder(t *testing.T) {
+	t.Run("FIFO", func(t *testing.T) {
+		c := strategies.NewFIFOCache[string, int](3)
+		// Overwriting a keeps it at the front of the queue
+		cachetest.AssertEvictionOrder[string, int](t, c,
+			[]string{"a", "b", "c", "a", "d", "e"},
+			[]string{"c", "d", "e"})
+	})
+
+	t.Run("LRU", func(t *testing.T) {
+		c := strategies.NewLRUCache[string, int](3)
+		// Overwriting a makes it the most recently used entry
+		cachetest.AssertEvictionOrder[string, int](t, c,
+			[]string{"a", "b", "c", "a", "d", "e"},
+			[]string{"a", "d", "e"})
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		c := strategies.NewFIFOCache[string, int](2)
+		inner := &recordingTB{TB: t}
+		assert.False(t, cachetest.AssertEvictionOrder[string, int](inner, c,
+			[]string{"a", "b", "c"},
+			[]string{"a", "b"}))
+		assert.True(t, inner.failed)
+	})
+}
+
+// recordingTB records failures instead of failing the test
+type recordingTB struct {
+	testing.TB
+	failed bool
+}
+
+func (tb *recordingTB) Errorf(string, ...any) {
+	tb.failed = true
+}
@@ -0,0 +1,73 @@
+package cache_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCARCache tests the clocks and ghost lists of the CAR cache
+func TestCARCache(t *testing.T) {
+	t.Run("Adaptation", func(t *testing.T) {
+		c := strategies.NewCARCache[string, int](2)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+
+		// The hand of t1 passes the referenced a on to t2 and evicts b
+		_, err := c.Get("a")
+		require.NoError(t, err)
+		require.NoError(t, c.Set("c", 3))
+		assert.Equal(t, []string{"b"}, evicted)
+		assert.ElementsMatch(t, []string{"a", "c"}, c.Keys())
+
+		// b is remembered in b1: it comes back into t2 and grows the
+		// target of t1, evicting c
+		_, err = c.Get("b")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		require.NoError(t, c.Set("b", 2))
+		assert.Equal(t, []string{"b", "c"}, evicted)
+
+		// With t1 below its target the hand of t2 turns and takes a
+		require.NoError(t, c.Set("d", 4))
+		assert.Equal(t, []string{"b", "c", "a"}, evicted)
+		assert.ElementsMatch(t, []string{"b", "d"}, c.Keys())
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		for _, capacity := range []int{1, 2, 5, 50} {
+			c := strategies.NewCARCache[int, int](capacity)
+			want := make(map[int]int)
+			r := rand.New(rand.NewSource(int64(capacity)))
+			for i := 0; i < 5000; i++ {
+				key := r.Intn(capacity * 3)
+				switch r.Intn(4) {
+				case 0:
+					if val, err := c.Get(key); err == nil {
+						require.Equal(t, want[key], val)
+					}
+				case 1:
+					_ = c.Delete(key)
+				default:
+					require.NoError(t, c.Set(key, i))
+					want[key] = i
+				}
+				require.LessOrEqual(t, len(c.Keys()), capacity)
+			}
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewCARCache[string, int](2)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Delete("a"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+		c.Clear()
+		assert.Equal(t, cache.ErrCacheFull, cache.NewCARCache[string, int](0).Set("a", 1))
+	})
+}
@@ -0,0 +1,67 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClockCache tests the CLOCK cache implementation
+func TestClockCache(t *testing.T) {
+	t.Run("SecondChance", func(t *testing.T) {
+		c := strategies.NewClockCache[string, int](3)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		require.NoError(t, c.Set("c", 3))
+
+		// a is referenced, so the hand spares it and takes b
+		_, err := c.Get("a")
+		require.NoError(t, err)
+		require.NoError(t, c.Set("d", 4))
+		assert.Equal(t, []string{"b"}, evicted)
+
+		// a lost its bit on the way, c is next under the hand
+		require.NoError(t, c.Set("e", 5))
+		assert.Equal(t, []string{"b", "c"}, evicted)
+		require.NoError(t, c.Set("f", 6))
+		assert.Equal(t, []string{"b", "c", "a"}, evicted)
+	})
+
+	t.Run("AllReferenced", func(t *testing.T) {
+		// With every bit set the hand goes round once and evicts where it started
+		c := strategies.NewClockCache[string, int](2)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		_, _ = c.Get("a")
+		_, _ = c.Get("b")
+		require.NoError(t, c.Set("c", 3))
+		_, err := c.Get("a")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		assert.ElementsMatch(t, []string{"b", "c"}, c.Keys())
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewClockCache[string, int](2)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		require.NoError(t, c.Delete("a"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+
+		// The freed slot is reused without evicting
+		require.NoError(t, c.Set("c", 3))
+		val, err := c.Get("b")
+		require.NoError(t, err)
+		assert.Equal(t, 2, val)
+
+		c.Clear()
+		_, err = c.Get("b")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		require.NoError(t, c.Set("d", 4))
+		assert.Equal(t, cache.ErrCacheFull, cache.NewClockCache[string, int](0).Set("a", 1))
+	})
+}
@@ -0,0 +1,100 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cloneable is a cache whose copies evict like the original
+type cloneable interface {
+	cache.Cache[string, int]
+	Keys() []string
+}
+
+// TestClone tests that a copy holds the same entries, evicts them in the
+// same order as the original and is independent of it
+func TestClone(t *testing.T) {
+	warm := func(c cloneable) {
+		for i, key := range []string{"a", "b", "c", "d"} {
+			require.NoError(t, c.Set(key, i))
+		}
+		_, _ = c.Get("b")
+		_, _ = c.Get("b")
+		_, _ = c.Get("c")
+	}
+	caches := map[string]func() (cloneable, cloneable){
+		"FIFO": func() (cloneable, cloneable) {
+			c := strategies.NewFIFOCache[string, int](3)
+			warm(c)
+			return c, c.Clone()
+		},
+		"LRU": func() (cloneable, cloneable) {
+			c := strategies.NewLRUCache[string, int](3)
+			warm(c)
+			return c, c.Clone()
+		},
+		"LFU": func() (cloneable, cloneable) {
+			c := strategies.NewLFUCache[string, int](3)
+			warm(c)
+			return c, c.Clone()
+		},
+		"TTL": func() (cloneable, cloneable) {
+			c := strategies.NewTTLCache[string, int](3, time.Hour)
+			warm(c)
+			return c, c.Clone()
+		},
+		"ARC": func() (cloneable, cloneable) {
+			c := strategies.NewARCCache[string, int](3)
+			warm(c)
+			return c, c.Clone()
+		},
+	}
+	for name, newPair := range caches {
+		t.Run(name, func(t *testing.T) {
+			original, clone := newPair()
+			assert.Equal(t, original.Keys(), clone.Keys())
+
+			// The same accesses lead to the same evictions
+			for _, key := range []string{"e", "b", "a", "f", "c"} {
+				if _, err := original.Get(key); err != nil {
+					require.NoError(t, original.Set(key, 0))
+				}
+				if _, err := clone.Get(key); err != nil {
+					require.NoError(t, clone.Set(key, 0))
+				}
+				assert.Equal(t, original.Keys(), clone.Keys())
+			}
+
+			// Changes to one cache do not show in the other
+			clone.Clear()
+			assert.Len(t, original.Keys(), 3)
+			require.NoError(t, original.Set("g", 7))
+			_, err := clone.Get("g")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+		})
+	}
+}
+
+// TestCloneSettings tests which settings a copy shares with the original
+func TestCloneSettings(t *testing.T) {
+	c := strategies.NewFIFOCache[string, int](2, strategies.WithEvictionEvents(4))
+	var evicted []string
+	c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+	require.NoError(t, c.SetPinned("p", 0))
+	require.NoError(t, c.Set("a", 1))
+
+	clone := c.Clone()
+	require.NoError(t, clone.Set("b", 2))
+	require.NoError(t, clone.Set("c", 3))
+	assert.Empty(t, evicted)
+	assert.Empty(t, drain(c.EvictionEvents()))
+	assert.Equal(t, []strategies.EvictionEvent[string, int]{
+		{Key: "a", Value: 1, Reason: strategies.ReasonCapacity},
+	}, drain(clone.EvictionEvents()))
+	assert.Equal(t, []string{"b", "c", "p"}, clone.Keys())
+}
@@ -0,0 +1,44 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClosableCache tests that Close drains every entry and shuts the cache
+func TestClosableCache(t *testing.T) {
+	inner := strategies.NewTTLCache[string, int](10, time.Hour, strategies.WithJanitor(time.Millisecond))
+	c := cache.NewClosableCache[string, int](inner)
+	want := map[string]int{"a": 1, "b": 2, "c": 3}
+	for key, value := range want {
+		require.NoError(t, c.Set(key, value))
+	}
+
+	// A failing drain leaves the cache open
+	boom := errors.New("boom")
+	assert.Equal(t, boom, c.Close(func(string, int) error { return boom }))
+	val, err := c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+
+	got := map[string]int{}
+	require.NoError(t, c.Close(func(key string, value int) error {
+		got[key] = value
+		return nil
+	}))
+	assert.Equal(t, want, got)
+	assert.Empty(t, inner.ToMap())
+
+	_, err = c.Get("a")
+	assert.Equal(t, cache.ErrCacheClosed, err)
+	assert.Equal(t, cache.ErrCacheClosed, c.Set("d", 4))
+	assert.Equal(t, cache.ErrCacheClosed, c.Delete("a"))
+	assert.Equal(t, cache.ErrCacheClosed, c.Close(func(string, int) error { return nil }))
+	c.Clear()
+}
@@ -2,8 +2,11 @@ package cache_test
 
 import (
 	"testing"
+	"time"
 
 	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -28,4 +31,336 @@ func TestCacheErrors(t *testing.T) {
 	val, err := c.Get("a")
 	require.NoError(t, err)
 	assert.Equal(t, 1, val)
-} 
\ No newline at end of file
+}
+
+// TestFreeze tests that frozen caches overfill and trim back on Unfreeze
+func TestFreeze(t *testing.T) {
+	type freezer interface {
+		cache.Cache[string, int]
+		Freeze()
+		Unfreeze()
+		SetEvictCallback(fn func(key string, value int))
+		ToMap() map[string]int
+	}
+	caches := map[string]freezer{
+		"FIFO": strategies.NewFIFOCache[string, int](2),
+		"LRU":  strategies.NewLRUCache[string, int](2),
+		"LFU":  strategies.NewLFUCache[string, int](2),
+	}
+	victims := map[string][]string{
+		"FIFO": {"a", "b", "c"},
+		"LRU":  {"b", "a", "c"},
+		"LFU":  {"b", "c", "d"},
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			var evicted []string
+			c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+
+			require.NoError(t, c.Set("a", 1))
+			require.NoError(t, c.Set("b", 2))
+			_, err := c.Get("a")
+			require.NoError(t, err)
+
+			c.Freeze()
+			for i, key := range []string{"c", "d", "e"} {
+				require.NoError(t, c.Set(key, i+3))
+			}
+			assert.Len(t, c.ToMap(), 5)
+			assert.Empty(t, evicted)
+
+			c.Unfreeze()
+			assert.Len(t, c.ToMap(), 2)
+			assert.Equal(t, victims[name], evicted)
+		})
+	}
+}
+
+// TestCapacityOne tests the single-slot edge case of every eviction policy
+func TestCapacityOne(t *testing.T) {
+	caches := map[string]func(int) cache.Cache[string, int]{
+		"FIFO": cache.NewFIFOCache[string, int],
+		"LRU":  cache.NewLRUCache[string, int],
+		"LFU":  cache.NewLFUCache[string, int],
+		"ARC":  cache.NewARCCache[string, int],
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			assertOnly := func(c cache.Cache[string, int], key string, value int) {
+				t.Helper()
+				for _, other := range []string{"a", "b"} {
+					got, err := c.Get(other)
+					if other == key {
+						require.NoError(t, err)
+						assert.Equal(t, value, got)
+					} else {
+						assert.Equal(t, cache.ErrKeyNotFound, err)
+					}
+				}
+			}
+
+			// Insert then insert again
+			c := newCache(1)
+			require.NoError(t, c.Set("a", 1))
+			require.NoError(t, c.Set("b", 2))
+			assertOnly(c, "b", 2)
+
+			// Overwriting the only entry keeps it resident
+			c = newCache(1)
+			require.NoError(t, c.Set("a", 1))
+			require.NoError(t, c.Set("a", 2))
+			assertOnly(c, "a", 2)
+
+			// An access does not protect the only entry from the next insert
+			c = newCache(1)
+			require.NoError(t, c.Set("a", 1))
+			_, err := c.Get("a")
+			require.NoError(t, err)
+			require.NoError(t, c.Set("b", 2))
+			assertOnly(c, "b", 2)
+
+			// The slot keeps turning over
+			require.NoError(t, c.Set("a", 3))
+			assertOnly(c, "a", 3)
+		})
+	}
+}
+
+// TestOverwrite tests that overwriting a key updates it in place and only
+// changes its position according to the policy
+func TestOverwrite(t *testing.T) {
+	type evicting interface {
+		cache.Cache[string, int]
+		SetEvictCallback(fn func(key string, value int))
+	}
+	tests := map[string]struct {
+		newCache func() evicting
+		victim   string
+	}{
+		// FIFO keeps the insertion order, LRU refreshes recency, LFU bumps frequency
+		"FIFO": {func() evicting { return strategies.NewFIFOCache[string, int](3) }, "a"},
+		"LRU":  {func() evicting { return strategies.NewLRUCache[string, int](3) }, "b"},
+		"LFU":  {func() evicting { return strategies.NewLFUCache[string, int](3) }, "b"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := tt.newCache()
+			var evicted []string
+			c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+
+			require.NoError(t, c.Set("a", 1))
+			require.NoError(t, c.Set("b", 2))
+			require.NoError(t, c.Set("c", 3))
+
+			// Overwriting in a full cache must not evict anything
+			require.NoError(t, c.Set("a", 10))
+			assert.Empty(t, evicted)
+
+			require.NoError(t, c.Set("d", 4))
+			assert.Equal(t, []string{tt.victim}, evicted)
+			if tt.victim != "a" {
+				val, err := c.Get("a")
+				require.NoError(t, err)
+				assert.Equal(t, 10, val)
+			}
+		})
+	}
+}
+
+// TestGetDefault tests that misses return the default and hits count as accesses
+func TestGetDefault(t *testing.T) {
+	c := cache.NewLRUCache[string, int](2)
+	require.NoError(t, c.Set("a", 1))
+	require.NoError(t, c.Set("b", 2))
+
+	assert.Equal(t, 1, cache.GetDefault(c, "a", -1))
+	assert.Equal(t, -1, cache.GetDefault(c, "missing", -1))
+
+	// The hit on a made b the least recently used entry
+	require.NoError(t, c.Set("c", 3))
+	assert.Equal(t, -1, cache.GetDefault(c, "b", -1))
+	assert.Equal(t, 1, cache.GetDefault(c, "a", -1))
+
+	// A miss doesn't count as an access
+	lfu := cache.NewLFUCache[string, int](2)
+	require.NoError(t, lfu.Set("a", 1))
+	require.NoError(t, lfu.Set("b", 2))
+	assert.Equal(t, 1, cache.GetDefault(lfu, "a", 0))
+	assert.Equal(t, 0, cache.GetDefault(lfu, "c", 0))
+	require.NoError(t, lfu.Set("c", 3))
+	assert.Equal(t, 0, cache.GetDefault(lfu, "b", 0))
+	assert.Equal(t, 3, cache.GetDefault(lfu, "c", 0))
+
+	// Expired entries return the default
+	ttl := cache.NewTTLCache[string, int](2, 10*time.Millisecond)
+	require.NoError(t, ttl.Set("a", 1))
+	assert.Equal(t, 1, cache.GetDefault(ttl, "a", 0))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 0, cache.GetDefault(ttl, "a", 0))
+}
+
+// TestSetErrors tests the Set error contract of every policy
+func TestSetErrors(t *testing.T) {
+	policies := map[string]func(capacity int) cache.Cache[string, int]{
+		"FIFO": cache.NewFIFOCache[string, int],
+		"LRU":  cache.NewLRUCache[string, int],
+		"LFU":  cache.NewLFUCache[string, int],
+		"ARC":  cache.NewARCCache[string, int],
+		"TTL": func(capacity int) cache.Cache[string, int] {
+			return cache.NewTTLCache[string, int](capacity, time.Hour)
+		},
+		"FIFOTTL": func(capacity int) cache.Cache[string, int] {
+			return cache.NewFIFOTTLCache[string, int](capacity, time.Hour)
+		},
+		"LRUK": func(capacity int) cache.Cache[string, int] {
+			return cache.NewLRUKCache[string, int](capacity, 2)
+		},
+	}
+
+	for name, newCache := range policies {
+		t.Run(name, func(t *testing.T) {
+			// Inserts, overwrites and evicting inserts all succeed
+			c := newCache(2)
+			for _, key := range []string{"a", "b", "a", "c", "d"} {
+				assert.NoError(t, c.Set(key, 1))
+			}
+
+			// Caches that can't hold anything reject every insert
+			for _, capacity := range []int{0, -1} {
+				c := newCache(capacity)
+				assert.Equal(t, cache.ErrCacheFull, c.Set("a", 1))
+				_, err := c.Get("a")
+				assert.Equal(t, cache.ErrKeyNotFound, err)
+			}
+		})
+	}
+}
+
+// TestDeleteFromEvictCallback tests that eviction callbacks may delete keys
+func TestDeleteFromEvictCallback(t *testing.T) {
+	type evicting interface {
+		cache.Cache[int, int]
+		SetEvictCallback(fn func(key int, value int))
+		ToMap() map[int]int
+	}
+	caches := map[string]evicting{
+		"FIFO": strategies.NewFIFOCache[int, int](8),
+		"LRU":  strategies.NewLRUCache[int, int](8),
+		"LFU":  strategies.NewLFUCache[int, int](8),
+		"TTL":  strategies.NewTTLCache[int, int](8, time.Hour),
+		"ARC":  strategies.NewARCCache[int, int](8),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			// Every odd key is a companion of the even key before it
+			c.SetEvictCallback(func(key, _ int) {
+				_ = c.Delete(key ^ 1)
+			})
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := 0; i < 1000; i++ {
+					_ = c.Set(i, i)
+					_, _ = c.Get(i / 2)
+				}
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Delete from the eviction callback deadlocked")
+			}
+
+			contents := c.ToMap()
+			assert.LessOrEqual(t, len(contents), 8)
+			for key, value := range contents {
+				assert.Equal(t, key, value)
+				val, err := c.Get(key)
+				require.NoError(t, err)
+				assert.Equal(t, value, val)
+			}
+		})
+	}
+}
+
+// TestLenCap tests the occupancy reported by the sized caches
+func TestLenCap(t *testing.T) {
+	clock := cachetest.NewClock(time.Unix(0, 0))
+	caches := map[string]cache.SizedCache[string, int]{
+		"FIFO": strategies.NewFIFOCache[string, int](3),
+		"LRU":  strategies.NewLRUCache[string, int](3),
+		"LFU":  strategies.NewLFUCache[string, int](3),
+		"ARC":  strategies.NewARCCache[string, int](3),
+		"TTL":  strategies.NewTTLCache[string, int](3, time.Minute, strategies.WithClock(clock)),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, 0, c.Len())
+			assert.Equal(t, 3, c.Cap())
+			for i, key := range []string{"a", "b", "a", "c", "d"} {
+				require.NoError(t, c.Set(key, i))
+			}
+			assert.Equal(t, 3, c.Len())
+			require.NoError(t, c.Delete("d"))
+			assert.Equal(t, 2, c.Len())
+			c.Clear()
+			assert.Equal(t, 0, c.Len())
+			assert.Equal(t, 3, c.Cap())
+		})
+	}
+
+	// Pinned entries count, expired ones don't
+	lru := strategies.NewLRUCache[string, int](2)
+	require.NoError(t, lru.SetPinned("p", 1))
+	require.NoError(t, lru.Set("a", 2))
+	assert.Equal(t, 2, lru.Len())
+	ttl := caches["TTL"]
+	require.NoError(t, ttl.Set("a", 1))
+	clock.Advance(2 * time.Minute)
+	assert.Equal(t, 0, ttl.Len())
+}
+
+// TestLenCapAllPolicies tests that every policy stays within its capacity
+func TestLenCapAllPolicies(t *testing.T) {
+	caches := map[string]cache.SizedCache[string, int]{
+		"LRUK":         strategies.NewLRUKCache[string, int](4, 2),
+		"WindowedLFU":  strategies.NewWindowedLFUCache[string, int](4, time.Hour),
+		"MRU":          strategies.NewMRUCache[string, int](4),
+		"Random":       strategies.NewRandomCache[string, int](4, nil),
+		"Clock":        strategies.NewClockCache[string, int](4),
+		"SLRU":         strategies.NewSLRUCache[string, int](2, 2),
+		"TwoQueue":     strategies.NewTwoQueueCache[string, int](4),
+		"LIRS":         strategies.NewLIRSCache[string, int](4),
+		"TinyLFU":      strategies.NewTinyLFUCache[string, int](4),
+		"SecondChance": strategies.NewSecondChanceCache[string, int](4),
+		"CAR":          strategies.NewCARCache[string, int](4),
+		"Sieve":        strategies.NewSieveCache[string, int](4),
+		"S3FIFO":       strategies.NewS3FIFOCache[string, int](4),
+		"GDSF":         strategies.NewGDSFCache[string, int](4),
+		"Priority":     strategies.NewPriorityCache[string, int](4),
+		"Policy":       strategies.NewPolicyCache[string, int](4, strategies.NewLRUPolicy[string]()),
+		"Optimal":      strategies.NewOfflineOptimalCache[string, int](4, nil),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, 4, c.Cap())
+			for i, key := range []string{"a", "b", "c", "d", "e", "a", "f"} {
+				require.NoError(t, c.Set(key, i))
+				assert.LessOrEqual(t, c.Len(), 4)
+			}
+			// SLRU only fills its protected segment with entries hit again
+			n := c.Len()
+			assert.Positive(t, n)
+			require.NoError(t, c.Delete("f"))
+			assert.Equal(t, n-1, c.Len())
+			c.Clear()
+			assert.Equal(t, 0, c.Len())
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompareAndDelete tests conditional deletes and their atomicity
+func TestCompareAndDelete(t *testing.T) {
+	type deleter interface {
+		cache.Cache[string, int]
+		cache.ConditionalDeleter[string, int]
+	}
+	caches := map[string]deleter{
+		"FIFO": strategies.NewFIFOCache[string, int](10),
+		"LRU":  strategies.NewLRUCache[string, int](10),
+		"LFU":  strategies.NewLFUCache[string, int](10),
+		"TTL":  strategies.NewTTLCache[string, int](10, time.Hour),
+		"ARC":  strategies.NewARCCache[string, int](10),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, c.Set("a", 1))
+
+			deleted, err := cache.CompareAndDelete[string, int](c, "a", 2)
+			require.NoError(t, err)
+			assert.False(t, deleted)
+			_, err = c.Get("a")
+			require.NoError(t, err)
+
+			deleted, err = cache.CompareAndDelete[string, int](c, "missing", 1)
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+			assert.False(t, deleted)
+
+			// Racing deletes of the same value succeed exactly once
+			var wg sync.WaitGroup
+			results := make([]bool, 2)
+			start := make(chan struct{})
+			for i := range results {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					<-start
+					results[i], _ = cache.CompareAndDelete[string, int](c, "a", 1)
+				}(i)
+			}
+			close(start)
+			wg.Wait()
+			assert.ElementsMatch(t, []bool{true, false}, results)
+			_, err = c.Get("a")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+		})
+	}
+}
+
+// TestCompareAndSwap tests conditional updates and their atomicity
+func TestCompareAndSwap(t *testing.T) {
+	type swapper interface {
+		cache.Cache[string, int]
+		cache.ConditionalSetter[string, int]
+	}
+	caches := map[string]swapper{
+		"FIFO": strategies.NewFIFOCache[string, int](10),
+		"LRU":  strategies.NewLRUCache[string, int](10),
+		"LFU":  strategies.NewLFUCache[string, int](10),
+		"TTL":  strategies.NewTTLCache[string, int](10, time.Hour),
+		"ARC":  strategies.NewARCCache[string, int](10),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, c.Set("a", 1))
+
+			swapped, err := cache.CompareAndSwap[string, int](c, "a", 2, 3)
+			require.NoError(t, err)
+			assert.False(t, swapped)
+			val, err := c.Get("a")
+			require.NoError(t, err)
+			assert.Equal(t, 1, val)
+
+			swapped, err = cache.CompareAndSwap[string, int](c, "missing", 0, 1)
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+			assert.False(t, swapped)
+			_, err = c.Get("missing")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+
+			// Concurrent increments retrying on conflict lose no update
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 50; j++ {
+						for {
+							old, _ := c.Get("a")
+							if swapped, _ := cache.CompareAndSwap[string, int](c, "a", old, old+1); swapped {
+								break
+							}
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			val, err = c.Get("a")
+			require.NoError(t, err)
+			assert.Equal(t, 401, val)
+		})
+	}
+}
+
+// TestCompareFunc tests the variants comparing values that are not
+// comparable
+func TestCompareFunc(t *testing.T) {
+	c := strategies.NewLRUCache[string, []int](10)
+	eq := func(a, b []int) bool { return assert.ObjectsAreEqual(a, b) }
+	require.NoError(t, c.Set("a", []int{1, 2}))
+
+	swapped, err := cache.CompareAndSwapFunc[string, []int](c, "a", []int{1}, []int{3}, eq)
+	require.NoError(t, err)
+	assert.False(t, swapped)
+	swapped, err = cache.CompareAndSwapFunc[string, []int](c, "a", []int{1, 2}, []int{3}, eq)
+	require.NoError(t, err)
+	assert.True(t, swapped)
+	val, err := c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, val)
+
+	deleted, err := cache.CompareAndDeleteFunc[string, []int](c, "a", []int{1, 2}, eq)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	deleted, err = cache.CompareAndDeleteFunc[string, []int](c, "a", []int{3}, eq)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+}
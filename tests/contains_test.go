@@ -0,0 +1,82 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContains tests membership checks across the policies
+func TestContains(t *testing.T) {
+	type container interface {
+		cache.Cache[string, int]
+		Contains(key string) bool
+	}
+	caches := map[string]container{
+		"FIFO":         strategies.NewFIFOCache[string, int](2),
+		"LRU":          strategies.NewLRUCache[string, int](2),
+		"LFU":          strategies.NewLFUCache[string, int](2),
+		"ARC":          strategies.NewARCCache[string, int](2),
+		"TTL":          strategies.NewTTLCache[string, int](2, time.Hour),
+		"LRUK":         strategies.NewLRUKCache[string, int](2, 2),
+		"WindowedLFU":  strategies.NewWindowedLFUCache[string, int](2, time.Hour),
+		"MRU":          strategies.NewMRUCache[string, int](2),
+		"Random":       strategies.NewRandomCache[string, int](2, nil),
+		"Clock":        strategies.NewClockCache[string, int](2),
+		"SLRU":         strategies.NewSLRUCache[string, int](1, 1),
+		"TwoQueue":     strategies.NewTwoQueueCache[string, int](2),
+		"LIRS":         strategies.NewLIRSCache[string, int](2),
+		"TinyLFU":      strategies.NewTinyLFUCache[string, int](2),
+		"SecondChance": strategies.NewSecondChanceCache[string, int](2),
+		"CAR":          strategies.NewCARCache[string, int](2),
+		"Sieve":        strategies.NewSieveCache[string, int](2),
+		"S3FIFO":       strategies.NewS3FIFOCache[string, int](2),
+		"GDSF":         strategies.NewGDSFCache[string, int](2),
+		"Priority":     strategies.NewPriorityCache[string, int](2),
+		"Policy":       strategies.NewPolicyCache[string, int](2, strategies.NewLRUPolicy[string]()),
+		"Optimal":      strategies.NewOfflineOptimalCache[string, int](2, nil),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, c.Set("a", 1))
+			assert.True(t, c.Contains("a"))
+			assert.False(t, c.Contains("b"))
+			require.NoError(t, c.Delete("a"))
+			assert.False(t, c.Contains("a"))
+		})
+	}
+
+	// Checking a doesn't make it recently used
+	lru := strategies.NewLRUCache[string, int](2)
+	require.NoError(t, lru.Set("a", 1))
+	require.NoError(t, lru.Set("b", 2))
+	assert.True(t, lru.Contains("a"))
+	require.NoError(t, lru.Set("c", 3))
+	assert.False(t, lru.Contains("a"))
+
+	// Ghosts are not stored
+	car := strategies.NewCARCache[string, int](1)
+	require.NoError(t, car.Set("a", 1))
+	require.NoError(t, car.Set("b", 2))
+	assert.False(t, car.Contains("a"))
+
+	// Neither are expired entries
+	clock := cachetest.NewClock(time.Unix(0, 0))
+	ttl := strategies.NewTTLCache[string, int](2, time.Minute, strategies.WithClock(clock))
+	require.NoError(t, ttl.Set("a", 1))
+	clock.Advance(time.Hour)
+	assert.False(t, ttl.Contains("a"))
+
+	ints := strategies.NewIntFIFOCache[int](2, 4)
+	require.NoError(t, ints.Set(1, 1))
+	assert.True(t, ints.Contains(1))
+	assert.False(t, ints.Contains(2))
+	assert.False(t, ints.Contains(-1))
+	assert.False(t, ints.Contains(4))
+}
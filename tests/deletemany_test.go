@@ -0,0 +1,40 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchDeleter is implemented by every cache supporting DeleteMany
+type batchDeleter interface {
+	Set(key int, value int) error
+	DeleteMany(keys []int) int
+	Keys() []int
+}
+
+// TestDeleteMany tests removing several keys at once, skipping misses and
+// duplicates
+func TestDeleteMany(t *testing.T) {
+	caches := map[string]batchDeleter{
+		"FIFO": strategies.NewFIFOCache[int, int](4),
+		"LRU":  strategies.NewLRUCache[int, int](4),
+		"LFU":  strategies.NewLFUCache[int, int](4),
+		"TTL":  strategies.NewTTLCache[int, int](4, time.Hour),
+		"ARC":  strategies.NewARCCache[int, int](4),
+	}
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			for key := 0; key < 4; key++ {
+				require.NoError(t, c.Set(key, key))
+			}
+
+			assert.Equal(t, 2, c.DeleteMany([]int{1, 7, 3, 1}))
+			assert.ElementsMatch(t, []int{0, 2}, c.Keys())
+			assert.Equal(t, 0, c.DeleteMany(nil))
+		})
+	}
+}
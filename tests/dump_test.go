@@ -0,0 +1,74 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestString tests the debug rendering of each policy after a scripted
+// sequence of operations
+func TestString(t *testing.T) {
+	t.Run("FIFO", func(t *testing.T) {
+		c := strategies.NewFIFOCache[string, int](3)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		require.NoError(t, c.Set("c", 3))
+		_, _ = c.Get("a")
+		require.NoError(t, c.Set("d", 4))
+		assert.Equal(t, "FIFO 3/3 [b=2 c=3 d=4]", c.String())
+
+		require.NoError(t, c.SetPinned("z", 26))
+		require.NoError(t, c.SetPinned("y", 25))
+		assert.Equal(t, "FIFO 3/3 [b=2 c=3 d=4] pinned[y=25 z=26]", c.String())
+	})
+
+	t.Run("LRU", func(t *testing.T) {
+		c := strategies.NewLRUCache[string, int](3)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		require.NoError(t, c.Set("c", 3))
+		_, _ = c.Get("a")
+		require.NoError(t, c.Set("d", 4))
+		assert.Equal(t, "LRU 3/3 [c=3 a=1 d=4]", c.String())
+	})
+
+	t.Run("LFU", func(t *testing.T) {
+		c := strategies.NewLFUCache[string, int](3)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		require.NoError(t, c.Set("c", 3))
+		_, _ = c.Get("b")
+		_, _ = c.Get("b")
+		_, _ = c.Get("a")
+		assert.Equal(t, "LFU 3/3 [f1: c=3 | f2: a=1 | f3: b=2]", c.String())
+		require.NoError(t, c.Set("d", 4))
+		require.NoError(t, c.Set("e", 5))
+		assert.Equal(t, "LFU 3/3 [f1: e=5 | f2: a=1 | f3: b=2]", c.String())
+	})
+
+	t.Run("ARC", func(t *testing.T) {
+		c := strategies.NewARCCache[string, int](2)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		_, _ = c.Get("a")
+		require.NoError(t, c.Set("c", 3))
+		assert.Equal(t, "ARC 2/2 p=0 T1[c=3] T2[a=1] B1[b] B2[]", c.String())
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Now())
+		c := strategies.NewTTLCache[string, int](3, 10*time.Second, strategies.WithClock(clock))
+		require.NoError(t, c.Set("a", 1))
+		clock.Advance(4 * time.Second)
+		require.NoError(t, c.Set("b", 2))
+		clock.Advance(3 * time.Second)
+		assert.Equal(t, "TTL 2/3 [a=1 (3s) b=2 (7s)]", c.String())
+		clock.Advance(4 * time.Second)
+		assert.Equal(t, "TTL 2/3 [a=1 (expired) b=2 (3s)]", c.String())
+	})
+}
@@ -0,0 +1,86 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventSource is implemented by every cache that can stream eviction events
+type eventSource interface {
+	Set(key string, value int) error
+	Delete(key string) error
+	Clear()
+	EvictionEvents() <-chan strategies.EvictionEvent[string, int]
+}
+
+// drain collects the events buffered so far
+func drain(events <-chan strategies.EvictionEvent[string, int]) []strategies.EvictionEvent[string, int] {
+	var got []strategies.EvictionEvent[string, int]
+	for {
+		select {
+		case event := <-events:
+			got = append(got, event)
+		default:
+			return got
+		}
+	}
+}
+
+// TestEvictionEvents tests that removals are streamed in order with their reason
+func TestEvictionEvents(t *testing.T) {
+	caches := map[string]eventSource{
+		"FIFO": strategies.NewFIFOCache[string, int](2, strategies.WithEvictionEvents(16)),
+		"LRU":  strategies.NewLRUCache[string, int](2, strategies.WithEvictionEvents(16)),
+		"LFU":  strategies.NewLFUCache[string, int](2, strategies.WithEvictionEvents(16)),
+		"TTL":  strategies.NewTTLCache[string, int](2, time.Hour, strategies.WithEvictionEvents(16)),
+		"ARC":  strategies.NewARCCache[string, int](2, strategies.WithEvictionEvents(16)),
+	}
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, c.Set("a", 1))
+			require.NoError(t, c.Set("b", 2))
+			require.NoError(t, c.Set("c", 3))
+			require.NoError(t, c.Delete("b"))
+			c.Clear()
+
+			assert.Equal(t, []strategies.EvictionEvent[string, int]{
+				{Key: "a", Value: 1, Reason: strategies.ReasonCapacity},
+				{Key: "b", Value: 2, Reason: strategies.ReasonDeleted},
+				{Key: "c", Value: 3, Reason: strategies.ReasonCleared},
+			}, drain(c.EvictionEvents()))
+		})
+	}
+
+	t.Run("Expired", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Unix(0, 0))
+		c := strategies.NewTTLCache[string, int](2, 20*time.Millisecond,
+			strategies.WithClock(clock), strategies.WithEvictionEvents(4))
+		require.NoError(t, c.Set("a", 1))
+		clock.Advance(40 * time.Millisecond)
+		_, err := c.Get("a")
+		assert.Error(t, err)
+		assert.Equal(t, []strategies.EvictionEvent[string, int]{
+			{Key: "a", Value: 1, Reason: strategies.ReasonExpired},
+		}, drain(c.EvictionEvents()))
+	})
+
+	t.Run("DropOldest", func(t *testing.T) {
+		c := strategies.NewFIFOCache[string, int](1, strategies.WithEvictionEvents(2))
+		for i, key := range []string{"a", "b", "c", "d"} {
+			require.NoError(t, c.Set(key, i))
+		}
+		assert.Equal(t, []strategies.EvictionEvent[string, int]{
+			{Key: "b", Value: 1, Reason: strategies.ReasonCapacity},
+			{Key: "c", Value: 2, Reason: strategies.ReasonCapacity},
+		}, drain(c.EvictionEvents()))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		assert.Nil(t, strategies.NewLRUCache[string, int](2).EvictionEvents())
+	})
+}
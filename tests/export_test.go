@@ -0,0 +1,59 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapCache is a cache exporting and importing its contents as a map
+type mapCache interface {
+	cache.Cache[string, int]
+	ToMap() map[string]int
+	FromMap(m map[string]int) error
+}
+
+// TestMapExportImport tests round-tripping cache contents through a map
+func TestMapExportImport(t *testing.T) {
+	seed := map[string]int{"a": 1, "b": 2, "c": 3}
+	caches := map[string]func(capacity int) mapCache{
+		"FIFO": func(capacity int) mapCache { return strategies.NewFIFOCache[string, int](capacity) },
+		"LRU":  func(capacity int) mapCache { return strategies.NewLRUCache[string, int](capacity) },
+		"LFU":  func(capacity int) mapCache { return strategies.NewLFUCache[string, int](capacity) },
+		"TTL": func(capacity int) mapCache {
+			return strategies.NewTTLCache[string, int](capacity, time.Minute)
+		},
+		"ARC": func(capacity int) mapCache { return strategies.NewARCCache[string, int](capacity) },
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			c := newCache(5)
+			require.NoError(t, c.FromMap(seed))
+			assert.Equal(t, seed, c.ToMap())
+
+			// The export is a copy
+			exported := c.ToMap()
+			exported["z"] = 26
+			assert.NotContains(t, c.ToMap(), "z")
+
+			// Importing more entries than fit applies the capacity
+			small := newCache(2)
+			require.NoError(t, small.FromMap(seed))
+			assert.Len(t, small.ToMap(), 2)
+		})
+	}
+
+	// Imported TTL entries get the default TTL
+	clock := cachetest.NewClock(time.Unix(0, 0))
+	c := strategies.NewTTLCache[string, int](5, 50*time.Millisecond, strategies.WithClock(clock))
+	require.NoError(t, c.FromMap(seed))
+	assert.Len(t, c.ToMap(), 3)
+	clock.Advance(70 * time.Millisecond)
+	assert.Empty(t, c.ToMap())
+}
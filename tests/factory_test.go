@@ -0,0 +1,169 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewByPolicy tests creating caches by policy name
+func TestNewByPolicy(t *testing.T) {
+	policies := map[string][]cache.Option{
+		"fifo": nil,
+		"lru":  {cache.WithAccessTracking()},
+		"LFU":  nil,
+		"ttl":  {cache.WithTTL(time.Minute)},
+		"arc":  nil,
+	}
+	for policy, opts := range policies {
+		t.Run(policy, func(t *testing.T) {
+			c, err := cache.New[string, int](policy, 2, opts...)
+			require.NoError(t, err)
+
+			require.NoError(t, c.Set("a", 1))
+			val, err := c.Get("a")
+			require.NoError(t, err)
+			assert.Equal(t, 1, val)
+		})
+	}
+
+	_, err := cache.New[string, int]("random", 2)
+	assert.ErrorIs(t, err, cache.ErrUnknownPolicy)
+
+	// TTL options only apply to the TTL policy, which requires one
+	_, err = cache.New[string, int]("lru", 2, cache.WithTTL(time.Minute))
+	assert.ErrorIs(t, err, cache.ErrUnsupportedOption)
+	_, err = cache.New[string, int]("ttl", 2)
+	assert.ErrorIs(t, err, cache.ErrUnsupportedOption)
+}
+
+// TestNewWithOnEvict tests registering an eviction callback through New
+func TestNewWithOnEvict(t *testing.T) {
+	for _, policy := range []string{"fifo", "lru", "lfu", "ttl", "arc"} {
+		t.Run(policy, func(t *testing.T) {
+			var evicted []string
+			opts := []cache.Option{cache.WithOnEvict(func(key string, value int) {
+				evicted = append(evicted, key)
+			})}
+			if policy == "ttl" {
+				opts = append(opts, cache.WithTTL(time.Minute))
+			}
+			c, err := cache.New[string, int](policy, 1, opts...)
+			require.NoError(t, err)
+
+			require.NoError(t, c.Set("a", 1))
+			require.NoError(t, c.Set("b", 2))
+			require.NoError(t, c.Delete("b"))
+			assert.Equal(t, []string{"a"}, evicted)
+		})
+	}
+
+	// The callback must match the key and value types of the cache
+	_, err := cache.New[string, int]("lru", 2, cache.WithOnEvict(func(key int, value int) {}))
+	assert.ErrorIs(t, err, cache.ErrUnsupportedOption)
+}
+
+// TestNewCache tests creating caches from options alone
+func TestNewCache(t *testing.T) {
+	policies := map[string][]cache.Option{
+		"FIFO": {cache.WithFIFO()},
+		"LRU":  {cache.WithLRU(), cache.WithAccessTracking()},
+		"LFU":  {cache.WithLFU()},
+		"ARC":  {cache.WithARC(), cache.WithAccessTracking()},
+		"TTL":  {cache.WithTTL(time.Minute)},
+		"Name": {cache.WithPolicy("LRU")},
+	}
+	for name, opts := range policies {
+		t.Run(name, func(t *testing.T) {
+			var evicted []string
+			opts = append(opts, cache.WithCapacity(2), cache.WithOnEvict(func(key string, _ int) {
+				evicted = append(evicted, key)
+			}))
+			c, err := cache.NewCache[string, int](opts...)
+			require.NoError(t, err)
+
+			for i, key := range []string{"a", "b", "c"} {
+				require.NoError(t, c.Set(key, i))
+			}
+			val, err := c.Get("c")
+			require.NoError(t, err)
+			assert.Equal(t, 2, val)
+			assert.NotEmpty(t, evicted)
+		})
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		c, err := cache.NewCache[string, int](cache.WithCapacity(2))
+		require.NoError(t, err)
+		assert.IsType(t, &strategies.LRUCache[string, int]{}, c)
+
+		c, err = cache.NewCache[string, int](cache.WithCapacity(2), cache.WithTTL(time.Minute))
+		require.NoError(t, err)
+		assert.IsType(t, &strategies.TTLCache[string, int]{}, c)
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		var stats cache.Stats
+		var evicted []string
+		c, err := cache.NewCache[string, int](cache.WithCapacity(2), cache.WithStats(&stats),
+			cache.WithOnEvict(func(key string, _ int) { evicted = append(evicted, key) }))
+		require.NoError(t, err)
+		assert.Zero(t, stats.HitRate())
+
+		for i, key := range []string{"a", "b", "c"} {
+			require.NoError(t, c.Set(key, i))
+		}
+		_, err = c.Get("a")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		_, err = c.Get("c")
+		require.NoError(t, err)
+		_, err = c.Get("b")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), stats.Hits())
+		assert.Equal(t, uint64(1), stats.Misses())
+		assert.InDelta(t, 2.0/3, stats.HitRate(), 1e-9)
+		assert.Equal(t, uint64(1), stats.Evictions())
+		assert.Equal(t, []string{"a"}, evicted)
+	})
+
+	t.Run("StrategyOptions", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Unix(0, 0))
+		c, err := cache.NewCache[string, int](cache.WithCapacity(2), cache.WithTTL(time.Minute),
+			cache.WithStrategyOptions(strategies.WithClock(clock), strategies.WithEvictionEvents(4)))
+		require.NoError(t, err)
+		require.NoError(t, c.Set("a", 1))
+		clock.Advance(2 * time.Minute)
+		_, err = c.Get("a")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		event := <-c.(*strategies.TTLCache[string, int]).EvictionEvents()
+		assert.Equal(t, "a", event.Key)
+		assert.Equal(t, strategies.ReasonExpired, event.Reason)
+	})
+
+	t.Run("Tracking", func(t *testing.T) {
+		c, err := cache.NewCache[string, int](cache.WithFIFO(), cache.WithCapacity(2), cache.WithAccessTracking())
+		require.NoError(t, err)
+		require.NoError(t, c.Set("a", 1))
+		_, err = c.Get("a")
+		require.NoError(t, err)
+		top := c.(*strategies.FIFOCache[string, int]).TopKeys(1)
+		assert.Equal(t, []strategies.KeyCount[string]{{Key: "a", Count: 2}}, top)
+	})
+
+	_, err := cache.NewCache[string, int](cache.WithLRU())
+	assert.ErrorIs(t, err, cache.ErrUnsupportedOption)
+	_, err = cache.NewCache[string, int](cache.WithPolicy("random"), cache.WithCapacity(2))
+	assert.ErrorIs(t, err, cache.ErrUnknownPolicy)
+	_, err = cache.NewCache[string, int](cache.WithLFU(), cache.WithCapacity(2), cache.WithTTL(time.Minute))
+	assert.ErrorIs(t, err, cache.ErrUnsupportedOption)
+
+	// The arguments of New take precedence over the policy options
+	c, err := cache.New[string, int]("fifo", 2, cache.WithARC(), cache.WithCapacity(10))
+	require.NoError(t, err)
+	assert.IsType(t, &strategies.FIFOCache[string, int]{}, c)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -13,38 +14,179 @@ func TestFIFOCache(t *testing.T) {
 	c := cache.NewFIFOCache[string, int](3)
 
 	// Test basic operations
-	err := c.Set("a", 1)
-	require.NoError(t, err)
+	t.Run("Basic", func(t *testing.T) {
+		err := c.Set("a", 1)
+		require.NoError(t, err)
 
-	val, err := c.Get("a")
-	require.NoError(t, err)
-	assert.Equal(t, 1, val)
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, val)
+	})
 
 	// Test FIFO eviction
-	err = c.Set("b", 2)
-	require.NoError(t, err)
-	err = c.Set("c", 3)
-	require.NoError(t, err)
-	err = c.Set("d", 4) // This should evict "a"
-	require.NoError(t, err)
+	t.Run("Eviction", func(t *testing.T) {
+		err := c.Set("b", 2)
+		require.NoError(t, err)
+		err = c.Set("c", 3)
+		require.NoError(t, err)
+		err = c.Set("d", 4) // This should evict "a"
+		require.NoError(t, err)
 
-	_, err = c.Get("a")
-	assert.Error(t, err)
-	assert.Equal(t, cache.ErrKeyNotFound, err)
+		_, err = c.Get("a")
+		assert.Error(t, err)
+		assert.Equal(t, cache.ErrKeyNotFound, err)
 
-	val, err = c.Get("b")
-	require.NoError(t, err)
-	assert.Equal(t, 2, val)
+		val, err := c.Get("b")
+		require.NoError(t, err)
+		assert.Equal(t, 2, val)
+	})
 
 	// Test delete
-	err = c.Delete("b")
-	require.NoError(t, err)
+	t.Run("Delete", func(t *testing.T) {
+		err := c.Delete("b")
+		require.NoError(t, err)
 
-	_, err = c.Get("b")
-	assert.Error(t, err)
+		_, err = c.Get("b")
+		assert.Error(t, err)
+	})
 
 	// Test clear
+	t.Run("Clear", func(t *testing.T) {
+		c.Clear()
+		_, err := c.Get("c")
+		assert.Error(t, err)
+	})
+}
+
+// TestIntFIFOCache tests the int FIFO cache, including its key range bounds
+func TestIntFIFOCache(t *testing.T) {
+	c := cache.NewIntFIFOCache[string](3, 128)
+
+	t.Run("Range", func(t *testing.T) {
+		for _, key := range []int{-1, 128, 1000} {
+			assert.Equal(t, cache.ErrKeyOutOfRange, c.Set(key, "x"))
+			_, err := c.Get(key)
+			assert.Equal(t, cache.ErrKeyOutOfRange, err)
+			assert.Equal(t, cache.ErrKeyOutOfRange, c.Delete(key))
+		}
+		// The edges of the key space, including both sides of a bitset word
+		for _, key := range []int{0, 63, 64} {
+			require.NoError(t, c.Set(key, "x"))
+		}
+		_, err := c.Get(65)
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		c.Clear()
+		require.NoError(t, c.Set(127, "last"))
+		val, err := c.Get(127)
+		require.NoError(t, err)
+		assert.Equal(t, "last", val)
+		_, err = c.Get(0)
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		c.Clear()
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		for key := 1; key <= 3; key++ {
+			require.NoError(t, c.Set(key, "v"))
+		}
+		// Overwrites keep their position
+		require.NoError(t, c.Set(1, "w"))
+		require.NoError(t, c.Set(4, "v"))
+		_, err := c.Get(1)
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+
+		// Deleting from the middle of the queue
+		require.NoError(t, c.Delete(3))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete(3))
+		require.NoError(t, c.Set(5, "v"))
+		require.NoError(t, c.Set(6, "v"))
+		_, err = c.Get(2)
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		for _, key := range []int{4, 5, 6} {
+			_, err := c.Get(key)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("ZeroCapacity", func(t *testing.T) {
+		assert.Equal(t, cache.ErrCacheFull, cache.NewIntFIFOCache[string](0, 8).Set(1, "x"))
+	})
+
+	t.Run("EvictCallback", func(t *testing.T) {
+		c := strategies.NewIntFIFOCache[string](2, 8)
+		var evicted []int
+		c.SetEvictCallback(func(key int, value string) {
+			evicted = append(evicted, key)
+			assert.Equal(t, "v", value)
+			// The lock is released before the callback runs
+			assert.False(t, c.Contains(key))
+		})
+		for _, key := range []int{1, 2, 3, 4} {
+			require.NoError(t, c.Set(key, "v"))
+		}
+		require.NoError(t, c.Delete(3))
+		c.Clear()
+		assert.Equal(t, []int{1, 2}, evicted)
+	})
+}
+
+// BenchmarkIntFIFO compares the int FIFO cache with the generic one on dense
+// int keys
+func BenchmarkIntFIFO(b *testing.B) {
+	const capacity, keySpace = 1024, 4096
+	caches := map[string]cache.Cache[int, int]{
+		"Generic": cache.NewFIFOCache[int, int](capacity),
+		"Int":     cache.NewIntFIFOCache[int](capacity, keySpace),
+	}
+	for name, c := range caches {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				key := (i * 7) % keySpace
+				if _, err := c.Get(key); err != nil {
+					_ = c.Set(key, i)
+				}
+			}
+		})
+	}
+}
+
+// TestSecondChanceCache tests that read entries are spared once
+func TestSecondChanceCache(t *testing.T) {
+	c := strategies.NewSecondChanceCache[string, int](3)
+	var evicted []string
+	c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+	require.NoError(t, c.Set("a", 1))
+	require.NoError(t, c.Set("b", 2))
+	require.NoError(t, c.Set("c", 3))
+
+	// a was read, so it moves behind c and b goes instead
+	_, err := c.Get("a")
+	require.NoError(t, err)
+	require.NoError(t, c.Set("d", 4))
+	assert.Equal(t, []string{"b"}, evicted)
+
+	// a used up its second chance
+	require.NoError(t, c.Set("e", 5))
+	require.NoError(t, c.Set("f", 6))
+	assert.Equal(t, []string{"b", "c", "a"}, evicted)
+
+	// With every entry read the queue goes round once, as plain FIFO
+	for _, key := range []string{"d", "e", "f"} {
+		_, err := c.Get(key)
+		require.NoError(t, err)
+	}
+	require.NoError(t, c.Set("g", 7))
+	assert.Equal(t, []string{"b", "c", "a", "d"}, evicted)
+
+	// Overwrites neither move entries nor set their read bit
+	require.NoError(t, c.Set("e", 50))
+	require.NoError(t, c.Set("h", 8))
+	assert.Equal(t, []string{"b", "c", "a", "d", "e"}, evicted)
+
+	require.NoError(t, c.Delete("f"))
+	assert.Equal(t, cache.ErrKeyNotFound, c.Delete("f"))
 	c.Clear()
-	_, err = c.Get("c")
-	assert.Error(t, err)
-} 
\ No newline at end of file
+	assert.Empty(t, c.Keys())
+	assert.Equal(t, cache.ErrCacheFull, cache.NewSecondChanceCache[string, int](0).Set("a", 1))
+}
@@ -0,0 +1,66 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestForEach tests bounded scans over the entries of every policy
+func TestForEach(t *testing.T) {
+	type visitable interface {
+		cache.Cache[string, int]
+		Keys() []string
+		ForEach(fn func(key string, value int) bool)
+	}
+	caches := map[string]visitable{
+		"FIFO": strategies.NewFIFOCache[string, int](4),
+		"LRU":  strategies.NewLRUCache[string, int](4),
+		"LFU":  strategies.NewLFUCache[string, int](4),
+		"ARC":  strategies.NewARCCache[string, int](4),
+		"TTL":  strategies.NewTTLCache[string, int](4, time.Hour),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			for i, key := range []string{"a", "b", "c", "d"} {
+				require.NoError(t, c.Set(key, i))
+			}
+			_, err := c.Get("b")
+			require.NoError(t, err)
+
+			var keys []string
+			c.ForEach(func(key string, _ int) bool {
+				keys = append(keys, key)
+				return true
+			})
+			assert.Equal(t, c.Keys(), keys)
+
+			// The scan stops at the first entry fn rejects
+			keys = nil
+			c.ForEach(func(key string, _ int) bool {
+				keys = append(keys, key)
+				return len(keys) < 2
+			})
+			assert.Equal(t, c.Keys()[:2], keys)
+		})
+	}
+
+	// Scanning takes no snapshot
+	lru := strategies.NewLRUCache[int, int](100)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, lru.Set(i, i))
+	}
+	sum := 0
+	allocs := testing.AllocsPerRun(10, func() {
+		lru.ForEach(func(_, value int) bool {
+			sum += value
+			return true
+		})
+	})
+	assert.Zero(t, allocs)
+}
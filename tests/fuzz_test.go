@@ -0,0 +1,131 @@
+package cache_test
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+)
+
+// model is an obviously correct reference implementation of a policy. keys
+// lists the resident keys, next victim first.
+type model struct {
+	capacity int
+	keys     []int
+	values   map[int]int
+	recency  bool // hits move keys to the back, as in LRU
+}
+
+// newModel creates an empty model, of an LRU if recency is set and of a
+// FIFO otherwise
+func newModel(capacity int, recency bool) *model {
+	return &model{capacity: capacity, values: make(map[int]int), recency: recency}
+}
+
+// get returns the value of key
+func (m *model) get(key int) (int, bool) {
+	value, ok := m.values[key]
+	if ok && m.recency {
+		m.touch(key)
+	}
+	return value, ok
+}
+
+// set stores value for key, evicting the first key of a full model
+func (m *model) set(key, value int) {
+	if _, ok := m.values[key]; ok {
+		if m.recency {
+			m.touch(key)
+		}
+	} else {
+		if len(m.keys) == m.capacity {
+			delete(m.values, m.keys[0])
+			m.keys = m.keys[1:]
+		}
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// delete removes key and reports whether it was resident
+func (m *model) delete(key int) bool {
+	i := slices.Index(m.keys, key)
+	if i < 0 {
+		return false
+	}
+	m.keys = slices.Delete(m.keys, i, i+1)
+	delete(m.values, key)
+	return true
+}
+
+// touch moves key to the back of the eviction order
+func (m *model) touch(key int) {
+	i := slices.Index(m.keys, key)
+	m.keys = append(slices.Delete(m.keys, i, i+1), key)
+}
+
+// modelCache is the part of a cache compared against its model
+type modelCache interface {
+	cache.Cache[int, int]
+	Keys() []int
+}
+
+// FuzzCacheAgainstModel replays random operations against each cache and
+// its reference model, comparing every result and the resident keys after
+// every step. Each pair of input bytes encodes one operation. A failure
+// reports the operations replayed so far, which the fuzzer minimizes.
+func FuzzCacheAgainstModel(f *testing.F) {
+	f.Add([]byte{1, 0, 1, 1, 1, 2, 1, 3, 0, 0, 1, 4, 0, 1})
+	f.Add([]byte{1, 5, 1, 6, 2, 5, 1, 7, 0, 6, 1, 5, 1, 0, 1, 1})
+	f.Add([]byte{1, 0, 1, 0, 0, 0, 1, 1, 1, 2, 1, 3, 1, 4, 0, 0})
+
+	const capacity = 4
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		for name, recency := range map[string]bool{"FIFO": false, "LRU": true} {
+			var c modelCache
+			if recency {
+				c = strategies.NewLRUCache[int, int](capacity)
+			} else {
+				c = strategies.NewFIFOCache[int, int](capacity)
+			}
+			m := newModel(capacity, recency)
+
+			var trace []string
+			for i := 0; i+1 < len(ops); i += 2 {
+				key := int(ops[i+1] % 8)
+				switch ops[i] % 3 {
+				case 0:
+					trace = append(trace, fmt.Sprintf("Get(%d)", key))
+					got, err := c.Get(key)
+					want, ok := m.get(key)
+					if (err == nil) != ok || ok && got != want {
+						t.Fatalf("%s: %v: got %d, %v, want %d, %v", name, trace, got, err, want, ok)
+					}
+				case 1:
+					trace = append(trace, fmt.Sprintf("Set(%d, %d)", key, i))
+					if err := c.Set(key, i); err != nil {
+						t.Fatalf("%s: %v: %v", name, trace, err)
+					}
+					m.set(key, i)
+				case 2:
+					trace = append(trace, fmt.Sprintf("Delete(%d)", key))
+					err := c.Delete(key)
+					if ok := m.delete(key); (err == nil) != ok {
+						t.Fatalf("%s: %v: got %v, want found %v", name, trace, err, ok)
+					}
+				}
+
+				keys := c.Keys()
+				sort.Ints(keys)
+				want := slices.Clone(m.keys)
+				sort.Ints(want)
+				if !slices.Equal(keys, want) {
+					t.Fatalf("%s: %v: resident keys %v, want %v", name, trace, keys, want)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGDSFCache tests the GreedyDual-Size-Frequency cache implementation
+func TestGDSFCache(t *testing.T) {
+	newCache := func(capacity int64) (*strategies.GDSFCache[string, int], *[]string) {
+		c := strategies.NewGDSFCache[string, int](capacity)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+		return c, &evicted
+	}
+
+	// A large entry gives way to small ones of the same cost
+	t.Run("Size", func(t *testing.T) {
+		c, evicted := newCache(10)
+		require.NoError(t, c.SetWithCost("big", 1, 6, 1))
+		require.NoError(t, c.SetWithCost("a", 2, 2, 1))
+		require.NoError(t, c.SetWithCost("b", 3, 2, 1))
+		assert.Equal(t, int64(10), c.Size())
+
+		require.NoError(t, c.SetWithCost("c", 4, 2, 1))
+		assert.Equal(t, []string{"big"}, *evicted)
+		assert.Equal(t, int64(6), c.Size())
+		assert.ElementsMatch(t, []string{"a", "b", "c"}, c.Keys())
+	})
+
+	// A costly entry outlives cheap ones of the same size
+	t.Run("Cost", func(t *testing.T) {
+		c, evicted := newCache(3)
+		require.NoError(t, c.SetWithCost("x", 1, 1, 1))
+		require.NoError(t, c.SetWithCost("y", 2, 1, 5))
+		require.NoError(t, c.SetWithCost("z", 3, 1, 1))
+		require.NoError(t, c.Set("w", 4))
+		require.NoError(t, c.Set("v", 5))
+		assert.Equal(t, []string{"x", "z"}, *evicted)
+	})
+
+	// Reads raise the priority, and the inflation term eventually lets new
+	// entries displace an entry hot long ago
+	t.Run("Inflation", func(t *testing.T) {
+		c, evicted := newCache(2)
+		require.NoError(t, c.Set("a", 1))
+		for i := 0; i < 3; i++ {
+			_, err := c.Get("a")
+			require.NoError(t, err)
+		}
+		for i, key := range []string{"b", "c", "d", "e", "f"} {
+			require.NoError(t, c.Set(key, i))
+		}
+		assert.Equal(t, []string{"b", "c", "d", "a"}, *evicted)
+	})
+
+	t.Run("Size limits", func(t *testing.T) {
+		c, _ := newCache(4)
+		require.NoError(t, c.SetWithCost("a", 1, 4, 1))
+		assert.Equal(t, cache.ErrCacheFull, c.SetWithCost("a", 2, 5, 1))
+		assert.Equal(t, cache.ErrCacheFull, c.SetWithCost("b", 2, 0, 1))
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, val)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewGDSFCache[string, int](4)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Delete("a"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+		require.NoError(t, c.Set("b", 2))
+		c.Clear()
+		_, err := c.Get("b")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+	})
+}
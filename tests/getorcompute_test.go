@@ -0,0 +1,58 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetOrCompute tests that the loader of every policy only runs on misses
+func TestGetOrCompute(t *testing.T) {
+	type computer interface {
+		cache.Cache[string, int]
+		GetOrCompute(key string, fn func() (int, error)) (int, error)
+	}
+	caches := map[string]computer{
+		"FIFO": strategies.NewFIFOCache[string, int](2),
+		"LRU":  strategies.NewLRUCache[string, int](2),
+		"LFU":  strategies.NewLFUCache[string, int](2),
+		"ARC":  strategies.NewARCCache[string, int](2),
+		"TTL":  strategies.NewTTLCache[string, int](2, time.Hour),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			load := func() (int, error) {
+				calls++
+				return 42, nil
+			}
+			for i := 0; i < 3; i++ {
+				value, err := c.GetOrCompute("a", load)
+				require.NoError(t, err)
+				assert.Equal(t, 42, value)
+			}
+			assert.Equal(t, 1, calls)
+
+			// Failed loads store nothing
+			errLoad := errors.New("load failed")
+			_, err := c.GetOrCompute("b", func() (int, error) { return 0, errLoad })
+			assert.Equal(t, errLoad, err)
+			_, err = c.Get("b")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+
+			// A value stored while fn ran wins over the result of fn
+			value, err := c.GetOrCompute("c", func() (int, error) {
+				require.NoError(t, c.Set("c", 1))
+				return 2, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 1, value)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package cache_test
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getOrSetter is a cache supporting GetOrSet
+type getOrSetter interface {
+	cache.Cache[string, int]
+	GetOrSet(key string, value int) (int, bool, error)
+}
+
+// TestGetOrSet tests the atomic read-or-insert of every policy
+func TestGetOrSet(t *testing.T) {
+	caches := map[string]func(capacity int) getOrSetter{
+		"FIFO": func(capacity int) getOrSetter { return strategies.NewFIFOCache[string, int](capacity) },
+		"LRU":  func(capacity int) getOrSetter { return strategies.NewLRUCache[string, int](capacity) },
+		"LFU":  func(capacity int) getOrSetter { return strategies.NewLFUCache[string, int](capacity) },
+		"ARC":  func(capacity int) getOrSetter { return strategies.NewARCCache[string, int](capacity) },
+		"TTL": func(capacity int) getOrSetter {
+			return strategies.NewTTLCache[string, int](capacity, time.Hour)
+		},
+		"MRU": func(capacity int) getOrSetter { return strategies.NewMRUCache[string, int](capacity) },
+		"Random": func(capacity int) getOrSetter {
+			return strategies.NewRandomCache[string, int](capacity, rand.NewSource(1))
+		},
+		"CLOCK": func(capacity int) getOrSetter { return strategies.NewClockCache[string, int](capacity) },
+		"SLRU": func(capacity int) getOrSetter {
+			return strategies.NewSLRUCache[string, int](capacity/2, capacity-capacity/2)
+		},
+		"2Q":           func(capacity int) getOrSetter { return strategies.NewTwoQueueCache[string, int](capacity) },
+		"LIRS":         func(capacity int) getOrSetter { return strategies.NewLIRSCache[string, int](capacity) },
+		"TinyLFU":      func(capacity int) getOrSetter { return strategies.NewTinyLFUCache[string, int](capacity) },
+		"CAR":          func(capacity int) getOrSetter { return strategies.NewCARCache[string, int](capacity) },
+		"SIEVE":        func(capacity int) getOrSetter { return strategies.NewSieveCache[string, int](capacity) },
+		"S3-FIFO":      func(capacity int) getOrSetter { return strategies.NewS3FIFOCache[string, int](capacity) },
+		"GDSF":         func(capacity int) getOrSetter { return strategies.NewGDSFCache[string, int](int64(capacity)) },
+		"Priority":     func(capacity int) getOrSetter { return strategies.NewPriorityCache[string, int](capacity) },
+		"SecondChance": func(capacity int) getOrSetter { return strategies.NewSecondChanceCache[string, int](capacity) },
+		"Policy": func(capacity int) getOrSetter {
+			return strategies.NewPolicyCache[string, int](capacity, strategies.NewLRUPolicy[string]())
+		},
+		"LRU-K": func(capacity int) getOrSetter { return strategies.NewLRUKCache[string, int](capacity, 2) },
+		"WindowedLFU": func(capacity int) getOrSetter {
+			return strategies.NewWindowedLFUCache[string, int](capacity, time.Minute)
+		},
+		"Optimal": func(capacity int) getOrSetter {
+			return strategies.NewOfflineOptimalCache[string, int](capacity, nil)
+		},
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			c := newCache(2)
+			actual, loaded, err := c.GetOrSet("a", 1)
+			require.NoError(t, err)
+			assert.Equal(t, 1, actual)
+			assert.False(t, loaded)
+
+			actual, loaded, err = c.GetOrSet("a", 2)
+			require.NoError(t, err)
+			assert.Equal(t, 1, actual)
+			assert.True(t, loaded)
+			assert.Equal(t, 1, cache.GetDefault[string, int](c, "a", 0))
+
+			// Concurrent callers all see the value of the single winner
+			c = newCache(2)
+			var stored atomic.Int32
+			values := make([]int, 16)
+			var wg sync.WaitGroup
+			for i := range values {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					actual, loaded, err := c.GetOrSet("k", i)
+					assert.NoError(t, err)
+					if !loaded {
+						stored.Add(1)
+					}
+					values[i] = actual
+				}(i)
+			}
+			wg.Wait()
+			assert.Equal(t, int32(1), stored.Load())
+			for _, value := range values {
+				assert.Equal(t, values[0], value)
+			}
+
+			_, _, err = newCache(0).GetOrSet("a", 1)
+			assert.Equal(t, cache.ErrCacheFull, err)
+		})
+	}
+
+	// Dense int keys outside the key space are rejected
+	ints := strategies.NewIntFIFOCache[int](2, 4)
+	actual, loaded, err := ints.GetOrSet(1, 10)
+	require.NoError(t, err)
+	assert.False(t, loaded)
+	assert.Equal(t, 10, actual)
+	actual, loaded, err = ints.GetOrSet(1, 20)
+	require.NoError(t, err)
+	assert.True(t, loaded)
+	assert.Equal(t, 10, actual)
+	_, _, err = ints.GetOrSet(4, 1)
+	assert.Equal(t, cache.ErrKeyOutOfRange, err)
+
+	// A hit counts once towards the LFU frequency: a ends up read twice and
+	// b three times, so a goes
+	lfu := strategies.NewLFUCache[string, int](2)
+	require.NoError(t, lfu.Set("b", 2))
+	for i := 0; i < 2; i++ {
+		_, err := lfu.Get("b")
+		require.NoError(t, err)
+		_, _, err = lfu.GetOrSet("a", 1)
+		require.NoError(t, err)
+	}
+	require.NoError(t, lfu.Set("c", 3))
+	_, err = lfu.Get("a")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	_, err = lfu.Get("b")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,58 @@
+package cache_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/httpcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTPCache tests serving repeated GET requests from the cache
+func TestHTTPCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/cached":
+			w.Header().Set("Cache-Control", "public, max-age=60")
+		case "/no-store":
+			w.Header().Set("Cache-Control", "no-store, max-age=60")
+		}
+		_, _ = io.WriteString(w, "body of "+r.URL.Path)
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: httpcache.New(cache.NewLRUCache[string, []byte](8), nil),
+	}
+	get := func(path string) string {
+		resp, err := client.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	// The second identical GET is served without hitting the network
+	assert.Equal(t, "body of /cached", get("/cached"))
+	assert.Equal(t, "body of /cached", get("/cached"))
+	assert.Equal(t, int32(1), requests.Load())
+
+	// no-store responses are fetched every time
+	assert.Equal(t, "body of /no-store", get("/no-store"))
+	assert.Equal(t, "body of /no-store", get("/no-store"))
+	assert.Equal(t, int32(3), requests.Load())
+
+	// Responses without max-age aren't cached either
+	get("/plain")
+	get("/plain")
+	assert.Equal(t, int32(5), requests.Load())
+}
@@ -0,0 +1,93 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIncrement tests concurrent counters on integer and float caches
+func TestIncrement(t *testing.T) {
+	ints := map[string]cache.Updater[string, int]{
+		"FIFO": strategies.NewFIFOCache[string, int](4),
+		"LRU":  strategies.NewLRUCache[string, int](4),
+		"LFU":  strategies.NewLFUCache[string, int](4),
+		"TTL":  strategies.NewTTLCache[string, int](4, time.Minute),
+		"ARC":  strategies.NewARCCache[string, int](4),
+	}
+	for name, c := range ints {
+		t.Run(name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 100; j++ {
+						_, err := cache.Increment(c, "hits", 1)
+						assert.NoError(t, err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			total, err := cache.Increment(c, "hits", 0)
+			require.NoError(t, err)
+			assert.Equal(t, 1000, total)
+		})
+	}
+
+	floats := strategies.NewLRUCache[string, float64](4)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, _ = cache.Increment[string, float64](floats, "load", 0.5)
+			}
+		}()
+	}
+	wg.Wait()
+	val, err := floats.Get("load")
+	require.NoError(t, err)
+	assert.Equal(t, 400.0, val)
+
+	// A negative delta decrements an existing counter
+	val, err = cache.Increment[string, float64](floats, "load", -100)
+	require.NoError(t, err)
+	assert.Equal(t, 300.0, val)
+}
+
+// TestDecrement tests counting down, including unsigned counters
+func TestDecrement(t *testing.T) {
+	c := strategies.NewLFUCache[string, uint](4)
+	_, err := cache.Increment[string, uint](c, "tokens", 10)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 2; j++ {
+				_, err := cache.Decrement[string, uint](c, "tokens", 1)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	val, err := c.Get("tokens")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), val)
+
+	// A missing key counts down from zero
+	neg, err := cache.Decrement[string, int](strategies.NewLRUCache[string, int](4), "debt", 3)
+	require.NoError(t, err)
+	assert.Equal(t, -3, neg)
+}
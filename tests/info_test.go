@@ -0,0 +1,84 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// infoCache is a cache reporting per-entry metadata
+type infoCache interface {
+	cache.Cache[string, int]
+	EntryInfo(key string) (strategies.Info, error)
+}
+
+// TestEntryInfo tests access counts and timestamps of cached entries
+func TestEntryInfo(t *testing.T) {
+	caches := map[string]infoCache{
+		"LRU": strategies.NewLRUCache[string, int](3),
+		"LFU": strategies.NewLFUCache[string, int](3),
+		"TTL": strategies.NewTTLCache[string, int](3, time.Minute),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			before := time.Now()
+			require.NoError(t, c.Set("a", 1))
+
+			info, err := c.EntryInfo("a")
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1), info.AccessCount)
+			assert.False(t, info.CreatedAt.Before(before))
+			assert.Equal(t, info.CreatedAt, info.LastAccessedAt)
+
+			time.Sleep(time.Millisecond)
+			for i := 0; i < 3; i++ {
+				_, err = c.Get("a")
+				require.NoError(t, err)
+			}
+
+			// EntryInfo itself is not an access
+			info, err = c.EntryInfo("a")
+			require.NoError(t, err)
+			info, err = c.EntryInfo("a")
+			require.NoError(t, err)
+			assert.Equal(t, uint64(4), info.AccessCount)
+			assert.True(t, info.LastAccessedAt.After(info.CreatedAt))
+			assert.False(t, info.LastAccessedAt.After(time.Now()))
+
+			_, err = c.EntryInfo("missing")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+		})
+	}
+}
+
+// TestEntryInfoPinned tests that pinned entries carry no metadata
+func TestEntryInfoPinned(t *testing.T) {
+	type pinningCache interface {
+		infoCache
+		SetPinned(key string, value int) error
+		Unpin(key string) error
+	}
+	caches := map[string]pinningCache{
+		"LRU": strategies.NewLRUCache[string, int](3),
+		"LFU": strategies.NewLFUCache[string, int](3),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, c.SetPinned("a", 1))
+			_, err := c.EntryInfo("a")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+
+			// Unpinning starts the metadata afresh
+			require.NoError(t, c.Unpin("a"))
+			info, err := c.EntryInfo("a")
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1), info.AccessCount)
+		})
+	}
+}
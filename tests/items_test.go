@@ -0,0 +1,84 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestItems tests that the snapshots of every policy follow eviction order
+func TestItems(t *testing.T) {
+	type enumerable interface {
+		cache.Cache[string, int]
+		Keys() []string
+		Values() []int
+		Items() []strategies.Entry[string, int]
+	}
+	tests := map[string]struct {
+		c    enumerable
+		keys []string
+	}{
+		"FIFO": {strategies.NewFIFOCache[string, int](4), []string{"a", "b", "c"}},
+		"LRU":  {strategies.NewLRUCache[string, int](4), []string{"b", "c", "a"}},
+		"LFU":  {strategies.NewLFUCache[string, int](4), []string{"b", "c", "a"}},
+		"ARC":  {strategies.NewARCCache[string, int](4), []string{"b", "c", "a"}},
+		"TTL":  {strategies.NewTTLCache[string, int](4, time.Hour), []string{"a", "b", "c"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := tt.c
+			values := map[string]int{"a": 1, "b": 2, "c": 3}
+			for _, key := range []string{"a", "b", "c"} {
+				require.NoError(t, c.Set(key, values[key]))
+			}
+			_, err := c.Get("a")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.keys, c.Keys())
+			var want []int
+			var items []strategies.Entry[string, int]
+			for _, key := range tt.keys {
+				want = append(want, values[key])
+				items = append(items, strategies.Entry[string, int]{Key: key, Value: values[key]})
+			}
+			assert.Equal(t, want, c.Values())
+			assert.Equal(t, items, c.Items())
+		})
+	}
+
+	// Pinned entries come last
+	lru := strategies.NewLRUCache[string, int](2)
+	require.NoError(t, lru.SetPinned("p", 0))
+	require.NoError(t, lru.Set("a", 1))
+	require.NoError(t, lru.Set("b", 2))
+	assert.Equal(t, []string{"a", "b", "p"}, lru.Keys())
+
+	// ARC hands out t2 first once t1 is down to its target
+	arc := strategies.NewARCCache[string, int](4)
+	for i, key := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, arc.Set(key, i))
+	}
+	_, _ = arc.Get("a")
+	_, _ = arc.Get("b")
+	require.NoError(t, arc.Set("e", 4))
+	require.NoError(t, arc.Set("c", 2))
+	assert.Equal(t, []string{"a", "b", "c", "e"}, arc.Keys())
+	var evicted []string
+	arc.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+	require.NoError(t, arc.Set("x", 5))
+	assert.Equal(t, []string{"a"}, evicted)
+
+	// Expired entries are left out
+	clock := cachetest.NewClock(time.Unix(0, 0))
+	ttl := strategies.NewTTLCache[string, int](4, time.Minute, strategies.WithClock(clock))
+	require.NoError(t, ttl.Set("a", 1))
+	clock.Advance(time.Hour)
+	require.NoError(t, ttl.Set("b", 2))
+	assert.Equal(t, []strategies.Entry[string, int]{{Key: "b", Value: 2}}, ttl.Items())
+}
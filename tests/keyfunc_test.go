@@ -0,0 +1,70 @@
+package cache_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"caching-labwork/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKeyFuncCache tests caches keyed through a canonical key function
+func TestKeyFuncCache(t *testing.T) {
+	// Slice keys aren't comparable but can be canonicalized
+	slices := cache.NewLRUCacheFunc[[]int, string](2, func(key []int) string {
+		return fmt.Sprint(key)
+	})
+	require.NoError(t, slices.Set([]int{1, 2}, "one-two"))
+	require.NoError(t, slices.Set([]int{3}, "three"))
+
+	val, err := slices.Get([]int{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, "one-two", val)
+
+	// LRU eviction still applies: {3} is the least recently used key
+	require.NoError(t, slices.Set([]int{4}, "four"))
+	_, err = slices.Get([]int{3})
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+
+	// Case-insensitive string keys hit regardless of spelling
+	names := cache.NewFIFOCacheFunc[string, int](2, strings.ToLower)
+	require.NoError(t, names.Set("Alice", 1))
+
+	val2, err := names.Get("ALICE")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val2)
+
+	require.NoError(t, names.Set("alice", 2))
+	val2, err = names.Get("Alice")
+	require.NoError(t, err)
+	assert.Equal(t, 2, val2)
+
+	require.NoError(t, names.Delete("aLiCe"))
+	_, err = names.Get("alice")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+}
+
+// TestKeyFuncCacheOriginalKeys tests that Keys and the eviction callback see
+// the original keys
+func TestKeyFuncCacheOriginalKeys(t *testing.T) {
+	c := cache.NewLFUCacheFunc[string, int](2, strings.ToLower)
+	var evicted []string
+	c.SetEvictCallback(func(key string, value int) {
+		evicted = append(evicted, fmt.Sprintf("%s=%d", key, value))
+	})
+
+	require.NoError(t, c.Set("Alice", 1))
+	require.NoError(t, c.Set("BOB", 2))
+	_, err := c.Get("alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BOB", "Alice"}, c.Keys())
+
+	// Overwrites store the key given last, and both entries now have the
+	// same frequency, so the least recently used one is evicted
+	require.NoError(t, c.Set("bob", 3))
+	require.NoError(t, c.Set("Carol", 4))
+	assert.Equal(t, []string{"Carol", "bob"}, c.Keys())
+	assert.Equal(t, []string{"Alice=1"}, evicted)
+}
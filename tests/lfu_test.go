@@ -1,9 +1,13 @@
 package cache_test
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -13,41 +17,162 @@ func TestLFUCache(t *testing.T) {
 	c := cache.NewLFUCache[string, int](3)
 
 	// Test basic operations
-	err := c.Set("a", 1)
-	require.NoError(t, err)
-	err = c.Set("b", 2)
-	require.NoError(t, err)
-	err = c.Set("c", 3)
-	require.NoError(t, err)
+	t.Run("Basic", func(t *testing.T) {
+		err := c.Set("a", 1)
+		require.NoError(t, err)
+		err = c.Set("b", 2)
+		require.NoError(t, err)
+		err = c.Set("c", 3)
+		require.NoError(t, err)
 
-	// Access "a" multiple times to increase its frequency
-	_, err = c.Get("a")
-	require.NoError(t, err)
-	_, err = c.Get("a")
-	require.NoError(t, err)
-	_, err = c.Get("a")
-	require.NoError(t, err)
+		// Access "a" multiple times to increase its frequency
+		_, err = c.Get("a")
+		require.NoError(t, err)
+		_, err = c.Get("a")
+		require.NoError(t, err)
+		_, err = c.Get("a")
+		require.NoError(t, err)
 
-	// Access "b" once
-	_, err = c.Get("b")
-	require.NoError(t, err)
+		// Access "b" once
+		_, err = c.Get("b")
+		require.NoError(t, err)
+	})
 
 	// Add "d" - should evict "c" (least frequently used)
-	err = c.Set("d", 4)
-	require.NoError(t, err)
+	t.Run("Eviction", func(t *testing.T) {
+		err := c.Set("d", 4)
+		require.NoError(t, err)
 
-	_, err = c.Get("c")
-	assert.Error(t, err)
+		_, err = c.Get("c")
+		assert.Error(t, err)
 
-	val, err := c.Get("a")
-	require.NoError(t, err)
-	assert.Equal(t, 1, val)
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, val)
 
-	val, err = c.Get("b")
-	require.NoError(t, err)
-	assert.Equal(t, 2, val)
+		val, err = c.Get("b")
+		require.NoError(t, err)
+		assert.Equal(t, 2, val)
+
+		val, err = c.Get("d")
+		require.NoError(t, err)
+		assert.Equal(t, 4, val)
+	})
+}
 
-	val, err = c.Get("d")
+// TestLFUCacheTieBreak tests that ties in frequency evict the least recently used entry
+func TestLFUCacheTieBreak(t *testing.T) {
+	c := cache.NewLFUCache[string, int](3)
+
+	require.NoError(t, c.Set("a", 1))
+	require.NoError(t, c.Set("b", 2))
+	require.NoError(t, c.Set("c", 3))
+
+	// All entries end up with the same frequency, "a" used longest ago
+	for _, key := range []string{"a", "b", "c"} {
+		_, err := c.Get(key)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, c.Set("d", 4))
+	_, err := c.Get("a")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+
+	// "d" is now the only entry with the lowest frequency
+	require.NoError(t, c.Set("e", 5))
+	_, err = c.Get("d")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+
+	for _, key := range []string{"b", "c", "e"} {
+		_, err := c.Get(key)
+		assert.NoError(t, err)
+	}
+}
+
+// BenchmarkLFUEviction measures eviction cost, which should not grow with capacity
+func BenchmarkLFUEviction(b *testing.B) {
+	for _, capacity := range []int{100, 1000, 10000, 100000} {
+		b.Run(fmt.Sprintf("cap=%d", capacity), func(b *testing.B) {
+			c := cache.NewLFUCache[int, int](capacity)
+			for i := 0; i < capacity; i++ {
+				_ = c.Set(i, i)
+				_, _ = c.Get(i)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = c.Set(capacity+i, i)
+			}
+		})
+	}
+}
+
+// TestWindowedLFUCache tests that popularity fades once the window passes
+func TestWindowedLFUCache(t *testing.T) {
+	clock := cachetest.NewClock(time.Now())
+	c := strategies.NewWindowedLFUCache[string, int](2, time.Minute, strategies.WithClock(clock))
+	var evicted []string
+	c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+
+	// Make a hot
+	require.NoError(t, c.Set("a", 1))
+	for i := 0; i < 10; i++ {
+		_, err := c.Get("a")
+		require.NoError(t, err)
+	}
+	require.NoError(t, c.Set("b", 2))
+	require.NoError(t, c.Set("c", 3))
+	assert.Equal(t, []string{"b"}, evicted)
+
+	// Past the window a's accesses no longer count
+	clock.Advance(2 * time.Minute)
+	_, err := c.Get("c")
 	require.NoError(t, err)
-	assert.Equal(t, 4, val)
-} 
\ No newline at end of file
+	require.NoError(t, c.Set("d", 4))
+	assert.Equal(t, []string{"b", "a"}, evicted)
+	_, err = c.Get("a")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+}
+
+// TestLFUFrequencyDecay tests that an entry hot long ago ages out with
+// decay and stays forever without it
+func TestLFUFrequencyDecay(t *testing.T) {
+	run := func(opts ...strategies.Option) []string {
+		c := strategies.NewLFUCache[string, int](2, opts...)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+		require.NoError(t, c.Set("old", 0))
+		for i := 0; i < 20; i++ {
+			_, err := c.Get("old")
+			require.NoError(t, err)
+		}
+		// A stream of new keys, each used a few times
+		for i := 0; i < 10; i++ {
+			key := fmt.Sprint("new", i)
+			require.NoError(t, c.Set(key, i))
+			for j := 0; j < 3; j++ {
+				_, err := c.Get(key)
+				require.NoError(t, err)
+			}
+		}
+		return evicted
+	}
+
+	assert.NotContains(t, run(), "old")
+	assert.Contains(t, run(strategies.WithFrequencyDecay(4)), "old")
+}
+
+// TestLFUFrequencyDecayOrder tests that halving keeps the eviction order of
+// entries whose frequencies collide
+func TestLFUFrequencyDecayOrder(t *testing.T) {
+	c := strategies.NewLFUCache[string, int](3, strategies.WithFrequencyDecay(6))
+	require.NoError(t, c.Set("a", 1))
+	require.NoError(t, c.Set("b", 2))
+	require.NoError(t, c.Set("c", 3))
+	_, _ = c.Get("b")
+	_, _ = c.Get("c")
+	_, _ = c.Get("c")
+	// The sixth access halved a=1, b=2 and c=3 down to 1, 1 and 1
+	assert.Equal(t, "LFU 3/3 [f1: a=1 b=2 c=3]", c.String())
+}
@@ -0,0 +1,86 @@
+package cache_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLIRSCache tests the LIRS cache implementation
+func TestLIRSCache(t *testing.T) {
+	t.Run("ReuseDistance", func(t *testing.T) {
+		// Two LIR slots and one HIR slot
+		c := strategies.NewLIRSCache[string, int](3)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		require.NoError(t, c.Set("c", 3))
+		_, err := c.Get("a")
+		require.NoError(t, err)
+
+		// d takes the HIR slot of c, which stays known as non-resident
+		require.NoError(t, c.Set("d", 4))
+		assert.Equal(t, []string{"c"}, evicted)
+
+		// c comes back with a shorter reuse distance than b and turns LIR,
+		// demoting b
+		require.NoError(t, c.Set("c", 3))
+		assert.Equal(t, []string{"c", "d"}, evicted)
+		assert.ElementsMatch(t, []string{"a", "b", "c"}, c.Keys())
+		require.NoError(t, c.Set("e", 5))
+		assert.Equal(t, []string{"c", "d", "b"}, evicted)
+	})
+
+	t.Run("Loop", func(t *testing.T) {
+		// A loop just larger than the cache defeats LRU but not LIRS
+		c := cache.NewLIRSCache[int, int](10)
+		hits := 0
+		for round := 0; round < 10; round++ {
+			for key := 0; key < 11; key++ {
+				if _, err := c.Get(key); err == nil {
+					hits++
+				} else {
+					require.NoError(t, c.Set(key, key))
+				}
+			}
+		}
+		assert.Greater(t, hits, 70)
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		for _, capacity := range []int{1, 2, 5, 50} {
+			c := strategies.NewLIRSCache[int, int](capacity)
+			want := make(map[int]int)
+			r := rand.New(rand.NewSource(int64(capacity)))
+			for i := 0; i < 5000; i++ {
+				key := r.Intn(capacity * 3)
+				switch r.Intn(4) {
+				case 0:
+					if val, err := c.Get(key); err == nil {
+						require.Equal(t, want[key], val)
+					}
+				case 1:
+					_ = c.Delete(key)
+				default:
+					require.NoError(t, c.Set(key, i))
+					want[key] = i
+				}
+				require.LessOrEqual(t, len(c.Keys()), capacity)
+			}
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewLIRSCache[string, int](3)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Delete("a"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+		c.Clear()
+		assert.Equal(t, cache.ErrCacheFull, cache.NewLIRSCache[string, int](0).Set("a", 1))
+	})
+}
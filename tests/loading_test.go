@@ -0,0 +1,105 @@
+package cache_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadingCache tests loading, background refresh and failed refreshes
+func TestLoadingCache(t *testing.T) {
+	var version atomic.Int32
+	var failing atomic.Bool
+	loader := func(key string) (int, error) {
+		if failing.Load() {
+			return 0, errors.New("backend down")
+		}
+		return int(version.Add(1)), nil
+	}
+	c := cache.NewLoadingCache[string, int](10, loader, 30*time.Millisecond)
+
+	// Missing keys are loaded once
+	val, err := c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	val, err = c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+
+	// Past the window the old value is returned while a refresh runs
+	time.Sleep(40 * time.Millisecond)
+	val, err = c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	assert.Eventually(t, func() bool {
+		val, err := c.Get("a")
+		return err == nil && val == 2
+	}, time.Second, 5*time.Millisecond)
+
+	// A failing refresh keeps the previous value
+	failing.Store(true)
+	time.Sleep(40 * time.Millisecond)
+	_, err = c.Get("a")
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return c.RefreshFailures() > 0 }, time.Second, 5*time.Millisecond)
+	val, err = c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 2, val)
+
+	// Initial load errors are returned and nothing is cached
+	_, err = c.Get("b")
+	assert.EqualError(t, err, "backend down")
+	failing.Store(false)
+	val, err = c.Get("b")
+	require.NoError(t, err)
+	assert.Equal(t, 3, val)
+}
+
+// TestLoadingCacheRefreshRaces tests background refreshes racing a Delete
+// and refreshes whose loader panics
+func TestLoadingCacheRefreshRaces(t *testing.T) {
+	var version atomic.Int32
+	var panicking atomic.Bool
+	entered, resume := make(chan struct{}, 1), make(chan struct{})
+	loader := func(key string) (int, error) {
+		if panicking.Load() {
+			panic("backend exploded")
+		}
+		if version.Load() == 1 {
+			entered <- struct{}{}
+			<-resume
+		}
+		return int(version.Add(1)), nil
+	}
+	c := cache.NewLoadingCache[string, int](10, loader, 30*time.Millisecond)
+	_, err := c.Get("a")
+	require.NoError(t, err)
+
+	// A refresh finishing after its key was deleted doesn't bring it back
+	time.Sleep(40 * time.Millisecond)
+	_, err = c.Get("a")
+	require.NoError(t, err)
+	<-entered
+	require.NoError(t, c.Delete("a"))
+	close(resume)
+	time.Sleep(20 * time.Millisecond)
+	val, err := c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 3, val, "a deleted key is loaded again")
+
+	// A panicking loader counts as a failed refresh and keeps the value
+	panicking.Store(true)
+	time.Sleep(40 * time.Millisecond)
+	val, err = c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 3, val)
+	assert.Eventually(t, func() bool { return c.RefreshFailures() == 1 }, time.Second, 5*time.Millisecond)
+	val, err = c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 3, val)
+}
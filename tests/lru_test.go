@@ -13,34 +13,120 @@ func TestLRUCache(t *testing.T) {
 	c := cache.NewLRUCache[string, int](3)
 
 	// Test basic operations
-	err := c.Set("a", 1)
-	require.NoError(t, err)
-	err = c.Set("b", 2)
-	require.NoError(t, err)
-	err = c.Set("c", 3)
-	require.NoError(t, err)
-
-	// Access "a" to make it most recently used
-	val, err := c.Get("a")
-	require.NoError(t, err)
-	assert.Equal(t, 1, val)
+	t.Run("Basic", func(t *testing.T) {
+		err := c.Set("a", 1)
+		require.NoError(t, err)
+		err = c.Set("b", 2)
+		require.NoError(t, err)
+		err = c.Set("c", 3)
+		require.NoError(t, err)
+
+		// Access "a" to make it most recently used
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, val)
+	})
 
 	// Add "d" - should evict "b" (least recently used)
-	err = c.Set("d", 4)
-	require.NoError(t, err)
+	t.Run("Eviction", func(t *testing.T) {
+		err := c.Set("d", 4)
+		require.NoError(t, err)
+
+		_, err = c.Get("b")
+		assert.Error(t, err)
+
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, val)
+
+		val, err = c.Get("c")
+		require.NoError(t, err)
+		assert.Equal(t, 3, val)
+
+		val, err = c.Get("d")
+		require.NoError(t, err)
+		assert.Equal(t, 4, val)
+	})
+}
+
+// BenchmarkLRUGetWarm checks that hits on a warm LRU cache don't allocate
+func BenchmarkLRUGetWarm(b *testing.B) {
+	const capacity = 1024
+	c := cache.NewLRUCache[int, int](capacity)
+	for i := 0; i < capacity; i++ {
+		_ = c.Set(i, i)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = c.Get(capacity / 2)
+	})
+	if allocs != 0 {
+		b.Fatalf("Get on a warm cache: got %v allocs/op, want 0", allocs)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = c.Get(i % capacity)
+	}
+}
+
+// TestMRUCache tests the MRU cache implementation
+func TestMRUCache(t *testing.T) {
+	c := cache.NewMRUCache[string, int](3)
+
+	t.Run("Basic", func(t *testing.T) {
+		require.NoError(t, c.Set("a", 1))
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, val)
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		require.NoError(t, c.Set("b", 2))
+		require.NoError(t, c.Set("c", 3))
+
+		// Access a to make it the most recently used
+		_, err := c.Get("a")
+		require.NoError(t, err)
 
-	_, err = c.Get("b")
-	assert.Error(t, err)
+		// Add d, should evict a
+		require.NoError(t, c.Set("d", 4))
+		_, err = c.Get("a")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
 
-	val, err = c.Get("a")
-	require.NoError(t, err)
-	assert.Equal(t, 1, val)
+		// d was used last, so e replaces it
+		require.NoError(t, c.Set("e", 5))
+		_, err = c.Get("d")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		for key, want := range map[string]int{"b": 2, "c": 3, "e": 5} {
+			val, err := c.Get(key)
+			require.NoError(t, err)
+			assert.Equal(t, want, val)
+		}
+	})
 
-	val, err = c.Get("c")
-	require.NoError(t, err)
-	assert.Equal(t, 3, val)
+	t.Run("Scan", func(t *testing.T) {
+		// A cyclic scan one larger than the cache keeps hitting
+		c := cache.NewMRUCache[int, int](3)
+		hits := 0
+		for round := 0; round < 5; round++ {
+			for key := 0; key < 4; key++ {
+				if _, err := c.Get(key); err == nil {
+					hits++
+				} else {
+					require.NoError(t, c.Set(key, key))
+				}
+			}
+		}
+		assert.Greater(t, hits, 10)
+	})
 
-	val, err = c.Get("d")
-	require.NoError(t, err)
-	assert.Equal(t, 4, val)
-} 
\ No newline at end of file
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, c.Delete("b"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("b"))
+		c.Clear()
+		_, err := c.Get("c")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+	})
+}
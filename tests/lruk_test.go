@@ -0,0 +1,84 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLRUKCache tests the LRU-K cache implementation with K=2
+func TestLRUKCache(t *testing.T) {
+	// A key referenced twice is kept over a key referenced once, even if the
+	// latter was used more recently
+	t.Run("History", func(t *testing.T) {
+		c := cache.NewLRUKCache[string, int](2, 2)
+		require.NoError(t, c.Set("a", 1))
+		_, err := c.Get("a")
+		require.NoError(t, err)
+		require.NoError(t, c.Set("b", 2))
+		require.NoError(t, c.Set("c", 3))
+
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, val)
+		_, err = c.Get("b")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+	})
+
+	// Once-referenced entries leave in LRU order, then the oldest second
+	// most recent reference goes
+	t.Run("Eviction", func(t *testing.T) {
+		c := strategies.NewLRUKCache[string, int](3, 2)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		_, _ = c.Get("a")
+		_, _ = c.Get("b")
+		require.NoError(t, c.Set("c", 3))
+		require.NoError(t, c.Set("d", 4))
+		require.NoError(t, c.Set("e", 5))
+		assert.Equal(t, []string{"c", "d"}, evicted)
+
+		// a: refs 1,3; b: refs 2,4 so a has the oldest second reference
+		_, _ = c.Get("e")
+		require.NoError(t, c.Set("f", 6))
+		assert.Equal(t, []string{"c", "d", "a"}, evicted)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewLRUKCache[string, int](2, 2)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Delete("a"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+		require.NoError(t, c.Set("b", 2))
+		c.Clear()
+		_, err := c.Get("b")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+	})
+}
+
+// TestLRUKCorrelationPeriod tests that references close to each other count
+// as one in the LRU-K history
+func TestLRUKCorrelationPeriod(t *testing.T) {
+	evictions := func(opts ...strategies.Option) []string {
+		c := strategies.NewLRUKCache[string, int](2, 2, opts...)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+		require.NoError(t, c.Set("a", 1))
+		_, _ = c.Get("a")
+		require.NoError(t, c.Set("b", 2))
+		require.NoError(t, c.Set("c", 3))
+		return evicted
+	}
+
+	// Without a period a has two references and b goes; with one the read
+	// right after the Set of a is correlated with it, so a has a single
+	// reference older than the one of b
+	assert.Equal(t, []string{"b"}, evictions())
+	assert.Equal(t, []string{"a"}, evictions(strategies.WithCorrelationPeriod(1)))
+}
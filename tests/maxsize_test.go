@@ -0,0 +1,34 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMaxValueSize tests that oversized values never reach the inner cache
+func TestMaxValueSize(t *testing.T) {
+	inner := cache.NewLRUCache[string, []byte](10)
+	c := cache.NewMaxValueSize(inner, func(v []byte) int64 { return int64(len(v)) }, 4)
+
+	require.NoError(t, c.Set("small", []byte("abcd")))
+	val, err := inner.Get("small")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("abcd"), val)
+
+	assert.Equal(t, cache.ErrValueTooLarge, c.Set("big", []byte("abcde")))
+	_, err = inner.Get("big")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+
+	// A rejected overwrite keeps the previous value
+	assert.Equal(t, cache.ErrValueTooLarge, c.Set("small", []byte("abcdef")))
+	val, err = c.Get("small")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("abcd"), val)
+
+	require.NoError(t, c.Delete("small"))
+	_, err = inner.Get("small")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+}
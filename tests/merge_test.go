@@ -0,0 +1,65 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replicas returns two LRU caches sharing some keys
+func replicas(t *testing.T) (local, remote *strategies.LRUCache[string, int]) {
+	local = strategies.NewLRUCache[string, int](10)
+	remote = strategies.NewLRUCache[string, int](10)
+	require.NoError(t, local.FromMap(map[string]int{"a": 1, "same": 0, "diff": 1}))
+	require.NoError(t, remote.FromMap(map[string]int{"b": 2, "same": 0, "diff": 2}))
+	return local, remote
+}
+
+// TestDiff tests the classification of keys between two caches
+func TestDiff(t *testing.T) {
+	local, remote := replicas(t)
+	onlyHere, onlyThere, differing := cache.Diff[string, int](local, remote)
+	assert.Equal(t, []string{"a"}, onlyHere)
+	assert.Equal(t, []string{"b"}, onlyThere)
+	assert.Equal(t, []string{"diff"}, differing)
+}
+
+// TestMerge tests the combined contents under every merge strategy
+func TestMerge(t *testing.T) {
+	t.Run("PreferLocal", func(t *testing.T) {
+		local, remote := replicas(t)
+		require.NoError(t, cache.Merge[string, int](local, remote, cache.MergePreferLocal))
+		assert.Equal(t, map[string]int{"a": 1, "b": 2, "same": 0, "diff": 1}, local.ToMap())
+		assert.Equal(t, map[string]int{"b": 2, "same": 0, "diff": 2}, remote.ToMap())
+	})
+
+	t.Run("PreferRemote", func(t *testing.T) {
+		local, remote := replicas(t)
+		require.NoError(t, cache.Merge[string, int](local, remote, cache.MergePreferRemote))
+		assert.Equal(t, map[string]int{"a": 1, "b": 2, "same": 0, "diff": 2}, local.ToMap())
+	})
+
+	t.Run("KeepNewest", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Now())
+		local := strategies.NewTTLCache[string, int](10, time.Hour, strategies.WithClock(clock))
+		remote := strategies.NewTTLCache[string, int](10, time.Hour, strategies.WithClock(clock))
+		require.NoError(t, local.Set("older-here", 1))
+		require.NoError(t, remote.Set("newer-here", 2))
+		clock.Advance(time.Minute)
+		require.NoError(t, remote.Set("older-here", 2))
+		require.NoError(t, local.Set("newer-here", 1))
+		require.NoError(t, remote.Set("b", 2))
+
+		require.NoError(t, cache.Merge[string, int](local, remote, cache.MergeKeepNewest))
+		assert.Equal(t, map[string]int{"older-here": 2, "newer-here": 1, "b": 2}, local.ToMap())
+
+		// Caches without expiries can't be merged by age
+		lru, other := replicas(t)
+		assert.ErrorIs(t, cache.Merge[string, int](lru, other, cache.MergeKeepNewest), cache.ErrUnsupportedOption)
+	})
+}
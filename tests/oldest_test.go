@@ -0,0 +1,80 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ender is implemented by every cache exposing both ends of its queue
+type ender interface {
+	Set(key string, value int) error
+	Get(key string) (int, error)
+	Oldest() (string, int, bool)
+	Newest() (string, int, bool)
+}
+
+// assertEnds checks the entries at both ends of the queue of c
+func assertEnds(t *testing.T, c ender, oldest, newest string) {
+	t.Helper()
+	key, _, ok := c.Oldest()
+	require.True(t, ok)
+	assert.Equal(t, oldest, key)
+	key, _, ok = c.Newest()
+	require.True(t, ok)
+	assert.Equal(t, newest, key)
+}
+
+// TestOldestNewest tests inspecting the next victim and the last inserted
+// or used entry
+func TestOldestNewest(t *testing.T) {
+	t.Run("FIFO", func(t *testing.T) {
+		c := strategies.NewFIFOCache[string, int](3)
+		_, _, ok := c.Oldest()
+		assert.False(t, ok)
+		_, _, ok = c.Newest()
+		assert.False(t, ok)
+
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		_, err := c.Get("a")
+		require.NoError(t, err)
+		assertEnds(t, c, "a", "b")
+
+		// Inspection does not disturb the order
+		require.NoError(t, c.Set("c", 3))
+		require.NoError(t, c.Set("d", 4))
+		assertEnds(t, c, "b", "d")
+	})
+
+	t.Run("LRU", func(t *testing.T) {
+		c := strategies.NewLRUCache[string, int](3)
+		_, _, ok := c.Oldest()
+		assert.False(t, ok)
+
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		_, err := c.Get("a")
+		require.NoError(t, err)
+		assertEnds(t, c, "b", "a")
+
+		key, value, ok := c.Oldest()
+		require.True(t, ok)
+		assert.Equal(t, "b", key)
+		assert.Equal(t, 2, value)
+		require.NoError(t, c.Set("c", 3))
+		require.NoError(t, c.Set("d", 4))
+		assertEnds(t, c, "a", "d")
+	})
+
+	t.Run("Pinned", func(t *testing.T) {
+		c := strategies.NewLRUCache[string, int](2)
+		require.NoError(t, c.SetPinned("a", 1))
+		_, _, ok := c.Oldest()
+		assert.False(t, ok)
+		require.NoError(t, c.Set("b", 2))
+		assertEnds(t, c, "b", "b")
+	})
+}
@@ -0,0 +1,84 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOnMiss tests that the miss hook sees every miss and no hit
+func TestOnMiss(t *testing.T) {
+	type missCache interface {
+		cache.Cache[string, int]
+		SetOnMiss(fn func(key string))
+	}
+	caches := map[string]missCache{
+		"FIFO": strategies.NewFIFOCache[string, int](2),
+		"LRU":  strategies.NewLRUCache[string, int](2),
+		"LFU":  strategies.NewLFUCache[string, int](2),
+		"TTL":  strategies.NewTTLCache[string, int](2, time.Hour),
+		"ARC":  strategies.NewARCCache[string, int](2),
+		"LRUK": strategies.NewLRUKCache[string, int](2, 2),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			var misses []string
+			c.SetOnMiss(func(key string) {
+				// The hook runs unlocked and may use the cache
+				_ = c.Set("seen:"+key, 0)
+				misses = append(misses, key)
+			})
+
+			require.NoError(t, c.Set("a", 1))
+			_, err := c.Get("a")
+			require.NoError(t, err)
+			_, err = c.Get("missing")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+			assert.Equal(t, []string{"missing"}, misses)
+		})
+	}
+
+	t.Run("Expired", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Unix(0, 0))
+		c := strategies.NewTTLCache[string, int](2, 10*time.Millisecond, strategies.WithClock(clock))
+		var misses []string
+		c.SetOnMiss(func(key string) { misses = append(misses, key) })
+
+		require.NoError(t, c.Set("a", 1))
+		clock.Advance(20 * time.Millisecond)
+		c.GetBatch([]string{"a", "b"})
+		assert.Equal(t, []string{"a", "b"}, misses)
+	})
+
+	t.Run("GetOrLoad", func(t *testing.T) {
+		inner := strategies.NewLRUCache[string, int](2)
+		var mu sync.Mutex
+		var misses []string
+		inner.SetOnMiss(func(key string) {
+			mu.Lock()
+			defer mu.Unlock()
+			misses = append(misses, key)
+		})
+		c := cache.NewSingleFlightCache[string, int](inner)
+
+		load := func(key string) (int, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			// The miss is reported before the loader runs
+			assert.Equal(t, []string{key}, misses)
+			return 1, nil
+		}
+		_, err := c.GetOrLoad("a", load)
+		require.NoError(t, err)
+		_, err = c.GetOrLoad("a", load)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, misses)
+	})
+}
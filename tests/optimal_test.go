@@ -0,0 +1,50 @@
+package cache_test
+
+import (
+	"strings"
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/bench"
+	"caching-labwork/cache/strategies"
+	"caching-labwork/cache/workload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOfflineOptimalCache tests Belady's offline optimal cache
+func TestOfflineOptimalCache(t *testing.T) {
+	// The entry needed again last goes, entries never needed again first
+	t.Run("Eviction", func(t *testing.T) {
+		trace := strings.Split("a b c a d b a", " ")
+		c := strategies.NewOfflineOptimalCache[string, int](2, trace)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+
+		stats := bench.RunTrace(c, trace)
+		assert.Equal(t, bench.Stats{Hits: 2, Misses: 5}, stats)
+		assert.Equal(t, []string{"b", "c", "d"}, evicted)
+	})
+
+	// No online policy beats it on the same trace
+	t.Run("UpperBound", func(t *testing.T) {
+		trace := workload.Strings(workload.Zipfian(200, 1.1, 5000, 3))
+		results := bench.CompareTrace(trace, 20)
+		opt := results[len(results)-1]
+		require.Equal(t, "OPT", opt.Policy)
+		for _, result := range results[:len(results)-1] {
+			assert.Greater(t, opt.Hits, result.Hits, result.Policy)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewOfflineOptimalCache[string, int](2, []string{"a", "b"})
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Delete("a"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+		require.NoError(t, c.Set("b", 2))
+		c.Clear()
+		_, err := c.Get("b")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+	})
+}
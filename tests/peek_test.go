@@ -0,0 +1,54 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPeek tests that peeking leaves the eviction order untouched
+func TestPeek(t *testing.T) {
+	type peeker interface {
+		cache.Cache[string, int]
+		Peek(key string) (int, error)
+	}
+	caches := map[string]peeker{
+		"FIFO": strategies.NewFIFOCache[string, int](2),
+		"LRU":  strategies.NewLRUCache[string, int](2),
+		"LFU":  strategies.NewLFUCache[string, int](2),
+		"ARC":  strategies.NewARCCache[string, int](2),
+		"TTL":  strategies.NewTTLCache[string, int](2, time.Hour),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			// Were the peeks on a accesses, b would be the victim
+			require.NoError(t, c.Set("a", 1))
+			require.NoError(t, c.Set("b", 2))
+			for i := 0; i < 3; i++ {
+				value, err := c.Peek("a")
+				require.NoError(t, err)
+				assert.Equal(t, 1, value)
+			}
+			require.NoError(t, c.Set("c", 3))
+			_, err := c.Peek("a")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+			value, err := c.Peek("b")
+			require.NoError(t, err)
+			assert.Equal(t, 2, value)
+		})
+	}
+
+	// Expired entries can't be peeked at
+	clock := cachetest.NewClock(time.Unix(0, 0))
+	ttl := strategies.NewTTLCache[string, int](2, time.Minute, strategies.WithClock(clock))
+	require.NoError(t, ttl.Set("a", 1))
+	clock.Advance(time.Hour)
+	_, err := ttl.Peek("a")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+}
@@ -0,0 +1,67 @@
+package cache_test
+
+import (
+	"fmt"
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPinned tests that pinned keys survive overflow until they are unpinned
+func TestPinned(t *testing.T) {
+	type pinner interface {
+		cache.Cache[string, int]
+		SetPinned(key string, value int) error
+		Unpin(key string) error
+		ToMap() map[string]int
+	}
+	caches := map[string]func() pinner{
+		"FIFO": func() pinner { return strategies.NewFIFOCache[string, int](2) },
+		"LRU":  func() pinner { return strategies.NewLRUCache[string, int](2) },
+		"LFU":  func() pinner { return strategies.NewLFUCache[string, int](2) },
+		"ARC":  func() pinner { return strategies.NewARCCache[string, int](2) },
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			c := newCache()
+			require.NoError(t, c.Set("config", 1))
+			require.NoError(t, c.SetPinned("config", 2))
+
+			// Pinned entries survive overflow and don't take up a slot
+			for i := 0; i < 10; i++ {
+				require.NoError(t, c.Set(fmt.Sprint(i), i))
+			}
+			val, err := c.Get("config")
+			require.NoError(t, err)
+			assert.Equal(t, 2, val)
+			assert.Equal(t, map[string]int{"config": 2, "8": 8, "9": 9}, c.ToMap())
+
+			// Overwriting keeps the entry pinned
+			require.NoError(t, c.Set("config", 3))
+			require.NoError(t, c.Set("10", 10))
+			val, err = c.Get("config")
+			require.NoError(t, err)
+			assert.Equal(t, 3, val)
+
+			// No more than capacity entries can be pinned
+			require.NoError(t, c.SetPinned("other", 4))
+			assert.Equal(t, cache.ErrTooManyPinned, c.SetPinned("third", 5))
+			require.NoError(t, c.SetPinned("other", 6))
+			require.NoError(t, c.Delete("other"))
+			assert.Equal(t, cache.ErrKeyNotFound, c.Unpin("other"))
+
+			// Once unpinned the entry is evictable again
+			require.NoError(t, c.Unpin("config"))
+			for i := 20; i < 30; i++ {
+				require.NoError(t, c.Set(fmt.Sprint(i), i))
+			}
+			_, err = c.Get("config")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+			assert.Len(t, c.ToMap(), 2)
+		})
+	}
+}
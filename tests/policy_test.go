@@ -0,0 +1,123 @@
+package cache_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPolicyCache tests the cache built on an EvictionPolicy against the
+// dedicated caches of the same policies
+func TestPolicyCache(t *testing.T) {
+	type evicting interface {
+		cache.Cache[int, int]
+		SetEvictCallback(fn func(key int, value int))
+	}
+	pairs := map[string]func() (evicting, evicting){
+		"FIFO": func() (evicting, evicting) {
+			return strategies.NewPolicyCache[int, int](8, strategies.NewFIFOPolicy[int]()), strategies.NewFIFOCache[int, int](8)
+		},
+		"LRU": func() (evicting, evicting) {
+			return strategies.NewPolicyCache[int, int](8, strategies.NewLRUPolicy[int]()), strategies.NewLRUCache[int, int](8)
+		},
+		"LFU": func() (evicting, evicting) {
+			return strategies.NewPolicyCache[int, int](8, strategies.NewLFUPolicy[int]()), strategies.NewLFUCache[int, int](8)
+		},
+	}
+
+	for name, newPair := range pairs {
+		t.Run(name, func(t *testing.T) {
+			got, want := newPair()
+			var gotEvicted, wantEvicted []int
+			got.SetEvictCallback(func(key, _ int) { gotEvicted = append(gotEvicted, key) })
+			want.SetEvictCallback(func(key, _ int) { wantEvicted = append(wantEvicted, key) })
+
+			rng := rand.New(rand.NewSource(1))
+			for i := 0; i < 2000; i++ {
+				key := rng.Intn(20)
+				switch rng.Intn(4) {
+				case 0, 1:
+					_, gotErr := got.Get(key)
+					_, wantErr := want.Get(key)
+					require.Equal(t, wantErr, gotErr, "step %d", i)
+				case 2:
+					require.NoError(t, got.Set(key, i))
+					require.NoError(t, want.Set(key, i))
+				case 3:
+					require.Equal(t, want.Delete(key), got.Delete(key), "step %d", i)
+				}
+			}
+			assert.Equal(t, wantEvicted, gotEvicted)
+		})
+	}
+}
+
+// TestARCPolicy tests the ARC eviction policy
+func TestARCPolicy(t *testing.T) {
+	c := strategies.NewPolicyCache[string, int](2, strategies.NewARCPolicy[string](2))
+	var evicted []string
+	c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+
+	// b is promoted to t2, so the once-seen a goes first
+	require.NoError(t, c.Set("a", 1))
+	require.NoError(t, c.Set("b", 2))
+	_, err := c.Get("b")
+	require.NoError(t, err)
+	require.NoError(t, c.Set("c", 3))
+	assert.Equal(t, []string{"a"}, evicted)
+
+	// The ghost a comes back into t2 and grows the target of t1, so c stays
+	// and b goes
+	require.NoError(t, c.Set("a", 1))
+	assert.Equal(t, []string{"a", "c"}, evicted)
+	require.NoError(t, c.Set("d", 4))
+	assert.Equal(t, []string{"a", "c", "b"}, evicted)
+	assert.ElementsMatch(t, []string{"a", "d"}, c.Keys())
+}
+
+// firstInPolicy is a policy written outside the package that always evicts
+// the first key it was given
+type firstInPolicy struct {
+	keys []string
+}
+
+func (p *firstInPolicy) OnAdd(key string)    { p.keys = append(p.keys, key) }
+func (p *firstInPolicy) OnAccess(key string) {}
+
+func (p *firstInPolicy) OnRemove(key string) {
+	for i, k := range p.keys {
+		if k == key {
+			p.keys = append(p.keys[:i], p.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+func (p *firstInPolicy) Victim() (string, bool) {
+	if len(p.keys) == 0 {
+		return "", false
+	}
+	key := p.keys[0]
+	p.keys = p.keys[1:]
+	return key, true
+}
+
+// TestCustomPolicy tests a cache running a policy of its own
+func TestCustomPolicy(t *testing.T) {
+	c := cache.NewPolicyCache[string, int](2, &firstInPolicy{})
+	require.NoError(t, c.Set("a", 1))
+	require.NoError(t, c.Set("b", 2))
+	require.NoError(t, c.Delete("a"))
+	require.NoError(t, c.Set("c", 3))
+	require.NoError(t, c.Set("d", 4))
+	assert.Equal(t, -1, cache.GetDefault(c, "b", -1))
+	assert.Equal(t, 3, cache.GetDefault(c, "c", -1))
+
+	c.Clear()
+	assert.Equal(t, cache.ErrKeyNotFound, c.Delete("c"))
+	assert.Equal(t, cache.ErrCacheFull, cache.NewPolicyCache[string, int](0, &firstInPolicy{}).Set("a", 1))
+}
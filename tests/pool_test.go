@@ -0,0 +1,52 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPool tests caches sharing one memory budget
+func TestPool(t *testing.T) {
+	sizeOf := func(v string) int64 { return int64(len(v)) }
+	pool := cache.NewPool(100)
+	users := cache.NewPooledLRU[string, string](pool, sizeOf)
+	orders := cache.NewPooledLRU[int, string](pool, sizeOf)
+
+	require.NoError(t, users.Set("alice", string(make([]byte, 40))))
+	require.NoError(t, orders.Set(1, string(make([]byte, 40))))
+	assert.Equal(t, int64(80), pool.Used())
+
+	// "alice" is the globally oldest entry and makes room for "bob"
+	require.NoError(t, users.Set("bob", string(make([]byte, 40))))
+	assert.Equal(t, int64(80), pool.Used())
+	_, err := users.Get("alice")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+
+	// After touching order 1, "bob" is the oldest even though order 2 is
+	// inserted into the other cache
+	_, err = orders.Get(1)
+	require.NoError(t, err)
+	require.NoError(t, orders.Set(2, string(make([]byte, 30))))
+	_, err = users.Get("bob")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	assert.Equal(t, int64(70), pool.Used())
+
+	// Overwrites replace the accounted size
+	require.NoError(t, orders.Set(2, "tiny"))
+	assert.Equal(t, int64(44), pool.Used())
+
+	// Values larger than the whole budget are rejected
+	assert.Equal(t, cache.ErrCacheFull, users.Set("huge", string(make([]byte, 101))))
+
+	// Closing a cache frees its bytes
+	require.NoError(t, users.Set("carol", "0123456789"))
+	orders.Close()
+	assert.Equal(t, int64(10), pool.Used())
+	assert.Equal(t, cache.ErrCacheFull, orders.Set(3, "x"))
+	val, err := users.Get("carol")
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", val)
+}
@@ -0,0 +1,72 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// popper is implemented by every cache supporting Pop
+type popper interface {
+	cache.Cache[string, int]
+	Pop(key string) (int, error)
+}
+
+// TestPop tests removing and returning entries, and that racing pops of
+// the same key succeed exactly once
+func TestPop(t *testing.T) {
+	caches := map[string]popper{
+		"FIFO": strategies.NewFIFOCache[string, int](4),
+		"LRU":  strategies.NewLRUCache[string, int](4),
+		"LFU":  strategies.NewLFUCache[string, int](4),
+		"TTL":  strategies.NewTTLCache[string, int](4, time.Hour),
+		"ARC":  strategies.NewARCCache[string, int](4),
+	}
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, c.Set("a", 1))
+			val, err := c.Pop("a")
+			require.NoError(t, err)
+			assert.Equal(t, 1, val)
+			_, err = c.Get("a")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+
+			_, err = c.Pop("a")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+
+			require.NoError(t, c.Set("token", 7))
+			var wg sync.WaitGroup
+			errs := make([]error, 8)
+			for i := range errs {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					_, errs[i] = c.Pop("token")
+				}(i)
+			}
+			wg.Wait()
+			popped := 0
+			for _, err := range errs {
+				if err == nil {
+					popped++
+				}
+			}
+			assert.Equal(t, 1, popped)
+		})
+	}
+
+	t.Run("Expired", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Unix(0, 0))
+		c := strategies.NewTTLCache[string, int](4, time.Minute, strategies.WithClock(clock))
+		require.NoError(t, c.Set("a", 1))
+		clock.Advance(2 * time.Minute)
+		_, err := c.Pop("a")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+	})
+}
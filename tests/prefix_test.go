@@ -0,0 +1,83 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeletePrefix tests that only keys under the prefix are removed
+func TestDeletePrefix(t *testing.T) {
+	type prefixCache interface {
+		cache.Cache[string, int]
+		cache.FuncDeleter[string, int]
+	}
+	caches := map[string]prefixCache{
+		"FIFO": strategies.NewFIFOCache[string, int](10),
+		"LRU":  strategies.NewLRUCache[string, int](10),
+		"LFU":  strategies.NewLFUCache[string, int](10),
+		"TTL":  strategies.NewTTLCache[string, int](10, time.Hour),
+		"ARC":  strategies.NewARCCache[string, int](10),
+		"LRUK": strategies.NewLRUKCache[string, int](10, 2),
+	}
+	keys := []string{"user:1:profile", "user:1:settings", "user:2:profile", "user:10:profile", "order:1"}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			for i, key := range keys {
+				require.NoError(t, c.Set(key, i))
+			}
+
+			assert.Equal(t, 2, cache.DeletePrefix[int](c, "user:1:"))
+			for _, key := range keys {
+				_, err := c.Get(key)
+				if key == "user:1:profile" || key == "user:1:settings" {
+					assert.Equal(t, cache.ErrKeyNotFound, err, key)
+				} else {
+					assert.NoError(t, err, key)
+				}
+			}
+			assert.Equal(t, 0, cache.DeletePrefix[int](c, "user:1:"))
+			assert.Equal(t, 2, cache.DeletePrefix[int](c, "user:"))
+			assert.Equal(t, 1, cache.DeletePrefix[int](c, ""))
+		})
+	}
+}
+
+// TestNamespace tests namespaces sharing a cache and clearing only their own
+// keys
+func TestNamespace(t *testing.T) {
+	shared := strategies.NewLRUCache[string, int](10)
+	users := cache.NewNamespace[int](shared, "user:")
+	tenant := users.Namespace("1:")
+	orders := cache.NewNamespace[int](shared, "order:")
+	assert.Equal(t, "user:1:", tenant.Prefix())
+
+	require.NoError(t, tenant.Set("profile", 1))
+	require.NoError(t, users.Set("2:profile", 2))
+	require.NoError(t, orders.Set("1", 3))
+
+	val, err := shared.Get("user:1:profile")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	val, err = users.Get("1:profile")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	_, err = orders.Get("profile")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+
+	tenant.Clear()
+	_, err = tenant.Get("profile")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	_, err = users.Get("2:profile")
+	assert.NoError(t, err)
+
+	users.Clear()
+	require.NoError(t, orders.Delete("1"))
+	assert.Equal(t, cache.ErrKeyNotFound, orders.Delete("1"))
+	assert.Empty(t, shared.Keys())
+}
@@ -0,0 +1,65 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPriorityCache tests the priority cache implementation
+func TestPriorityCache(t *testing.T) {
+	// High priority entries survive a stream of bulk entries
+	t.Run("Priority", func(t *testing.T) {
+		c := strategies.NewPriorityCache[string, int](3)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+
+		require.NoError(t, c.SetWithPriority("config", 1, 10))
+		require.NoError(t, c.SetWithPriority("a", 2, 1))
+		require.NoError(t, c.Set("b", 3))
+		require.NoError(t, c.Set("c", 4))
+		require.NoError(t, c.Set("d", 5))
+		require.NoError(t, c.Set("e", 6))
+		assert.Equal(t, []string{"b", "c", "d"}, evicted)
+		assert.ElementsMatch(t, []string{"config", "a", "e"}, c.Keys())
+
+		// Lowering the priority of config makes it the next victim
+		require.NoError(t, c.SetWithPriority("config", 7, -1))
+		require.NoError(t, c.Set("f", 8))
+		assert.Equal(t, []string{"b", "c", "d", "config"}, evicted)
+	})
+
+	// Ties between equal priorities go to the least recently used entry
+	t.Run("Recency", func(t *testing.T) {
+		c := strategies.NewPriorityCache[string, int](2)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+
+		require.NoError(t, c.SetWithPriority("a", 1, 5))
+		require.NoError(t, c.SetWithPriority("b", 2, 5))
+		_, err := c.Get("a")
+		require.NoError(t, err)
+		require.NoError(t, c.SetWithPriority("c", 3, 5))
+		assert.Equal(t, []string{"b"}, evicted)
+
+		// Set keeps the priority of an existing key
+		require.NoError(t, c.Set("a", 4))
+		require.NoError(t, c.SetWithPriority("d", 5, 5))
+		assert.Equal(t, []string{"b", "c"}, evicted)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewPriorityCache[string, int](2)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Delete("a"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+		require.NoError(t, c.Set("b", 2))
+		c.Clear()
+		_, err := c.Get("b")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		assert.Equal(t, cache.ErrCacheFull, cache.NewPriorityCache[string, int](0).Set("a", 1))
+	})
+}
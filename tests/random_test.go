@@ -0,0 +1,59 @@
+package cache_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRandomCache tests the random replacement cache implementation
+func TestRandomCache(t *testing.T) {
+	t.Run("Basic", func(t *testing.T) {
+		c := cache.NewRandomCache[string, int](2, rand.NewSource(1))
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("a", 2))
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 2, val)
+
+		require.NoError(t, c.Delete("a"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+		assert.Equal(t, cache.ErrCacheFull, cache.NewRandomCache[string, int](0, nil).Set("a", 1))
+	})
+
+	t.Run("Deterministic", func(t *testing.T) {
+		evictions := func() []int {
+			c := strategies.NewRandomCache[int, int](8, rand.NewSource(42))
+			var evicted []int
+			c.SetEvictCallback(func(key, _ int) { evicted = append(evicted, key) })
+			for i := 0; i < 100; i++ {
+				require.NoError(t, c.Set(i, i))
+			}
+			assert.Len(t, c.Keys(), 8)
+			return evicted
+		}
+		first := evictions()
+		assert.Len(t, first, 92)
+		assert.Equal(t, first, evictions())
+	})
+
+	t.Run("Uniform", func(t *testing.T) {
+		src := rand.NewSource(7)
+		counts := make(map[int]int)
+		for trial := 0; trial < 4000; trial++ {
+			c := strategies.NewRandomCache[int, int](4, src)
+			c.SetEvictCallback(func(key, _ int) { counts[key]++ })
+			for i := 0; i < 5; i++ {
+				require.NoError(t, c.Set(i, i))
+			}
+		}
+		require.Len(t, counts, 4)
+		for key, n := range counts {
+			assert.InDelta(t, 1000, n, 150, "key %d", key)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+
+	"caching-labwork/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecorder tests that every operation is recorded without changing behavior
+func TestRecorder(t *testing.T) {
+	recorder, c := cache.NewRecorder(cache.NewLRUCache[string, int](2))
+
+	require.NoError(t, c.Set("a", 1))
+	val, err := c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	_, err = c.Get("b")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	require.NoError(t, c.Set("b", 2))
+	require.NoError(t, c.Set("c", 3)) // evicts "a"
+	_, err = c.Get("a")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+	require.NoError(t, c.Delete("b"))
+	c.Clear()
+
+	assert.Equal(t, []cache.Event[string]{
+		{Op: cache.OpSet, Key: "a", Hit: true},
+		{Op: cache.OpGet, Key: "a", Hit: true},
+		{Op: cache.OpGet, Key: "b", Hit: false},
+		{Op: cache.OpSet, Key: "b", Hit: true},
+		{Op: cache.OpSet, Key: "c", Hit: true},
+		{Op: cache.OpGet, Key: "a", Hit: false},
+		{Op: cache.OpDelete, Key: "a", Hit: false},
+		{Op: cache.OpDelete, Key: "b", Hit: true},
+		{Op: cache.OpClear, Hit: true},
+	}, recorder.Events())
+
+	// The ring buffer keeps only the most recent events
+	recorder.Reset()
+	for i := 0; i < cache.DefaultRecorderSize+3; i++ {
+		_, _ = c.Get("x")
+	}
+	require.NoError(t, c.Set("last", 1))
+	events := recorder.Events()
+	require.Len(t, events, cache.DefaultRecorderSize)
+	assert.Equal(t, cache.Event[string]{Op: cache.OpSet, Key: "last", Hit: true}, events[len(events)-1])
+
+	// Concurrent use records every operation
+	recorder.Reset()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, _ = c.Get("x")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, recorder.Events(), 800)
+}
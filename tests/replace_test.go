@@ -0,0 +1,84 @@
+package cache_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplace tests that readers never observe a partially replaced cache
+func TestReplace(t *testing.T) {
+	type replacer interface {
+		cache.Cache[string, int]
+		Replace(entries map[string]int) error
+		ToMap() map[string]int
+	}
+	caches := map[string]replacer{
+		"FIFO": strategies.NewFIFOCache[string, int](100),
+		"LRU":  strategies.NewLRUCache[string, int](100),
+		"LFU":  strategies.NewLFUCache[string, int](100),
+		"TTL":  strategies.NewTTLCache[string, int](100, time.Hour),
+		"ARC":  strategies.NewARCCache[string, int](100),
+	}
+	generation := func(n int) map[string]int {
+		entries := map[string]int{"known": n}
+		for i := 0; i < 50; i++ {
+			entries[fmt.Sprintf("%d-%d", n, i)] = n
+		}
+		return entries
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, c.Replace(generation(0)))
+
+			var stop atomic.Bool
+			var misses, reads atomic.Int64
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for !stop.Load() {
+					if _, err := c.Get("known"); err != nil {
+						misses.Add(1)
+					}
+					reads.Add(1)
+				}
+			}()
+			// Keep replacing until the reader had a fair chance to interleave
+			n := 0
+			for n < 50 || reads.Load() < 1000 {
+				n++
+				require.NoError(t, c.Replace(generation(n)))
+			}
+			stop.Store(true)
+			wg.Wait()
+
+			assert.Zero(t, misses.Load(), "reads during Replace missed the known key")
+			assert.Equal(t, generation(n), c.ToMap())
+		})
+	}
+
+	// The capacity still applies to the new entries, and a map too large for
+	// it is rejected before anything changes
+	c := strategies.NewFIFOCache[string, int](2)
+	var evicted []string
+	c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+	require.NoError(t, c.Set("old", 0))
+	assert.Equal(t, cache.ErrCacheFull, c.Replace(map[string]int{"a": 1, "b": 2, "c": 3}))
+	assert.Equal(t, map[string]int{"old": 0}, c.ToMap())
+	require.NoError(t, c.Replace(map[string]int{"a": 1, "b": 2}))
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, c.ToMap())
+	assert.Empty(t, evicted)
+
+	z := strategies.NewLRUCache[string, int](0)
+	assert.Equal(t, cache.ErrCacheFull, z.Replace(map[string]int{"a": 1}))
+	require.NoError(t, z.Replace(nil))
+}
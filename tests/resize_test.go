@@ -0,0 +1,192 @@
+package cache_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResize tests shrinking and growing every policy at runtime
+func TestResize(t *testing.T) {
+	type resizable interface {
+		cache.SizedCache[string, int]
+		Keys() []string
+		SetEvictCallback(fn func(key string, value int))
+		Resize(newCapacity int) error
+	}
+	tests := map[string]struct {
+		c       resizable
+		evicted []string
+	}{
+		"FIFO": {strategies.NewFIFOCache[string, int](4), []string{"a", "b"}},
+		"LRU":  {strategies.NewLRUCache[string, int](4), []string{"b", "c"}},
+		"LFU":  {strategies.NewLFUCache[string, int](4), []string{"b", "c"}},
+		"ARC":  {strategies.NewARCCache[string, int](4), []string{"b", "c"}},
+		"TTL":  {strategies.NewTTLCache[string, int](4, time.Hour), []string{"a", "b"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := tt.c
+			var evicted []string
+			c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+			for i, key := range []string{"a", "b", "c", "d"} {
+				require.NoError(t, c.Set(key, i))
+			}
+			_, err := c.Get("a")
+			require.NoError(t, err)
+
+			// Shrinking evicts in policy order
+			require.NoError(t, c.Resize(2))
+			assert.Equal(t, tt.evicted, evicted)
+			assert.Equal(t, 2, c.Len())
+			assert.Equal(t, 2, c.Cap())
+
+			// Growing keeps every entry and makes room for more
+			kept := c.Keys()
+			require.NoError(t, c.Resize(4))
+			require.NoError(t, c.Set("e", 5))
+			require.NoError(t, c.Set("f", 6))
+			assert.Len(t, evicted, 2)
+			assert.Subset(t, c.Keys(), kept)
+			assert.Equal(t, 4, c.Len())
+
+			require.NoError(t, c.Resize(0))
+			assert.Equal(t, 0, c.Len())
+			assert.Equal(t, cache.ErrCacheFull, c.Set("g", 7))
+		})
+	}
+
+	// Pinned entries must still fit
+	lru := strategies.NewLRUCache[string, int](4)
+	require.NoError(t, lru.SetPinned("p", 1))
+	require.NoError(t, lru.SetPinned("q", 2))
+	assert.Equal(t, cache.ErrTooManyPinned, lru.Resize(1))
+	assert.Equal(t, 4, lru.Cap())
+
+	// A frozen cache only shrinks once unfrozen
+	fifo := strategies.NewFIFOCache[string, int](3)
+	for i, key := range []string{"a", "b", "c"} {
+		require.NoError(t, fifo.Set(key, i))
+	}
+	fifo.Freeze()
+	require.NoError(t, fifo.Resize(1))
+	assert.Equal(t, 3, fifo.Len())
+	fifo.Unfreeze()
+	assert.Equal(t, []string{"c"}, fifo.Keys())
+
+	// ARC forgets the ghosts beyond the new bounds
+	arc := strategies.NewARCCache[string, int](4)
+	for i, key := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, arc.Set(key, i))
+	}
+	require.NoError(t, arc.Resize(2))
+	state := arc.State()
+	assert.Equal(t, []string{"c", "d"}, state.T1)
+	assert.Empty(t, state.B1)
+}
+
+// TestResizeOtherPolicies tests that every other policy shrinks to its new
+// capacity, reporting what it evicts, and grows back
+func TestResizeOtherPolicies(t *testing.T) {
+	type resizable interface {
+		cache.SizedCache[string, int]
+		Contains(key string) bool
+		SetEvictCallback(fn func(key string, value int))
+		Resize(newCapacity int) error
+	}
+	caches := map[string]func(capacity int) resizable{
+		"MRU": func(capacity int) resizable { return strategies.NewMRUCache[string, int](capacity) },
+		"Random": func(capacity int) resizable {
+			return strategies.NewRandomCache[string, int](capacity, rand.NewSource(1))
+		},
+		"CLOCK": func(capacity int) resizable { return strategies.NewClockCache[string, int](capacity) },
+		"SLRU": func(capacity int) resizable {
+			return strategies.NewSLRUCache[string, int](capacity/2, capacity-capacity/2)
+		},
+		"2Q":           func(capacity int) resizable { return strategies.NewTwoQueueCache[string, int](capacity) },
+		"LIRS":         func(capacity int) resizable { return strategies.NewLIRSCache[string, int](capacity) },
+		"TinyLFU":      func(capacity int) resizable { return strategies.NewTinyLFUCache[string, int](capacity) },
+		"CAR":          func(capacity int) resizable { return strategies.NewCARCache[string, int](capacity) },
+		"SIEVE":        func(capacity int) resizable { return strategies.NewSieveCache[string, int](capacity) },
+		"S3-FIFO":      func(capacity int) resizable { return strategies.NewS3FIFOCache[string, int](capacity) },
+		"GDSF":         func(capacity int) resizable { return strategies.NewGDSFCache[string, int](int64(capacity)) },
+		"Priority":     func(capacity int) resizable { return strategies.NewPriorityCache[string, int](capacity) },
+		"SecondChance": func(capacity int) resizable { return strategies.NewSecondChanceCache[string, int](capacity) },
+		"Policy": func(capacity int) resizable {
+			return strategies.NewPolicyCache[string, int](capacity, strategies.NewLRUPolicy[string]())
+		},
+		"LRU-K": func(capacity int) resizable { return strategies.NewLRUKCache[string, int](capacity, 2) },
+		"WindowedLFU": func(capacity int) resizable {
+			return strategies.NewWindowedLFUCache[string, int](capacity, time.Minute)
+		},
+		"Optimal": func(capacity int) resizable {
+			return strategies.NewOfflineOptimalCache[string, int](capacity, nil)
+		},
+	}
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	stored := func(c resizable, keys []string) []string {
+		var present []string
+		for _, key := range keys {
+			if c.Contains(key) {
+				present = append(present, key)
+			}
+		}
+		return present
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			c := newCache(len(keys))
+			var evicted []string
+			c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+			for i, key := range keys {
+				require.NoError(t, c.Set(key, i))
+			}
+			for _, key := range keys[4:] {
+				_, err := c.Get(key)
+				require.NoError(t, err)
+			}
+
+			// Shrinking evicts exactly the entries that no longer fit
+			require.NoError(t, c.Resize(3))
+			assert.Equal(t, 3, c.Len())
+			assert.Equal(t, 3, c.Cap())
+			assert.ElementsMatch(t, keys, append(stored(c, keys), evicted...))
+
+			// Growing keeps every entry and makes room for more
+			kept, dropped := stored(c, keys), len(evicted)
+			require.NoError(t, c.Resize(len(keys)))
+			assert.Equal(t, len(keys), c.Cap())
+			for i, key := range []string{"x", "y", "z", "w", "v"} {
+				require.NoError(t, c.Set(key, i))
+				// Reused, so that SLRU protects it rather than filling probation
+				_, err := c.Get(key)
+				require.NoError(t, err)
+			}
+			assert.Len(t, evicted, dropped)
+			assert.Equal(t, kept, stored(c, keys))
+			assert.Equal(t, len(keys), c.Len())
+
+			require.NoError(t, c.Resize(0))
+			assert.Equal(t, 0, c.Len())
+			assert.Equal(t, cache.ErrCacheFull, c.Set("u", 1))
+		})
+	}
+
+	// IntFIFO keeps its key space
+	ints := strategies.NewIntFIFOCache[int](4, 10)
+	for key := 0; key < 4; key++ {
+		require.NoError(t, ints.Set(key, key))
+	}
+	require.NoError(t, ints.Resize(2))
+	assert.Equal(t, 2, ints.Len())
+	assert.False(t, ints.Contains(1))
+	assert.True(t, ints.Contains(3))
+	assert.Equal(t, cache.ErrKeyOutOfRange, ints.Set(10, 10))
+}
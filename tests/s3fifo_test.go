@@ -0,0 +1,67 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"caching-labwork/cache/workload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestS3FIFOCache tests the queues of the S3-FIFO cache
+func TestS3FIFOCache(t *testing.T) {
+	t.Run("Queues", func(t *testing.T) {
+		// A small queue of 1 entry, a main queue of 3 and a ghost queue of 3
+		c := strategies.NewS3FIFOCache[string, int](4)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+		for i, key := range []string{"a", "b", "c", "d"} {
+			require.NoError(t, c.Set(key, i))
+		}
+
+		// a was read in the small queue, so it moves to the main queue and
+		// the one-hit wonder b goes
+		_, err := c.Get("a")
+		require.NoError(t, err)
+		require.NoError(t, c.Set("e", 4))
+		assert.Equal(t, []string{"b"}, evicted)
+
+		// b is remembered and goes straight to the main queue
+		require.NoError(t, c.Set("b", 1))
+		assert.Equal(t, []string{"b", "c"}, evicted)
+		assert.ElementsMatch(t, []string{"a", "b", "d", "e"}, c.Keys())
+	})
+
+	t.Run("ScanResistance", func(t *testing.T) {
+		// A skewed workload interleaved with one-off keys
+		keys := workload.Zipfian(1000, 1.1, 20000, 5)
+		hitRatio := func(c cache.Cache[int, int]) float64 {
+			hits := 0
+			for i, key := range keys {
+				if i%2 == 1 {
+					key = 1000 + i
+				}
+				if _, err := c.Get(key); err == nil {
+					hits++
+				} else {
+					require.NoError(t, c.Set(key, key))
+				}
+			}
+			return float64(hits) / float64(len(keys))
+		}
+		s3 := hitRatio(cache.NewS3FIFOCache[int, int](50))
+		lru := hitRatio(cache.NewLRUCache[int, int](50))
+		assert.Greater(t, s3, lru)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewS3FIFOCache[string, int](4)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Delete("a"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+		c.Clear()
+		assert.Equal(t, cache.ErrCacheFull, cache.NewS3FIFOCache[string, int](0).Set("a", 1))
+	})
+}
@@ -0,0 +1,51 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCacheFrom tests seeding caches within and beyond their capacity
+func TestCacheFrom(t *testing.T) {
+	constructors := map[string]func(capacity int, seed map[string]int) cache.Cache[string, int]{
+		"FIFO": cache.NewFIFOCacheFrom[string, int],
+		"LRU":  cache.NewLRUCacheFrom[string, int],
+		"LFU":  cache.NewLFUCacheFrom[string, int],
+		"ARC":  cache.NewARCCacheFrom[string, int],
+		"TTL": func(capacity int, seed map[string]int) cache.Cache[string, int] {
+			return cache.NewTTLCacheFrom(capacity, time.Hour, seed)
+		},
+	}
+	seed := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+
+	for name, newCache := range constructors {
+		t.Run(name, func(t *testing.T) {
+			// Within capacity every entry is kept
+			c := newCache(4, seed)
+			for key, want := range seed {
+				val, err := c.Get(key)
+				require.NoError(t, err)
+				assert.Equal(t, want, val)
+			}
+
+			// Beyond capacity exactly capacity seed entries remain
+			c = newCache(2, seed)
+			resident := 0
+			for key, want := range seed {
+				if val, err := c.Get(key); err == nil {
+					assert.Equal(t, want, val)
+					resident++
+				}
+			}
+			assert.Equal(t, 2, resident)
+
+			c = newCache(0, seed)
+			_, err := c.Get("a")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cache_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchSetter is implemented by every cache supporting SetMany
+type batchSetter interface {
+	SetMany(entries []strategies.Entry[int, int]) error
+	Set(key int, value int) error
+	Keys() []int
+}
+
+// TestSetManyOverflow tests both overflow policies with a batch larger than
+// the capacity
+func TestSetManyOverflow(t *testing.T) {
+	batch := make([]strategies.Entry[int, int], 5)
+	for i := range batch {
+		batch[i] = strategies.Entry[int, int]{Key: i, Value: i}
+	}
+	caches := func(opts ...strategies.Option) map[string]batchSetter {
+		return map[string]batchSetter{
+			"FIFO": strategies.NewFIFOCache[int, int](3, opts...),
+			"LRU":  strategies.NewLRUCache[int, int](3, opts...),
+			"LFU":  strategies.NewLFUCache[int, int](3, opts...),
+			"TTL":  strategies.NewTTLCache[int, int](3, time.Hour, opts...),
+			"ARC":  strategies.NewARCCache[int, int](3, opts...),
+		}
+	}
+
+	for name, c := range caches() {
+		t.Run(name+"/EvictOldest", func(t *testing.T) {
+			require.NoError(t, c.SetMany(batch))
+			keys := c.Keys()
+			sort.Ints(keys)
+			assert.Equal(t, []int{2, 3, 4}, keys)
+		})
+	}
+
+	for name, c := range caches(strategies.WithOverflowPolicy(strategies.RejectExcess)) {
+		t.Run(name+"/RejectExcess", func(t *testing.T) {
+			require.NoError(t, c.Set(100, 100))
+			assert.Equal(t, cache.ErrCacheFull, c.SetMany(batch))
+			assert.Equal(t, []int{100}, c.Keys())
+
+			// Duplicate keys count once, and a fitting batch may still
+			// evict older entries
+			require.NoError(t, c.SetMany(append(batch[:3:3], batch[0])))
+			keys := c.Keys()
+			sort.Ints(keys)
+			assert.Equal(t, []int{0, 1, 2}, keys)
+		})
+	}
+
+	t.Run("SetManyWithTTL", func(t *testing.T) {
+		c := strategies.NewTTLCache[int, int](1, time.Hour, strategies.WithOverflowPolicy(strategies.RejectExcess))
+		assert.Equal(t, cache.ErrCacheFull, c.SetManyWithTTL(map[int]int{1: 1, 2: 2}, time.Minute))
+		assert.Empty(t, c.Keys())
+		require.NoError(t, c.SetManyWithTTL(map[int]int{1: 1}, time.Minute))
+	})
+}
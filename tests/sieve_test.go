@@ -0,0 +1,46 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSieveCache tests the moving hand of the SIEVE cache
+func TestSieveCache(t *testing.T) {
+	c := strategies.NewSieveCache[string, int](3)
+	var evicted []string
+	c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+	require.NoError(t, c.Set("a", 1))
+	require.NoError(t, c.Set("b", 2))
+	require.NoError(t, c.Set("c", 3))
+
+	// The hand passes the visited a and b and evicts c, then wraps around
+	_, _ = c.Get("a")
+	_, _ = c.Get("b")
+	require.NoError(t, c.Set("d", 4))
+	assert.Equal(t, []string{"c"}, evicted)
+	require.NoError(t, c.Set("e", 5))
+	assert.Equal(t, []string{"c", "a"}, evicted)
+
+	// The hand stays where it evicted instead of restarting at the oldest
+	_, _ = c.Get("d")
+	require.NoError(t, c.Set("f", 6))
+	require.NoError(t, c.Set("g", 7))
+	assert.Equal(t, []string{"c", "a", "b", "e"}, evicted)
+	assert.ElementsMatch(t, []string{"d", "f", "g"}, c.Keys())
+
+	// Deleting the entry under the hand moves the hand on to g
+	require.NoError(t, c.Delete("f"))
+	require.NoError(t, c.Set("h", 8))
+	require.NoError(t, c.Set("i", 9))
+	assert.Equal(t, []string{"c", "a", "b", "e", "g"}, evicted)
+
+	assert.Equal(t, cache.ErrKeyNotFound, c.Delete("f"))
+	c.Clear()
+	assert.Empty(t, c.Keys())
+	assert.Equal(t, cache.ErrCacheFull, cache.NewSieveCache[string, int](0).Set("a", 1))
+}
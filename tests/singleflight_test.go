@@ -0,0 +1,152 @@
+package cache_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSingleFlightCache tests that concurrent misses share a single load
+func TestSingleFlightCache(t *testing.T) {
+	c := cache.NewSingleFlightCache(cache.NewLRUCache[string, int](10))
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(key string) (int, error) {
+		loads.Add(1)
+		<-release
+		return len(key), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			val, err := c.GetOrLoad("abc", load)
+			assert.NoError(t, err)
+			results[i] = val
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads.Load())
+	for _, val := range results {
+		assert.Equal(t, 3, val)
+	}
+	val, err := c.Get("abc")
+	require.NoError(t, err)
+	assert.Equal(t, 3, val)
+}
+
+// TestSingleFlightCacheReentrant tests that a loader asking for its own key
+// fails instead of deadlocking
+func TestSingleFlightCacheReentrant(t *testing.T) {
+	c := cache.NewSingleFlightCache(cache.NewLRUCache[string, int](10))
+
+	var inner error
+	var load func(key string) (int, error)
+	load = func(key string) (int, error) {
+		_, inner = c.GetOrLoad(key, load)
+		return 1, nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.GetOrLoad("a", load)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+		assert.Equal(t, cache.ErrReentrantLoad, inner)
+	case <-time.After(time.Second):
+		t.Fatal("reentrant GetOrLoad deadlocked")
+	}
+
+	// The key is usable again once the load finished
+	val, err := c.GetOrLoad("a", load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+}
+
+// TestSingleFlightCachePanic tests that waiters of a panicking load get an
+// error while the panic goes on in the caller running the load
+func TestSingleFlightCachePanic(t *testing.T) {
+	c := cache.NewSingleFlightCache(cache.NewLRUCache[string, int](10))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	panicked := make(chan any, 1)
+	go func() {
+		defer func() { panicked <- recover() }()
+		_, _ = c.GetOrLoad("a", func(string) (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := c.GetOrLoad("a", func(string) (int, error) {
+			return 2, nil
+		})
+		waiter <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, "boom", <-panicked)
+	err := <-waiter
+	assert.ErrorIs(t, err, cache.ErrLoaderPanicked)
+	_, err = c.Get("a")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+
+	// The key is usable again once the load finished
+	val, err := c.GetOrLoad("a", func(string) (int, error) { return 3, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 3, val)
+}
+
+// TestLoadLatency tests the load duration statistics of GetOrLoad
+func TestLoadLatency(t *testing.T) {
+	c := cache.NewSingleFlightCache(cache.NewLRUCache[int, int](100))
+	assert.Equal(t, cache.LatencyStats{}, c.LoadLatency())
+
+	// Eight fast loads and two slow ones
+	load := func(key int) (int, error) {
+		if key >= 8 {
+			time.Sleep(40 * time.Millisecond)
+		} else {
+			time.Sleep(5 * time.Millisecond)
+		}
+		return key, nil
+	}
+	for key := 0; key < 10; key++ {
+		_, err := c.GetOrLoad(key, load)
+		require.NoError(t, err)
+	}
+	// Hits don't load
+	_, err := c.GetOrLoad(0, load)
+	require.NoError(t, err)
+
+	stats := c.LoadLatency()
+	assert.Equal(t, uint64(10), stats.Count)
+	assert.GreaterOrEqual(t, stats.Min, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, stats.P50, 5*time.Millisecond)
+	assert.Less(t, stats.P50, 40*time.Millisecond)
+	assert.GreaterOrEqual(t, stats.P90, 40*time.Millisecond)
+	assert.GreaterOrEqual(t, stats.P99, stats.P90)
+	assert.GreaterOrEqual(t, stats.Max, stats.P99)
+	assert.Greater(t, stats.Mean, stats.P50)
+	assert.Less(t, stats.Mean, stats.P90)
+}
@@ -0,0 +1,56 @@
+package cache_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"caching-labwork/cache/sketch"
+	"caching-labwork/cache/workload"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCountMinSketch tests that estimates never undercount and stay close
+func TestCountMinSketch(t *testing.T) {
+	const width, depth = 1024, 4
+	s := sketch.NewCountMinSketch(width, depth)
+
+	keys := workload.Strings(workload.Zipfian(5000, 1.1, 50000, 1))
+	counts := make(map[string]uint64)
+	for _, key := range keys {
+		s.Add(key)
+		counts[key]++
+	}
+
+	bound := uint64(math.E / width * float64(len(keys)))
+	exceeded := 0
+	for key, count := range counts {
+		estimate := s.Estimate(key)
+		assert.GreaterOrEqual(t, estimate, count, key)
+		if estimate-count > bound {
+			exceeded++
+		}
+	}
+	// The bound holds with a probability of 1 - e^-depth for every key
+	assert.LessOrEqual(t, float64(exceeded), 0.05*float64(len(counts)))
+
+	// Keys never added have small estimates
+	for i := 0; i < 100; i++ {
+		assert.LessOrEqual(t, s.Estimate("absent-"+strconv.Itoa(i)), 3*bound)
+	}
+
+	s.Reset()
+	assert.Zero(t, s.Estimate(keys[0]))
+}
+
+// TestCountMinSketchHalve tests that halving ages every estimate
+func TestCountMinSketchHalve(t *testing.T) {
+	s := sketch.NewCountMinSketch(64, 4)
+	for i := 0; i < 9; i++ {
+		s.Add("a")
+	}
+	s.Add("b")
+	s.Halve()
+	assert.Equal(t, uint64(4), s.Estimate("a"))
+	assert.Zero(t, s.Estimate("b"))
+}
@@ -0,0 +1,46 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSLRUCache tests promotion, demotion and scan resistance of the SLRU
+// cache
+func TestSLRUCache(t *testing.T) {
+	c := strategies.NewSLRUCache[string, int](2, 2)
+	var evicted []string
+	c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+
+	require.NoError(t, c.Set("a", 1))
+	require.NoError(t, c.Set("b", 2))
+	_, err := c.Get("a")
+	require.NoError(t, err)
+
+	// A scan only churns the probationary segment
+	for _, key := range []string{"x", "y", "z"} {
+		require.NoError(t, c.Set(key, 0))
+	}
+	assert.Equal(t, []string{"b", "x"}, evicted)
+	val, err := c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+
+	// Promoting y and z overflows the protected segment, demoting a
+	_, _ = c.Get("y")
+	_, _ = c.Get("z")
+	assert.ElementsMatch(t, []string{"a", "y", "z"}, c.Keys())
+	require.NoError(t, c.Set("w", 0))
+	require.NoError(t, c.Set("v", 0))
+	assert.Equal(t, []string{"b", "x", "a"}, evicted)
+
+	require.NoError(t, c.Delete("y"))
+	assert.Equal(t, cache.ErrKeyNotFound, c.Delete("y"))
+	c.Clear()
+	assert.Empty(t, c.Keys())
+	assert.Equal(t, cache.ErrCacheFull, cache.NewSLRUCache[string, int](0, 2).Set("a", 1))
+}
@@ -0,0 +1,124 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotter is implemented by every cache supporting Snapshot and Restore
+type snapshotter interface {
+	cache.Cache[string, int]
+	Keys() []string
+	Snapshot() []strategies.SnapshotEntry[string, int]
+	Restore(entries []strategies.SnapshotEntry[string, int]) error
+}
+
+// TestSnapshotRestore tests that a restored cache holds the same entries and
+// keeps evicting them in the same order as the original
+func TestSnapshotRestore(t *testing.T) {
+	caches := map[string]func() snapshotter{
+		"FIFO": func() snapshotter { return strategies.NewFIFOCache[string, int](3) },
+		"LRU":  func() snapshotter { return strategies.NewLRUCache[string, int](3) },
+		"LFU":  func() snapshotter { return strategies.NewLFUCache[string, int](3) },
+		"TTL":  func() snapshotter { return strategies.NewTTLCache[string, int](3, time.Hour) },
+		"ARC":  func() snapshotter { return strategies.NewARCCache[string, int](3) },
+	}
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			src := newCache()
+			for i, key := range []string{"a", "b", "c"} {
+				require.NoError(t, src.Set(key, i))
+			}
+			_, err := src.Get("a")
+			require.NoError(t, err)
+			_, err = src.Get("a")
+			require.NoError(t, err)
+
+			dst := newCache()
+			require.NoError(t, dst.Set("stale", 9))
+			snapshot := src.Snapshot()
+			require.NoError(t, dst.Restore(snapshot))
+			assert.Equal(t, src.Keys(), dst.Keys())
+			assert.Equal(t, snapshot, dst.Snapshot())
+			_, err = dst.Get("stale")
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+
+			require.NoError(t, src.Set("d", 3))
+			require.NoError(t, dst.Set("d", 3))
+			assert.Equal(t, src.Keys(), dst.Keys())
+		})
+	}
+}
+
+// TestSnapshotMetadata tests the policy metadata captured by Snapshot
+func TestSnapshotMetadata(t *testing.T) {
+	t.Run("LFU", func(t *testing.T) {
+		c := strategies.NewLFUCache[string, int](3)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		_, err := c.Get("b")
+		require.NoError(t, err)
+		require.NoError(t, c.SetPinned("p", 0))
+
+		assert.Equal(t, []strategies.SnapshotEntry[string, int]{
+			{Key: "a", Value: 1, Frequency: 1},
+			{Key: "b", Value: 2, Frequency: 2},
+			{Key: "p", Value: 0, Pinned: true},
+		}, c.Snapshot())
+
+		// Frequencies decide the victim after a restore
+		require.NoError(t, c.Restore([]strategies.SnapshotEntry[string, int]{
+			{Key: "x", Value: 1, Frequency: 5},
+			{Key: "y", Value: 2},
+			{Key: "z", Value: 3, Frequency: 3},
+		}))
+		require.NoError(t, c.Set("w", 4))
+		assert.Equal(t, []string{"w", "z", "x"}, c.Keys())
+	})
+
+	t.Run("ARC", func(t *testing.T) {
+		c := strategies.NewARCCache[string, int](3)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		_, err := c.Get("a")
+		require.NoError(t, err)
+
+		restored := strategies.NewARCCache[string, int](3)
+		require.NoError(t, restored.Restore(c.Snapshot()))
+		assert.Equal(t, []string{"b"}, restored.State().T1)
+		assert.Equal(t, []string{"a"}, restored.State().T2)
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Unix(0, 0))
+		c := strategies.NewTTLCache[string, int](3, time.Minute, strategies.WithClock(clock))
+		require.NoError(t, c.Set("a", 1))
+		clock.Advance(30 * time.Second)
+		require.NoError(t, c.Set("b", 2))
+		snapshot := c.Snapshot()
+		assert.Equal(t, time.Unix(60, 0), snapshot[0].ExpiresAt)
+
+		// Expiries survive the restore, and expired entries are dropped
+		clock.Advance(45 * time.Second)
+		require.NoError(t, c.Restore(snapshot))
+		assert.Equal(t, []string{"b"}, c.Keys())
+		clock.Advance(15 * time.Second)
+		assert.Empty(t, c.Keys())
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		c := strategies.NewLRUCache[string, int](1)
+		require.NoError(t, c.Set("a", 1))
+		entries := []strategies.SnapshotEntry[string, int]{{Key: "x"}, {Key: "y"}}
+		assert.Equal(t, cache.ErrCacheFull, c.Restore(entries))
+		entries = []strategies.SnapshotEntry[string, int]{{Key: "x", Pinned: true}, {Key: "y", Pinned: true}}
+		assert.Equal(t, cache.ErrTooManyPinned, c.Restore(entries))
+		assert.Equal(t, []string{"a"}, c.Keys())
+	})
+}
@@ -0,0 +1,74 @@
+package cache_test
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSoftCache tests that hot entries survive garbage collection while cold
+// ones may be reclaimed
+func TestSoftCache(t *testing.T) {
+	c := cache.NewSoftCache[string, []byte](2)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, c.Set(fmt.Sprint(i), make([]byte, 1<<20)))
+	}
+	require.NoError(t, c.Set("hot1", []byte("a")))
+	require.NoError(t, c.Set("hot2", []byte("b")))
+
+	runtime.GC()
+	runtime.GC()
+
+	for key, want := range map[string]string{"hot1": "a", "hot2": "b"} {
+		val, err := c.Get(key)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(val))
+	}
+	// Cold entries are either intact or reported as misses
+	for i := 0; i < 10; i++ {
+		val, err := c.Get(fmt.Sprint(i))
+		if err != nil {
+			assert.Equal(t, cache.ErrKeyNotFound, err)
+			continue
+		}
+		assert.Len(t, val, 1<<20)
+	}
+
+	// Cold entries are found again until a collection reclaims them
+	c = cache.NewSoftCache[string, []byte](1)
+	require.NoError(t, c.Set("a", []byte("a")))
+	require.NoError(t, c.Set("b", []byte("b")))
+	if val, err := c.Get("a"); err == nil {
+		assert.Equal(t, "a", string(val))
+	}
+	require.NoError(t, c.Delete("b"))
+	c.Clear()
+	_, err := c.Get("a")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+}
+
+// TestSoftCacheReclaim tests that reclaimed cold entries leave the cache
+// without any further access
+func TestSoftCacheReclaim(t *testing.T) {
+	c := cache.NewSoftCache[int, []byte](1)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, c.Set(i, make([]byte, 1<<10)))
+	}
+	assert.LessOrEqual(t, c.Len(), 100)
+
+	// Pools drop their values over two collections, and finalizers run
+	// asynchronously afterwards
+	for i := 0; i < 100 && c.Len() > 1; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, 1, c.Len())
+	val, err := c.Get(99)
+	require.NoError(t, err)
+	assert.Len(t, val, 1<<10)
+}
@@ -0,0 +1,63 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"caching-labwork/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSpilloverCache tests spilling evicted entries to disk and reading them back
+func TestSpilloverCache(t *testing.T) {
+	dir := t.TempDir()
+	spilled := func() []string {
+		files, err := filepath.Glob(filepath.Join(dir, "*.spill"))
+		require.NoError(t, err)
+		return files
+	}
+
+	c := cache.NewSpilloverCache[string, []string](2, dir, cache.JSONCodec[[]string]{})
+	require.NoError(t, c.Set("a", []string{"alpha"}))
+	require.NoError(t, c.Set("b", []string{"beta"}))
+	assert.Empty(t, spilled())
+
+	// "a" overflows memory onto disk
+	require.NoError(t, c.Set("c", []string{"gamma"}))
+	assert.Len(t, spilled(), 1)
+
+	// Reading "a" back moves it into memory and spills "b" instead
+	val, err := c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha"}, val)
+	assert.Len(t, spilled(), 1)
+
+	val, err = c.Get("b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"beta"}, val)
+
+	// Delete removes spilled entries from disk, "c" is on disk now
+	assert.Len(t, spilled(), 1)
+	require.NoError(t, c.Delete("c"))
+	assert.Empty(t, spilled())
+	_, err = c.Get("c")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	assert.Equal(t, cache.ErrKeyNotFound, c.Delete("c"))
+
+	// Disk read errors surface from Get
+	require.NoError(t, c.Set("d", []string{"delta"}))
+	files := spilled()
+	require.Len(t, files, 1)
+	require.NoError(t, os.WriteFile(files[0], []byte("not json"), 0o600))
+	_, err = c.Get("a")
+	assert.Error(t, err)
+	assert.NotEqual(t, cache.ErrKeyNotFound, err)
+
+	// Clear removes spilled files too
+	c.Clear()
+	assert.Empty(t, spilled())
+	_, err = c.Get("d")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+}
@@ -0,0 +1,95 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTaggedCache tests invalidating entries by tag
+func TestTaggedCache(t *testing.T) {
+	c := cache.NewTaggedCache[string, int](strategies.NewLRUCache[string, int](4))
+
+	require.NoError(t, c.SetTagged("profile:42", 1, "user:42"))
+	require.NoError(t, c.SetTagged("orders:42", 2, "user:42", "orders"))
+	require.NoError(t, c.SetTagged("orders:7", 3, "user:7", "orders"))
+	require.NoError(t, c.Set("config", 4))
+
+	assert.Equal(t, 2, c.InvalidateTag("user:42"))
+	_, err := c.Get("profile:42")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	_, err = c.Get("orders:42")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+
+	val, err := c.Get("orders:7")
+	require.NoError(t, err)
+	assert.Equal(t, 3, val)
+	val, err = c.Get("config")
+	require.NoError(t, err)
+	assert.Equal(t, 4, val)
+
+	// Invalidated tags are gone, other tags still hold their live keys
+	assert.Equal(t, 0, c.InvalidateTag("user:42"))
+	assert.Equal(t, 0, c.InvalidateTag("unknown"))
+
+	// Evicted entries leave the tag index: "orders:7" is the LRU victim
+	require.NoError(t, c.SetTagged("a", 5, "letters"))
+	require.NoError(t, c.SetTagged("b", 6, "letters"))
+	require.NoError(t, c.SetTagged("c", 7, "letters"))
+	_, err = c.Get("orders:7")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	assert.Equal(t, 0, c.InvalidateTag("orders"))
+
+	// Re-setting a key replaces its tags
+	require.NoError(t, c.SetTagged("a", 8, "vowels"))
+	assert.Equal(t, 2, c.InvalidateTag("letters"))
+	assert.Equal(t, 1, c.InvalidateTag("vowels"))
+}
+
+// TestTaggedCacheJanitor tests that expirations reported by the janitor of a
+// TTL cache update the tag index under the lock of the wrapper. Run it with
+// -race to catch unsynchronized access.
+func TestTaggedCacheJanitor(t *testing.T) {
+	clock := cachetest.NewClock(time.Unix(0, 0))
+	inner := strategies.NewTTLCache[int, int](64, time.Second, strategies.WithClock(clock),
+		strategies.WithJanitor(time.Millisecond), strategies.WithEvictionEvents(1))
+	defer inner.StopJanitor()
+	c := cache.NewTaggedCache[int, int](inner)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				assert.NoError(t, c.SetTagged(g*1000+i, i, "all"))
+				clock.Advance(100 * time.Millisecond)
+			}
+		}(g)
+	}
+	wg.Wait()
+	require.NoError(t, c.SetTagged(-1, 0, "kept"))
+
+	// A key expired by the janitor and stored again keeps its new tags, no
+	// matter when the expiry reaches the wrapper. The janitor removes entries
+	// soonest expiry first, so the event of -1 is the last one.
+	clock.Advance(time.Hour)
+	deadline := time.After(time.Second)
+	for expired := false; !expired; {
+		select {
+		case event := <-inner.EvictionEvents():
+			expired = event.Key == -1
+		case <-deadline:
+			t.Fatal("janitor did not expire the entries")
+		}
+	}
+	require.NoError(t, c.SetTagged(-1, 1, "kept"))
+	assert.Equal(t, 1, c.InvalidateTag("kept"))
+	assert.Equal(t, 0, c.InvalidateTag("all"))
+}
@@ -0,0 +1,97 @@
+package cache_test
+
+import (
+	"fmt"
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"caching-labwork/cache/workload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTinyLFUCache tests the admission of the W-TinyLFU cache
+func TestTinyLFUCache(t *testing.T) {
+	t.Run("Admission", func(t *testing.T) {
+		// A window of 1 entry and a main space of 9
+		c := strategies.NewTinyLFUCache[string, int](10)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+		for i := 0; i < 9; i++ {
+			key := fmt.Sprint("k", i)
+			require.NoError(t, c.Set(key, i))
+			for j := 0; j < 2; j++ {
+				_, err := c.Get(key)
+				require.NoError(t, err)
+			}
+		}
+		require.NoError(t, c.Set("k9", 9))
+
+		// Misses count too, making x more frequent than any resident entry
+		for i := 0; i < 8; i++ {
+			_, err := c.Get("x")
+			require.Error(t, err)
+		}
+
+		// k9 leaves the window but is less frequent than the main victim
+		require.NoError(t, c.Set("x", 0))
+		assert.Equal(t, []string{"k9"}, evicted)
+
+		// x is admitted in place of the main victim
+		require.NoError(t, c.Set("y", 0))
+		assert.Equal(t, []string{"k9", "k0"}, evicted)
+		_, err := c.Get("x")
+		assert.NoError(t, err)
+	})
+
+	t.Run("ScanResistance", func(t *testing.T) {
+		c := cache.NewTinyLFUCache[string, int](100)
+		for i := 0; i < 50; i++ {
+			key := fmt.Sprint("hot", i)
+			require.NoError(t, c.Set(key, i))
+			for j := 0; j < 5; j++ {
+				_, err := c.Get(key)
+				require.NoError(t, err)
+			}
+		}
+		for i := 0; i < 1000; i++ {
+			require.NoError(t, c.Set(fmt.Sprint("scan", i), i))
+		}
+		// Keys hash deterministically, so no hot key ever loses out to a
+		// colliding scan key
+		for i := 0; i < 50; i++ {
+			_, err := c.Get(fmt.Sprint("hot", i))
+			assert.NoError(t, err, "hot%d was evicted by the scan", i)
+		}
+	})
+
+	t.Run("HitRatio", func(t *testing.T) {
+		// A skewed workload interleaved with one-off keys
+		keys := workload.Zipfian(1000, 1.1, 20000, 3)
+		hitRatio := func(c cache.Cache[int, int]) float64 {
+			hits := 0
+			for i, key := range keys {
+				if i%2 == 1 {
+					key = 1000 + i
+				}
+				if _, err := c.Get(key); err == nil {
+					hits++
+				} else {
+					require.NoError(t, c.Set(key, key))
+				}
+			}
+			return float64(hits) / float64(len(keys))
+		}
+		assert.Greater(t, hitRatio(cache.NewTinyLFUCache[int, int](50)), hitRatio(cache.NewLRUCache[int, int](50)))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewTinyLFUCache[string, int](10)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Delete("a"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+		c.Clear()
+		assert.Equal(t, cache.ErrCacheFull, cache.NewTinyLFUCache[string, int](0).Set("a", 1))
+	})
+}
@@ -0,0 +1,68 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTopKeys tests that the hottest keys are reported in access order
+func TestTopKeys(t *testing.T) {
+	type tracker interface {
+		Get(key string) (int, error)
+		Set(key string, value int) error
+		TopKeys(n int) []strategies.KeyCount[string]
+	}
+	caches := map[string]tracker{
+		"FIFO": strategies.NewFIFOCache[string, int](4, strategies.WithAccessTracking()),
+		"LRU":  strategies.NewLRUCache[string, int](4),
+		"LFU":  strategies.NewLFUCache[string, int](4),
+		"TTL":  strategies.NewTTLCache[string, int](4, time.Hour),
+		"ARC":  strategies.NewARCCache[string, int](4, strategies.WithAccessTracking()),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			// Every key is set once and read hits-1 times
+			for key, hits := range map[string]int{"a": 5, "b": 1, "c": 3, "d": 2} {
+				require.NoError(t, c.Set(key, 0))
+				for i := 1; i < hits; i++ {
+					_, err := c.Get(key)
+					require.NoError(t, err)
+				}
+			}
+			_, _ = c.Get("missing")
+
+			assert.Equal(t, []strategies.KeyCount[string]{
+				{Key: "a", Count: 5},
+				{Key: "c", Count: 3},
+			}, c.TopKeys(2))
+			assert.Len(t, c.TopKeys(10), 4)
+		})
+	}
+
+	t.Run("Eviction", func(t *testing.T) {
+		c := strategies.NewFIFOCache[string, int](2, strategies.WithAccessTracking())
+		require.NoError(t, c.Set("a", 1))
+		for i := 0; i < 5; i++ {
+			_, _ = c.Get("a")
+		}
+		require.NoError(t, c.Set("b", 2))
+		require.NoError(t, c.Set("c", 3))
+
+		// Counters of evicted keys are dropped
+		assert.ElementsMatch(t, []strategies.KeyCount[string]{
+			{Key: "b", Count: 1},
+			{Key: "c", Count: 1},
+		}, c.TopKeys(10))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		c := strategies.NewFIFOCache[string, int](2)
+		require.NoError(t, c.Set("a", 1))
+		assert.Nil(t, c.TopKeys(1))
+	})
+}
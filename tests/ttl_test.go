@@ -1,10 +1,15 @@
 package cache_test
 
 import (
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"caching-labwork/cache"
+	"caching-labwork/cache/cachetest"
+	"caching-labwork/cache/strategies"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -14,29 +19,459 @@ func TestTTLCache(t *testing.T) {
 	c := cache.NewTTLCache[string, int](3, 100*time.Millisecond)
 
 	// Test basic operations
-	err := c.Set("a", 1)
-	require.NoError(t, err)
-	err = c.Set("b", 2)
-	require.NoError(t, err)
+	t.Run("Basic", func(t *testing.T) {
+		err := c.Set("a", 1)
+		require.NoError(t, err)
+		err = c.Set("b", 2)
+		require.NoError(t, err)
 
-	val, err := c.Get("a")
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, val)
+	})
+
+	// Should not find expired entries
+	t.Run("Expiration", func(t *testing.T) {
+		// Wait for expiration
+		time.Sleep(150 * time.Millisecond)
+
+		_, err := c.Get("a")
+		assert.Error(t, err)
+
+		_, err = c.Get("b")
+		assert.Error(t, err)
+	})
+
+	// Test that new entries work after expiration
+	t.Run("SetAfterExpiration", func(t *testing.T) {
+		err := c.Set("c", 3)
+		require.NoError(t, err)
+		val, err := c.Get("c")
+		require.NoError(t, err)
+		assert.Equal(t, 3, val)
+	})
+}
+
+// TestAdaptiveTTLCache tests that hot entries outlive cold ones
+func TestAdaptiveTTLCache(t *testing.T) {
+	clock := cachetest.NewClock(time.Unix(0, 0))
+	c := cache.NewAdaptiveTTLCache[string, int](3, 40*time.Millisecond, 120*time.Millisecond,
+		strategies.WithClock(clock))
+
+	require.NoError(t, c.Set("hot", 1))
+	require.NoError(t, c.Set("cold", 2))
+
+	// Every access extends the lifetime of "hot", capped at maxTTL
+	for i := 0; i < 10; i++ {
+		_, err := c.Get("hot")
+		require.NoError(t, err)
+	}
+
+	clock.Advance(60 * time.Millisecond)
+	_, err := c.Get("cold")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	val, err := c.Get("hot")
 	require.NoError(t, err)
 	assert.Equal(t, 1, val)
 
-	// Wait for expiration
-	time.Sleep(150 * time.Millisecond)
+	// No amount of accesses keeps an entry beyond maxTTL
+	clock.Advance(150 * time.Millisecond)
+	_, err = c.Get("hot")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+}
 
-	// Should not find expired entries
+// TestTTLCacheGetSWR tests stale-while-revalidate lookups
+func TestTTLCacheGetSWR(t *testing.T) {
+	clock := cachetest.NewClock(time.Unix(0, 0))
+	c := strategies.NewTTLCache[string, int](3, 50*time.Millisecond, strategies.WithClock(clock))
+	c.SetGracePeriod(100 * time.Millisecond)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	loader := func() (int, error) {
+		loads.Add(1)
+		<-release
+		return 2, nil
+	}
+
+	// Fresh hit doesn't touch the loader
+	require.NoError(t, c.Set("a", 1))
+	val, stale, err := c.GetSWR("a", loader)
+	require.NoError(t, err)
+	assert.False(t, stale)
+	assert.Equal(t, 1, val)
+
+	// Within the grace window the stale value is served while one refresh runs
+	clock.Advance(70 * time.Millisecond)
 	_, err = c.Get("a")
-	assert.Error(t, err)
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	for i := 0; i < 3; i++ {
+		val, stale, err = c.GetSWR("a", loader)
+		require.NoError(t, err)
+		assert.True(t, stale)
+		assert.Equal(t, 1, val)
+	}
+	close(release)
+	assert.Eventually(t, func() bool {
+		val, err := c.Get("a")
+		return err == nil && val == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(1), loads.Load())
 
-	_, err = c.Get("b")
-	assert.Error(t, err)
+	// A reload finishing after the key was deleted doesn't bring it back
+	require.NoError(t, c.Set("b", 1))
+	clock.Advance(70 * time.Millisecond)
+	entered, resume := make(chan struct{}), make(chan struct{})
+	_, stale, err = c.GetSWR("b", func() (int, error) {
+		close(entered)
+		<-resume
+		return 2, nil
+	})
+	require.NoError(t, err)
+	assert.True(t, stale)
+	<-entered
+	assert.Equal(t, cache.ErrKeyNotFound, c.Delete("b"))
+	close(resume)
+	assert.Never(t, func() bool { return c.Contains("b") }, 50*time.Millisecond, 5*time.Millisecond)
 
-	// Test that new entries work after expiration
-	err = c.Set("c", 3)
+	// Past the grace window the loader runs synchronously
+	clock.Advance(200 * time.Millisecond)
+	val, stale, err = c.GetSWR("a", func() (int, error) { return 3, nil })
 	require.NoError(t, err)
-	val, err = c.Get("c")
+	assert.False(t, stale)
+	assert.Equal(t, 3, val)
+	val, err = c.Get("a")
 	require.NoError(t, err)
 	assert.Equal(t, 3, val)
-} 
\ No newline at end of file
+
+	// Loader errors of synchronous loads are returned
+	loadErr := errors.New("backend down")
+	_, _, err = c.GetSWR("missing", func() (int, error) { return 0, loadErr })
+	assert.Equal(t, loadErr, err)
+}
+
+// TestTTLCacheGetWithState tests telling expired entries from missing ones
+func TestTTLCacheGetWithState(t *testing.T) {
+	clock := cachetest.NewClock(time.Unix(0, 0))
+	c := strategies.NewTTLCache[string, int](3, 50*time.Millisecond, strategies.WithClock(clock))
+
+	require.NoError(t, c.Set("a", 1))
+	val, state, err := c.GetWithState("a")
+	require.NoError(t, err)
+	assert.Equal(t, strategies.StateHit, state)
+	assert.Equal(t, 1, val)
+
+	val, state, err = c.GetWithState("never")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	assert.Equal(t, strategies.StateMissing, state)
+	assert.Zero(t, val)
+
+	// An expired entry reports its stale value once and is removed
+	clock.Advance(70 * time.Millisecond)
+	val, state, err = c.GetWithState("a")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	assert.Equal(t, strategies.StateExpired, state)
+	assert.Equal(t, 1, val)
+
+	_, state, err = c.GetWithState("a")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+	assert.Equal(t, strategies.StateMissing, state)
+	assert.Equal(t, "missing", state.String())
+}
+
+// TestTTLCacheSetManyWithTTL tests that a batch shares a single expiry
+func TestTTLCacheSetManyWithTTL(t *testing.T) {
+	clock := cachetest.NewClock(time.Unix(0, 0))
+	c := strategies.NewTTLCache[string, int](5, time.Hour, strategies.WithClock(clock))
+	require.NoError(t, c.Set("default", 0))
+
+	entries := map[string]int{"a": 1, "b": 2, "c": 3}
+	require.NoError(t, c.SetManyWithTTL(entries, 50*time.Millisecond))
+
+	expiry, err := c.Expiry("a")
+	require.NoError(t, err)
+	for key := range entries {
+		other, err := c.Expiry(key)
+		require.NoError(t, err)
+		assert.Equal(t, expiry, other)
+	}
+
+	// The batch expires together while entries with the default TTL remain
+	clock.Advance(70 * time.Millisecond)
+	for key := range entries {
+		_, err := c.Get(key)
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+	}
+	val, err := c.Get("default")
+	require.NoError(t, err)
+	assert.Equal(t, 0, val)
+}
+
+// TestFIFOTTLCache tests that FIFO overflow and expiry both bound the cache
+func TestFIFOTTLCache(t *testing.T) {
+	t.Run("Expiration", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Unix(0, 0))
+		c := cache.NewFIFOTTLCache[string, int](3, 50*time.Millisecond, strategies.WithClock(clock))
+		require.NoError(t, c.Set("a", 1))
+		clock.Advance(30 * time.Millisecond)
+		require.NoError(t, c.Set("b", 2))
+
+		// Overwriting keeps the original expiry
+		require.NoError(t, c.Set("a", 10))
+		clock.Advance(30 * time.Millisecond)
+		_, err := c.Get("a")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		val, err := c.Get("b")
+		require.NoError(t, err)
+		assert.Equal(t, 2, val)
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		c := cache.NewFIFOTTLCache[string, int](2, time.Hour)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		_, err := c.Get("a")
+		require.NoError(t, err)
+
+		// Neither access nor overwrite moves a in the queue
+		require.NoError(t, c.Set("a", 10))
+		require.NoError(t, c.Set("c", 3))
+		_, err = c.Get("a")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		for key, want := range map[string]int{"b": 2, "c": 3} {
+			val, err := c.Get(key)
+			require.NoError(t, err)
+			assert.Equal(t, want, val)
+		}
+	})
+
+	t.Run("SetAfterExpiry", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Unix(0, 0))
+		c := strategies.NewFIFOTTLCache[string, int](3, time.Minute, strategies.WithClock(clock))
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Set("b", 2))
+		clock.Advance(2 * time.Minute)
+
+		// No janitor removed the expired entries, yet new values replace them
+		// with a fresh expiry
+		require.NoError(t, c.Set("a", 10))
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 10, val)
+		require.NoError(t, c.Add("b", 20))
+		val, err = c.Get("b")
+		require.NoError(t, err)
+		assert.Equal(t, 20, val)
+
+		clock.Advance(time.Minute)
+		_, err = c.Get("a")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+	})
+
+	t.Run("Janitor", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Unix(0, 0))
+		c := strategies.NewFIFOTTLCache[string, int](3, time.Minute, strategies.WithClock(clock),
+			strategies.WithJanitor(5*time.Millisecond), strategies.WithEvictionEvents(4))
+		defer c.StopJanitor()
+		require.NoError(t, c.Set("a", 1))
+		clock.Advance(2 * time.Minute)
+
+		// The entry is removed without any further access
+		select {
+		case event := <-c.EvictionEvents():
+			assert.Equal(t, "a", event.Key)
+			assert.Equal(t, strategies.ReasonExpired, event.Reason)
+		case <-time.After(time.Second):
+			t.Fatal("janitor did not remove the expired entry")
+		}
+	})
+}
+
+// TestTTLCacheDrainExpired tests that only expired entries are drained
+func TestTTLCacheDrainExpired(t *testing.T) {
+	clock := cachetest.NewClock(time.Now())
+	c := strategies.NewTTLCache[string, int](10, time.Hour, strategies.WithClock(clock))
+	var expired []string
+	c.SetEvictCallback(func(key string, _ int) { expired = append(expired, key) })
+
+	require.NoError(t, c.SetManyWithTTL(map[string]int{"a": 1, "b": 2}, 10*time.Millisecond))
+	require.NoError(t, c.SetManyWithTTL(map[string]int{"c": 3}, 20*time.Millisecond))
+	require.NoError(t, c.Set("d", 4))
+	clock.Advance(15 * time.Millisecond)
+
+	drained := c.DrainExpired()
+	assert.ElementsMatch(t, []strategies.Entry[string, int]{{Key: "a", Value: 1}, {Key: "b", Value: 2}}, drained)
+	assert.ElementsMatch(t, []string{"a", "b"}, expired)
+	assert.Equal(t, map[string]int{"c": 3, "d": 4}, c.ToMap())
+
+	clock.Advance(5 * time.Millisecond)
+	assert.Equal(t, []strategies.Entry[string, int]{{Key: "c", Value: 3}}, c.DrainExpired())
+	assert.Empty(t, c.DrainExpired())
+	assert.Equal(t, map[string]int{"d": 4}, c.ToMap())
+}
+
+// TestTTLCacheWithClock tests expiry driven by a fake clock, without sleeping
+func TestTTLCacheWithClock(t *testing.T) {
+	clock := cachetest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := strategies.NewTTLCache[string, int](10, time.Minute, strategies.WithClock(clock))
+	require.NoError(t, c.Set("a", 1))
+
+	clock.Advance(59 * time.Second)
+	val, err := c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	expiry, err := c.Expiry("a")
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(time.Second), expiry)
+
+	clock.Advance(time.Second)
+	_, err = c.Get("a")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+
+	// The janitor judges expiry by the same clock
+	c = strategies.NewTTLCache[string, int](10, time.Minute,
+		strategies.WithClock(clock), strategies.WithJanitor(time.Millisecond), strategies.WithEvictionEvents(1))
+	defer c.StopJanitor()
+	require.NoError(t, c.Set("b", 2))
+	clock.Advance(time.Hour)
+	select {
+	case event := <-c.EvictionEvents():
+		assert.Equal(t, "b", event.Key)
+	case <-time.After(time.Second):
+		t.Fatal("janitor ignored the clock")
+	}
+}
+
+// TestTTLCacheExpiredBehindLive tests that a full cache reclaims an entry
+// with a short custom TTL even when a live entry was refreshed before it
+func TestTTLCacheExpiredBehindLive(t *testing.T) {
+	clock := cachetest.NewClock(time.Unix(0, 0))
+	c := strategies.NewTTLCache[string, int](2, time.Hour, strategies.WithClock(clock))
+	var evicted []string
+	c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+
+	require.NoError(t, c.Set("live", 1))
+	require.NoError(t, c.SetManyWithTTL(map[string]int{"short": 2}, time.Minute))
+
+	clock.Advance(2 * time.Minute)
+	require.NoError(t, c.Set("new", 3))
+	assert.Equal(t, []string{"short"}, evicted)
+	assert.Equal(t, map[string]int{"live": 1, "new": 3}, c.ToMap())
+}
+
+// TestTTLCacheLock tests that a lock has a single holder until it is
+// released or expires
+func TestTTLCacheLock(t *testing.T) {
+	clock := cachetest.NewClock(time.Now())
+	c := strategies.NewTTLCache[string, struct{}](10, time.Hour, strategies.WithClock(clock))
+
+	var holders, maxHolders, acquired atomic.Int32
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if !c.Lock("job", time.Minute) {
+					continue
+				}
+				acquired.Add(1)
+				n := holders.Add(1)
+				for {
+					m := maxHolders.Load()
+					if n <= m || maxHolders.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				holders.Add(-1)
+				assert.NoError(t, c.Unlock("job"))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), maxHolders.Load())
+	assert.Positive(t, acquired.Load())
+
+	// A lock that is never released expires
+	require.True(t, c.Lock("job", time.Minute))
+	assert.False(t, c.Lock("job", time.Minute))
+	clock.Advance(time.Minute)
+	assert.True(t, c.Lock("job", time.Minute))
+	require.NoError(t, c.Unlock("job"))
+	assert.Equal(t, cache.ErrKeyNotFound, c.Unlock("job"))
+}
+
+// TestTTLCacheRefreshAhead tests that read entries are reloaded just before
+// they expire and that unread ones are left to expire
+func TestTTLCacheRefreshAhead(t *testing.T) {
+	t.Run("Janitor", func(t *testing.T) {
+		start := time.Now()
+		clock := cachetest.NewClock(start)
+		c := strategies.NewTTLCache[string, int](10, 10*time.Second,
+			strategies.WithClock(clock), strategies.WithJanitor(time.Millisecond))
+		defer c.StopJanitor()
+
+		var loads atomic.Int32
+		require.NoError(t, c.Set("a", 0))
+		c.RefreshAhead("a", func() (int, error) { return int(loads.Add(1)), nil }, 2*time.Second)
+
+		// Not due yet
+		clock.Advance(7 * time.Second)
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 0, val)
+		assert.Never(t, func() bool { return loads.Load() > 0 }, 20*time.Millisecond, time.Millisecond)
+
+		// Within the lead time the janitor reloads the entry and restarts its lifetime
+		clock.Advance(1500 * time.Millisecond)
+		assert.Eventually(t, func() bool {
+			val, err := c.Get("a")
+			return err == nil && val == 1
+		}, time.Second, time.Millisecond)
+		expiry, err := c.Expiry("a")
+		require.NoError(t, err)
+		assert.Equal(t, start.Add(18500*time.Millisecond), expiry)
+
+		// Read since the last refresh, so refreshed again
+		clock.Advance(8 * time.Second)
+		assert.Eventually(t, func() bool {
+			expiry, err := c.Expiry("a")
+			return err == nil && expiry.Equal(start.Add(26500*time.Millisecond))
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, int32(2), loads.Load())
+
+		// Unread since, so left to expire
+		clock.Advance(8 * time.Second)
+		assert.Never(t, func() bool { return loads.Load() > 2 }, 20*time.Millisecond, time.Millisecond)
+		clock.Advance(2 * time.Second)
+		_, err = c.Get("a")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		clock := cachetest.NewClock(time.Now())
+		c := strategies.NewTTLCache[string, int](10, 10*time.Second, strategies.WithClock(clock))
+
+		var loads atomic.Int32
+		require.NoError(t, c.Set("a", 0))
+		c.RefreshAhead("a", func() (int, error) { return int(loads.Add(1)), nil }, 2*time.Second)
+
+		// A hit within the lead time returns the current value and reloads it
+		clock.Advance(9 * time.Second)
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 0, val)
+		assert.Eventually(t, func() bool {
+			val, err := c.Get("a")
+			return err == nil && val == 1
+		}, time.Second, time.Millisecond)
+
+		// A deleted key is no longer refreshed
+		require.NoError(t, c.Delete("a"))
+		require.NoError(t, c.Set("a", 0))
+		clock.Advance(9 * time.Second)
+		_, err = c.Get("a")
+		require.NoError(t, err)
+		assert.Never(t, func() bool { return loads.Load() > 1 }, 20*time.Millisecond, time.Millisecond)
+	})
+}
@@ -0,0 +1,64 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTwoQueueCache tests the queues of the 2Q cache
+func TestTwoQueueCache(t *testing.T) {
+	t.Run("ScanResistance", func(t *testing.T) {
+		// A1in targets 1 entry and A1out remembers 2 keys
+		c := strategies.NewTwoQueueCache[string, int](4)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+		for i, key := range []string{"a", "b", "c", "d", "e"} {
+			require.NoError(t, c.Set(key, i))
+		}
+		assert.Equal(t, []string{"a"}, evicted)
+
+		// a is remembered in A1out, so setting it again makes it hot
+		_, err := c.Get("a")
+		assert.Equal(t, cache.ErrKeyNotFound, err)
+		require.NoError(t, c.Set("a", 0))
+		for _, key := range []string{"f", "g", "h"} {
+			require.NoError(t, c.Set(key, 0))
+		}
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, evicted)
+		val, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 0, val)
+		assert.ElementsMatch(t, []string{"a", "f", "g", "h"}, c.Keys())
+	})
+
+	t.Run("HotEviction", func(t *testing.T) {
+		c := strategies.NewTwoQueueCacheWithRatios[string, int](2, 0.5, 1)
+		var evicted []string
+		c.SetEvictCallback(func(key string, _ int) { evicted = append(evicted, key) })
+		for _, key := range []string{"a", "b", "c", "a", "b"} {
+			require.NoError(t, c.Set(key, 0))
+		}
+
+		// With A1in at its target size the least recently used hot entry
+		// goes, and is not remembered
+		assert.Equal(t, []string{"a", "b", "a"}, evicted)
+		assert.ElementsMatch(t, []string{"b", "c"}, c.Keys())
+
+		// Forgotten, a comes back as a new entry
+		require.NoError(t, c.Set("a", 0))
+		assert.ElementsMatch(t, []string{"a", "c"}, c.Keys())
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewTwoQueueCache[string, int](4)
+		require.NoError(t, c.Set("a", 1))
+		require.NoError(t, c.Delete("a"))
+		assert.Equal(t, cache.ErrKeyNotFound, c.Delete("a"))
+		c.Clear()
+		assert.Equal(t, cache.ErrCacheFull, cache.NewTwoQueueCache[string, int](0).Set("a", 1))
+	})
+}
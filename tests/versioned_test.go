@@ -0,0 +1,84 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+
+	"caching-labwork/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVersionedCache tests versions of sets, conflicts and absent keys
+func TestVersionedCache(t *testing.T) {
+	c, err := cache.NewVersionedCache[string, int]("lru", 2)
+	require.NoError(t, err)
+
+	// 0 means absent
+	_, err = c.SetVersioned("a", 1, 5)
+	assert.Equal(t, cache.ErrVersionConflict, err)
+	v1, err := c.SetVersioned("a", 1, 0)
+	require.NoError(t, err)
+	_, err = c.SetVersioned("a", 1, 0)
+	assert.Equal(t, cache.ErrVersionConflict, err)
+
+	val, version, err := c.GetVersioned("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	assert.Equal(t, v1, version)
+
+	v2, err := c.SetVersioned("a", 2, v1)
+	require.NoError(t, err)
+	assert.Greater(t, v2, v1)
+	_, err = c.SetVersioned("a", 3, v1)
+	assert.Equal(t, cache.ErrVersionConflict, err)
+
+	// A plain Set bumps the version too
+	require.NoError(t, c.Set("a", 4))
+	_, v3, err := c.GetVersioned("a")
+	require.NoError(t, err)
+	assert.Greater(t, v3, v2)
+
+	// Versions are not reused after a delete
+	require.NoError(t, c.Delete("a"))
+	v4, err := c.SetVersioned("a", 5, 0)
+	require.NoError(t, err)
+	assert.Greater(t, v4, v3)
+
+	_, _, err = c.GetVersioned("missing")
+	assert.Equal(t, cache.ErrKeyNotFound, err)
+}
+
+// TestVersionedCacheConcurrentWriters tests that of two writers holding the
+// same version only one wins
+func TestVersionedCacheConcurrentWriters(t *testing.T) {
+	c, err := cache.NewVersionedCache[string, int]("fifo", 10)
+	require.NoError(t, err)
+	require.NoError(t, c.Set("counter", 0))
+
+	for round := 0; round < 100; round++ {
+		value, version, err := c.GetVersioned("counter")
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = c.SetVersioned("counter", value+1, version)
+			}(i)
+		}
+		wg.Wait()
+
+		if errs[0] == nil {
+			assert.Equal(t, cache.ErrVersionConflict, errs[1])
+		} else {
+			assert.Equal(t, cache.ErrVersionConflict, errs[0])
+			assert.NoError(t, errs[1])
+		}
+	}
+	value, _, err := c.GetVersioned("counter")
+	require.NoError(t, err)
+	assert.Equal(t, 100, value)
+}
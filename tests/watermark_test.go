@@ -0,0 +1,62 @@
+package cache_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/strategies"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWatermark tests that utilization crossings fire once each
+func TestWatermark(t *testing.T) {
+	type watermarked interface {
+		cache.Cache[string, int]
+		SetWatermark(highPct, lowPct float64, onHigh, onLow func())
+		Keys() []string
+	}
+	caches := map[string]watermarked{
+		"FIFO": strategies.NewFIFOCache[string, int](10),
+		"LRU":  strategies.NewLRUCache[string, int](10),
+		"LFU":  strategies.NewLFUCache[string, int](10),
+		"TTL":  strategies.NewTTLCache[string, int](10, time.Hour),
+		"ARC":  strategies.NewARCCache[string, int](10),
+		"LRUK": strategies.NewLRUKCache[string, int](10, 2),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			var highs, lows []int
+			// The callbacks run unlocked and may use the cache
+			c.SetWatermark(80, 50,
+				func() { highs = append(highs, len(c.Keys())) },
+				func() { lows = append(lows, len(c.Keys())) })
+
+			for i := 0; i < 20; i++ {
+				require.NoError(t, c.Set(fmt.Sprint(i), i))
+			}
+			assert.Equal(t, []int{8}, highs)
+			assert.Empty(t, lows)
+
+			// Dropping to exactly 50% is not below the low watermark
+			keys := c.Keys()
+			for _, key := range keys[:5] {
+				require.NoError(t, c.Delete(key))
+			}
+			assert.Empty(t, lows)
+			for _, key := range keys[5:] {
+				require.NoError(t, c.Delete(key))
+			}
+			assert.Equal(t, []int{4}, lows)
+
+			// Refilling fires onHigh again
+			for i := 0; i < 10; i++ {
+				require.NoError(t, c.Set(fmt.Sprint(i), i))
+			}
+			assert.Equal(t, []int{8, 8}, highs)
+		})
+	}
+}
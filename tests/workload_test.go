@@ -0,0 +1,30 @@
+package cache_test
+
+import (
+	"testing"
+
+	"caching-labwork/cache/workload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWorkloadGenerators tests the synthetic key sequence generators
+func TestWorkloadGenerators(t *testing.T) {
+	keys := workload.Zipfian(100, 1.2, 10000, 42)
+	require.Len(t, keys, 10000)
+	assert.Equal(t, keys, workload.Zipfian(100, 1.2, 10000, 42))
+
+	counts := make([]int, 100)
+	for _, key := range keys {
+		require.True(t, key >= 0 && key < 100)
+		counts[key]++
+	}
+	// The most popular key dominates the tail of the distribution
+	assert.Greater(t, counts[0], 20*counts[99])
+	assert.Greater(t, counts[0], counts[1])
+	assert.Greater(t, counts[1], counts[10])
+
+	assert.Equal(t, []int{0, 1, 2, 0, 1, 2, 0}, workload.Sequential(3, 7))
+	assert.Equal(t, []string{"0", "1", "2"}, workload.Strings(workload.Sequential(3, 3)))
+	assert.Empty(t, workload.Zipfian(0, 1, 10, 1))
+}
@@ -0,0 +1,61 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"caching-labwork/cache"
+	"caching-labwork/cache/bench"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zeroPolicies returns a constructor for every policy storing V values
+func zeroPolicies[V any]() map[string]func(capacity int) cache.Cache[string, V] {
+	return map[string]func(capacity int) cache.Cache[string, V]{
+		"FIFO": cache.NewFIFOCache[string, V],
+		"LRU":  cache.NewLRUCache[string, V],
+		"LFU":  cache.NewLFUCache[string, V],
+		"ARC":  cache.NewARCCache[string, V],
+		"TTL": func(capacity int) cache.Cache[string, V] {
+			return cache.NewTTLCache[string, V](capacity, time.Hour)
+		},
+	}
+}
+
+// testZeroValue checks that a stored zero value is a hit taking up a slot
+func testZeroValue[V any](t *testing.T, other V) {
+	var zero V
+	for name, newCache := range zeroPolicies[V]() {
+		t.Run(name, func(t *testing.T) {
+			c := newCache(2)
+			require.NoError(t, c.Set("zero", zero))
+			val, err := c.Get("zero")
+			require.NoError(t, err)
+			assert.Equal(t, zero, val)
+
+			// The zero entry counts toward the capacity
+			require.NoError(t, c.Set("a", other))
+			require.NoError(t, c.Set("b", other))
+			resident := 0
+			for _, key := range []string{"zero", "a", "b"} {
+				if _, err := c.Get(key); err == nil {
+					resident++
+				}
+			}
+			assert.Equal(t, 2, resident)
+		})
+	}
+}
+
+// TestZeroValues tests that zero values are stored like any other value
+func TestZeroValues(t *testing.T) {
+	t.Run("Int", func(t *testing.T) { testZeroValue(t, 1) })
+	t.Run("String", func(t *testing.T) { testZeroValue(t, "x") })
+	t.Run("Pointer", func(t *testing.T) { testZeroValue(t, new(int)) })
+
+	// RunTrace stores the trace position on a miss, so a holds 0 and its
+	// later lookups must count as hits
+	stats := bench.RunTrace(cache.NewLRUCache[string, int](2), []string{"a", "a", "a"})
+	assert.Equal(t, bench.Stats{Hits: 2, Misses: 1}, stats)
+}